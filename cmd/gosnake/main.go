@@ -22,9 +22,10 @@
 // 										| (has) -> snake
 // 	 									| (has) -> candy
 // 		| (var) ->	uimanager
-// 						| (has) -> gocui
+// 						| (has) -> gocui, or ANSI escape sequences
 //
 // uimanager encapsulates functions from Gocui https://github.com/jroimartin/gocui
+// or draws with ANSI escape sequences (-backend ansi)
 //
 // The main package controls the gamestate, creates the views layouts
 // and updates them to reflect the state via uimanager
@@ -33,9 +34,13 @@
 //
 // The rounds are called in a loop controlled by tickers at intervals
 // The errors from the routines are channeled back to the main function
+// After each round, a message flashes while the region the head can reach is smaller than the snake (cf gameboard TrapRegion)
+// Once the game sat idle for the attract timeout, the autopilot plays demo games in the same game engine
+// while "PRESS SPACE" scrolls in the message view, any key stops the demo and draws the real game again
 //
 // In order to manage the keys pressed, the bindings are all affected to the same eventHandler which selects the appropriate action
 // Since the eventHandler will only receive the key pressed as parameter, a closure is used to allow access to the main parameters
+// The closure translates the key into an action with the binding table (cf keybindings package)
 //
 // Most functions and methods start with a defer common.ErrorWrapper
 // Which handles unexpected panics and wraps any error with the function/method name
@@ -45,57 +50,289 @@
 // In the case of panics the content is checked, the panic can be an error or a string (cf common.ErrorWrapper test)
 // The errors and unexpected panics can be controlled and logged if needed
 // For example, if an error/panic occurs in the routines gameEngine or gameOverAnim, a red alert is displayed without terminating the program.
+// With the -log option, errors are also written to a log file as JSON entries holding the chain split into frames,
+// and for panics, the recovered value and the stack captured at recovery (cf common.PanicError).
+//
+// The controls, the timing, the board sizes and the glyphs are read from a YAML configuration file (cf config package)
+// "gosnake config init" writes the default configuration
+//
+// With -spectate, teammates watch the game with nc or telnet, the screen is mirrored to their terminals (cf spectator package)
+//
+// With -record, the frames are also written to an asciicast file which asciinema plays (cf asciicast package)
+//
+// gamestate keeps the sprites changed by each round, once over the game can be saved as an animated GIF (cf gifexport package)
+// and the board can be saved at any time to PNG, SVG or text snapshots (cf gameboard.WriteSnapshot)
+//
+// With -bot, a program written in any language steers the snake: it reads the game as a line of JSON per round
+// and replies with a direction, which goes the way of the keys (cf bot package). "gosnake bot" plays it without user interface
+// With -battlesnake, a Battlesnake server plays the same way, GoSnake calls its /start, /move and /end (cf battlesnake package)
+// Otherwise the O key switches the autopilot on and off, it plays a strategy as a bot would (cf autopilot package)
+// "gosnake train" teaches the autopilot with Q-learning over games without user interface, -autopilot plays the table (cf qlearning package)
+// "gosnake evolve" tunes the weights of a heuristic bot with a genetic algorithm, -autopilot plays the profile (cf evolve package)
+// The mcts strategy searches the moves ahead on copies of the game state, each copy draws its candies from its own seed (cf gamestate.Cloner)
+//
+// "gosnake tournament" plays the strategies (cf strategy package) on seeded boards, in parallel and without user interface,
+// the game states are driven as the keys do and the scores are compared in a report (cf tournament package)
+//
+// "gosnake web" serves the game to a browser, the page is drawn on a canvas and the game runs on the server (cf webui package)
+// The rounds and the moves, the pause and the start keys are the same in the terminal and the browser (cf engine package)
 
 package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"gosnake/pkg/asciicast"
+	"gosnake/pkg/autopilot"
+	"gosnake/pkg/battlesnake"
+	"gosnake/pkg/bot"
 	"gosnake/pkg/common"
+	"gosnake/pkg/config"
+	"gosnake/pkg/engine"
+	"gosnake/pkg/errorhistory"
+	"gosnake/pkg/errorlog"
+	"gosnake/pkg/evolve"
+	"gosnake/pkg/gameboard"
 	"gosnake/pkg/gamestate"
+	"gosnake/pkg/gifexport"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/qlearning"
+	"gosnake/pkg/spectator"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/supervisor"
+	"gosnake/pkg/theme"
+	"gosnake/pkg/tournament"
 	"gosnake/pkg/uimanager"
+	"gosnake/pkg/webui"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-const (
-	defaultBoardSize = 40
-	sizeIncrement    = 10
-	refreshInterval  = 100 * time.Millisecond // Defines the animations refresh rate
+// options holds the settings given on the command line
+type options struct {
+	configFile     string
+	logFile        string
+	logLevel       errorlog.Level
+	retries        int
+	faultInjection bool
+	backend        string
+	spectate       string // The address the spectators connect to
+	maxSpectators  int
+	record         string // The asciicast file recording the session
+	bot            string // The command of the bot steering the snake
+	battlesnake    string // The URL of the Battlesnake server steering the snake
+	botTimeout     time.Duration
+	botForfeit     bool
+	autopilot      string // The strategy, the bot profile or the Q-table file played by the autopilot, greedy when empty
+}
+
+// trapWarning is the trap shown to the player, the cells shaded are drawn again once the snake is out
+type trapWarning struct {
+	trapped bool
+	shaded  []common.Position
+}
+
+// attractMode is the demo game the autopilot plays while the game sits idle, cf watchIdle
+type attractMode struct {
+	mutex    sync.Mutex
+	pilot    bot.Boter            // Plays the demo games, it is always switched on
+	idleFrom time.Time            // The last key press, or the last round where the game wasn't idle
+	demo     gamestate.GameStater // The demo game played, nil when there is none
+	stop     chan struct{}        // Closed to stop the engine of the demo game, cf stopDemo
+	stopped  bool                 // A key stopped the demos, the real game is drawn again once the demo is over
+}
+
+// demoGame is the game state of a demo game, its trap warnings would garble the marquee
+type demoGame struct {
+	gamestate.GameStater
+}
+
+// TrapRegion never finds the snake of a demo game trapped
+func (demoGame) TrapRegion() (region []common.Position, trapped bool, err error) {
+	return nil, false, nil
+}
+
+// Defines custom errors
+var (
+	errInvalidRetries    = errors.New("the number of retries can't be negative")
+	errInvalidSpectators = errors.New("the number of spectators must be positive")
+	errUnknownCommand    = errors.New("unknown command")
+	errInvalidBoardSizes = errors.New("the board sizes must be numbers separated by commas")
+	errInvalidBotGames   = errors.New("invalid bot games")
+	errTwoBots           = errors.New("a bot command and a Battlesnake server can't play together")
+	errBotAutopilot      = errors.New("the autopilot only plays when no bot does")
 )
 
 func main() {
 	var (
-		gameState     = gamestate.New()
-		userInterface = uimanager.New()
-		boardSize     = common.Size{
-			Width:  defaultBoardSize,
-			Height: defaultBoardSize,
-		}
-		scrollOver = true
-		err        error // main function errors
-		errChn     error // errors channeled from routines are written in errChn
+		gameState      = gamestate.New()
+		userInterface  uimanager.UIManagerer
+		boardSize      common.Size
+		cfg            config.Config
+		errLog         = errorlog.New()
+		errHistory     = errorhistory.New()
+		gameSupervisor supervisor.Supervisorer
+		mirror         uimanager.Mirrorer
+		spectators     spectator.Spectatorer
+		recorder       = asciicast.New()
+		aBot           bot.Boter // Steers the snake in place of the keys when set
+		opts           options
+		scrollOver     = true
+		attract        = attractMode{idleFrom: time.Now()}
+		err            error // main function errors
+		errChn         error // errors channeled from routines are written in errChn
 	)
 
+	// The log file is closed after the last report
+	defer closeErrorLog(errLog)
+
 	// When terminating if any, errChn or err are displayed
-	defer reportError(common.GetCurrentFuncName(), &err, &errChn)
+	defer reportError(common.GetCurrentFuncName(), errLog, &err, &errChn)
+
+	// "gosnake config ..." manages the configuration file instead of playing
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err = configCommand(os.Args[2:]); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// "gosnake web" serves the game to the browsers instead of playing in the terminal
+	if len(os.Args) > 1 && os.Args[1] == "web" {
+		if err = webCommand(os.Args[2:], errLog); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// "gosnake bot" plays a bot without user interface
+	if len(os.Args) > 1 && os.Args[1] == "bot" {
+		if err = botCommand(os.Args[2:], os.Stdout); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// "gosnake train" teaches the autopilot without user interface
+	if len(os.Args) > 1 && os.Args[1] == "train" {
+		if err = trainCommand(os.Args[2:], os.Stdout); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// "gosnake evolve" tunes the heuristic bot without user interface
+	if len(os.Args) > 1 && os.Args[1] == "evolve" {
+		if err = evolveCommand(os.Args[2:], os.Stdout); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// "gosnake tournament" plays the strategies against each other without user interface
+	if len(os.Args) > 1 && os.Args[1] == "tournament" {
+		if err = tournamentCommand(os.Args[2:], os.Stdout); errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	if opts, err = parseOptions(os.Args[1:]); err != nil {
+		// The usage has already been printed
+		if errors.Is(err, flag.ErrHelp) {
+			err = nil
+		}
+
+		return
+	}
+
+	// Opens the log file if requested
+	if err = openErrorLog(errLog, opts); err != nil {
+		return
+	}
+
+	// Reads the settings
+	if cfg, err = loadConfig(opts.configFile); err != nil {
+		return
+	}
+
+	// The error panel shows the keys of the configuration
+	if err = setErrorKeys(errHistory, &cfg); err != nil {
+		return
+	}
+
+	boardSize = common.Size{
+		Width:  cfg.Board.DefaultSize,
+		Height: cfg.Board.DefaultSize,
+	}
+
+	// In test mode Play() can be made to fail on demand
+	if opts.faultInjection {
+		gameState = gamestate.NewFaultInjector(gameState)
+	}
+
+	// The supervisor allows resuming a game after a crash of the engine
+	gameSupervisor = supervisor.New(opts.retries)
+
+	// The bot is started before the game, the errors of a program would garble the screen and are discarded
+	if opts.bot != "" || opts.battlesnake != "" {
+		aBot = newBot(strings.Fields(opts.bot), opts.battlesnake, bot.Settings{Timeout: opts.botTimeout,
+			Forfeit: opts.botForfeit})
+		if err = aBot.Start(); err != nil {
+			return
+		}
+		defer closeBot(aBot)
+	} else if aBot, err = newAutopilot(opts.autopilot); err != nil {
+		// Without a bot, the autopilot can take over the keys
+		return
+	}
 
 	// Inits the user interface library
-	if err = openUI(userInterface); err != nil {
+	if userInterface, err = uimanager.NewBackend(opts.backend); err != nil {
+		return
+	}
+
+	// The spectators and the recording get a copy of the screen drawn off screen
+	if opts.spectate != "" || opts.record != "" {
+		mirror = uimanager.NewMirror(userInterface)
+		userInterface = mirror
+	}
+
+	if err = openUI(userInterface, cfg.Display.Colors); err != nil {
 		return
 	}
 	defer closeUI(userInterface)
 
+	// The board cells are displayed with the configured glyphs and theme
+	userInterface.SetGlyphs(cfg.Glyphs.Map())
+	userInterface.SetBoardMode(cfg.Display.BoardMode)
+
+	if err = setTheme(userInterface, cfg.Display.Theme); err != nil {
+		return
+	}
+
 	// Inits the state and creates the gameBoard
-	if err = initGame(gameState, common.Size{
-		Width:  defaultBoardSize,
-		Height: defaultBoardSize,
-	}); err != nil {
+	if err = initGame(gameState, boardSize); err != nil {
 		return
 	}
 
-	// Creates the UI layout
-	if err = createViews(gameState, userInterface, boardSize); err != nil {
+	// Creates the UI layout, the panel stands beside the largest board
+	aScreen := setLayout(cfg.BoardViewSize(boardSize))
+
+	if err = createViews(gameState, userInterface, aScreen, &cfg, boardSize); err != nil {
 		return
 	}
 
@@ -109,36 +346,58 @@ func main() {
 		return
 	}
 
+	// Records the frames as displayed, from the first one
+	if opts.record != "" {
+		if err = recorder.Start(opts.record, common.Size{Width: aScreen.maxX + 1, Height: aScreen.maxY + 1},
+			mirror.Frame, cfg.Timing.RefreshInterval); err != nil {
+			return
+		}
+		defer closeRecorder(recorder)
+	}
+
+	// Lets teammates watch the game with nc or telnet
+	if opts.spectate != "" {
+		if spectators, err = startSpectators(gameState, mirror, aScreen, errLog, &cfg, opts); err != nil {
+			return
+		}
+		defer spectators.Close()
+
+		// The score views drawn from now on count the spectators
+		aScreen.spectatorCount = spectators.Count
+	}
+
 	// Attaches the event handler
-	if err = setEventHandler(gameState,
-		userInterface, &scrollOver, &boardSize, &errChn); err != nil {
+	if err = setEventHandler(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot, &cfg,
+		&scrollOver, &boardSize, &attract, &errChn); err != nil {
 		return
 	}
 
+	// The autopilot plays demo games while the game sits idle
+	if cfg.Timing.AttractTimeout > 0 {
+		if attract.pilot, err = newDemoPilot(opts.autopilot); err != nil {
+			return
+		}
+
+		// The routine gets its own copy of the configuration, the key handler changes the theme of cfg
+		attractCfg := cfg
+
+		go runAttract(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, &attractCfg, &scrollOver,
+			&attract, &errChn)
+	}
+
 	// Enters the user interface main loop
 	// which will quit when it receives uimanager.ErrQuit from the event handler
 	err = eventLoop(userInterface)
 }
 
-func reportError(funcName string, err, errChn *error) {
+func reportError(funcName string, errLog errorlog.ErrorLogger, err, errChn *error) {
 	// Called in defer to report errors before quitting
 
 	var errPanic = errors.New("runtime error")
 
 	// Is there a aPanic going on?
 	if aPanic := recover(); aPanic != nil {
-		// Test the type of aPanic
-		switch val := aPanic.(type) {
-		case string:
-			*err = errors.New(val)
-		case error:
-			*err = val
-		default: // or simply convert aPanic to string then create a new error...
-			strErr := fmt.Sprint(aPanic)
-			*err = errors.New(strErr)
-		}
-
-		*err = fmt.Errorf(funcName+": %w", *err)
+		*err = fmt.Errorf(funcName+": %w", common.NewPanicError(aPanic))
 	}
 	// Is there an error coming from routines?
 	// Then errChn will be reported first
@@ -155,260 +414,1246 @@ func reportError(funcName string, err, errChn *error) {
 		// So it is not an error
 		if !errors.Is(*err, uimanager.ErrQuit) {
 			fmt.Println(*err)
+
+			if errLog := errLog.LogError("main", *err); errLog != nil {
+				fmt.Println(errLog)
+			}
 		}
 	}
 }
 
-func initGame(gameState gamestate.GameStater, boardSize common.Size) (err error) {
+func parseOptions(args []string) (opts options, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	return gameState.InitBoard(boardSize)
-
-}
+	var (
+		levelName string
+		flags     = flag.NewFlagSet("gosnake", flag.ContinueOnError)
+	)
 
-func displayPlayers(gameState gamestate.GameStater, userInterface uimanager.UIManagerer) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+	flags.StringVar(&opts.configFile, "config", "",
+		"configuration `file` (default $XDG_CONFIG_HOME/gosnake/config.yaml)")
+	flags.StringVar(&opts.logFile, "log", "", "writes errors and events to a structured log `file`")
+	flags.StringVar(&levelName, "log-level", errorlog.LevelError.String(),
+		"minimum `level` written to the log file: debug, info, warn or error")
+	flags.IntVar(&opts.retries, "retries", supervisor.DefaultRetries,
+		"`number` of times a game can be resumed after a crash of the engine")
+	flags.BoolVar(&opts.faultInjection, "fault-injection", false,
+		"test mode: F9 makes the next round fail with an error, F10 with a panic")
+	flags.StringVar(&opts.backend, "backend", uimanager.BackendGocui,
+		"`name` of the user interface backend: gocui, or ansi which doesn't use termbox")
+	flags.StringVar(&opts.spectate, "spectate", "",
+		"lets teammates watch the game with nc or telnet on `address` (e.g. :2323)")
+	flags.IntVar(&opts.maxSpectators, "max-spectators", spectator.DefaultMaxSpectators,
+		"maximum `number` of spectators connected at once")
+	flags.StringVar(&opts.record, "record", "", "records the session to an asciicast v2 `file` (asciinema)")
+	flags.StringVar(&opts.bot, "bot", "", "the `command` of a bot steering the snake, e.g. \"python3 bot.py\"")
+	flags.StringVar(&opts.battlesnake, "battlesnake", "",
+		"the `URL` of a Battlesnake server steering the snake, e.g. http://localhost:8000")
+	flags.DurationVar(&opts.botTimeout, "bot-timeout", bot.DefaultTimeout, "time budget of each reply of the bot")
+	flags.BoolVar(&opts.botForfeit, "bot-forfeit", false,
+		"a late or malformed reply of the bot loses the game instead of keeping the snake straight")
+	flags.StringVar(&opts.autopilot, "autopilot", "",
+		"the strategy played by the autopilot, e.g. mcts, or the `file` of a bot profile written by gosnake evolve "+
+			"or of a Q-table written by gosnake train (default greedy)")
+
+	if err = flags.Parse(args); err != nil {
+		return opts, err
+	}
 
-	// Creates the objects
-	var listSprite []common.Sprite
+	if opts.retries < 0 {
+		return opts, errInvalidRetries
+	}
 
-	if listSprite, err = createObjects(gameState); err != nil {
-		return err
+	if opts.maxSpectators < 1 {
+		return opts, errInvalidSpectators
 	}
 
-	// Displays the objects to the board view
-	return updateView(userInterface, boardViewTitle, listSprite)
-}
+	if opts.bot != "" && opts.battlesnake != "" {
+		return opts, errTwoBots
+	}
 
-func openUI(userInterface uimanager.UIManagerer) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+	if opts.autopilot != "" && (opts.bot != "" || opts.battlesnake != "") {
+		return opts, errBotAutopilot
+	}
 
-	return userInterface.OpenUIManager()
-}
+	opts.logLevel, err = errorlog.ParseLevel(levelName)
 
-func closeUI(userInterface uimanager.UIManagerer) {
-	userInterface.Close()
+	return opts, err
 }
 
-func setEventHandler(gameState gamestate.GameStater, userInterface uimanager.UIManagerer,
-	scollOver *bool, boardSize *common.Size, errChan *error) (err error) {
-
+func configCommand(args []string) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	// We use a closure
-	theHandler := func(key uimanager.Key) error {
-		// which will have access to the surrounding parameters
-		return handleKeyPress(gameState, userInterface, key,
-			scollOver, boardSize, errChan)
-	}
+	var (
+		configFile string
+		force      bool
+		flags      = flag.NewFlagSet("gosnake config init", flag.ContinueOnError)
+	)
 
-	return userInterface.OnKeyPress(theHandler)
-}
+	if len(args) == 0 || args[0] != "init" {
+		return fmt.Errorf("%w: gosnake config %s (expected: gosnake config init)",
+			errUnknownCommand, strings.Join(args, " "))
+	}
 
-func eventLoop(userInterface uimanager.UIManagerer) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+	flags.StringVar(&configFile, "config", "", "configuration `file` to write instead of the default one")
+	flags.BoolVar(&force, "force", false, "replaces an existing file")
 
-	return userInterface.MainLoop()
-}
+	if err = flags.Parse(args[1:]); err != nil {
+		return err
+	}
 
-func createObjects(gameState gamestate.GameStater) (listSprite []common.Sprite, err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+	if configFile == "" {
+		if configFile, err = config.DefaultPath(); err != nil {
+			return err
+		}
+	}
 
-	// Creates the snake and candy
-	return gameState.CreateObjects()
-}
+	if err = config.Write(configFile, config.Default(), force); err != nil {
+		return err
+	}
 
-func updateView(userInterface uimanager.UIManagerer, viewName string, spriteList []common.Sprite) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+	fmt.Println("Default configuration written to", configFile)
 
-	return userInterface.Update(viewName, spriteList)
+	return nil
 }
 
-func handleKeyPress(gameState gamestate.GameStater, userInterface uimanager.UIManagerer,
-	key uimanager.Key, scrollOver *bool, boardSize *common.Size, errChan *error) (err error) {
+func webCommand(args []string, errLog errorlog.ErrorLogger) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	switch key {
-	case uimanager.KeyCtrlC:
-		return userInterface.Quit()
-	case uimanager.KeyArrowUp:
-		gameState.MoveUp()
-		return nil
-	case uimanager.KeyArrowDown:
-		gameState.MoveDown()
-		return nil
-	case uimanager.KeyArrowLeft:
-		gameState.MoveLeft()
-		return nil
-	case uimanager.KeyArrowRight:
-		gameState.MoveRight()
-		return nil
-	case uimanager.KeySpace:
-		if !gameState.GameInProgress() && *scrollOver {
-			if gameState.Dirty() {
-				if err := prepareGame(gameState, userInterface, boardSize); err != nil {
-					return err
-				}
-			}
+	var (
+		address    string
+		configFile string
+		logFile    string
+		flags      = flag.NewFlagSet("gosnake web", flag.ContinueOnError)
+	)
 
-			if err := startGame(gameState, userInterface, scrollOver, errChan); err != nil {
-				return err
-			}
-		}
+	flags.StringVar(&address, "listen", webui.DefaultAddress,
+		"`address` the browsers connect to, :8080 accepts the other machines")
+	flags.StringVar(&configFile, "config", "",
+		"configuration `file` (default $XDG_CONFIG_HOME/gosnake/config.yaml)")
+	flags.StringVar(&logFile, "log", "", "writes the connections and the errors to a structured log `file`")
 
-		return nil
-	case uimanager.KeyEnter:
-		if !gameState.GameInProgress() && *scrollOver {
-			toggleBoardViewSize(boardSize)
+	if err = flags.Parse(args); err != nil {
+		return err
+	}
 
-			if err := prepareGame(gameState, userInterface, boardSize); err != nil {
-				return err
-			}
+	if logFile != "" {
+		if err = errLog.Open(logFile, errorlog.LevelInfo); err != nil {
+			return err
 		}
+	}
 
-		return nil
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	server, err := webui.New(cfg, errLog)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Open", webURL(address), "in a browser, ctrl+c stops the server")
+
+	return server.ListenAndServe(address)
 }
 
-func toggleBoardViewSize(boardSize *common.Size) {
-	// Change the size of the board by cycling threw 10;20;30;40 (default)
-	if boardSize.Width < defaultBoardSize {
-		boardSize.Width += sizeIncrement
-		boardSize.Height += sizeIncrement
-	} else {
-		boardSize.Width = sizeIncrement
-		boardSize.Height = sizeIncrement
+// webURL returns the address of the page, on the local machine when the host is omitted
+func webURL(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "http://" + address
+	}
+
+	if host == "" {
+		host = "localhost"
 	}
+
+	return "http://" + net.JoinHostPort(host, port)
 }
 
-func prepareGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, boardSize *common.Size) (err error) {
+func tournamentCommand(args []string, output io.Writer) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	if err := initGame(gameState, *boardSize); err != nil {
+	var (
+		settings   tournament.Settings
+		strategies string
+		sizes      string
+		csvFile    string
+		flags      = flag.NewFlagSet("gosnake tournament", flag.ContinueOnError)
+	)
+
+	flags.StringVar(&strategies, "strategies", strings.Join(strategy.Names(), ","),
+		"comma separated `names` of the strategies playing")
+	flags.StringVar(&sizes, "sizes", "10,20,40", "comma separated `sizes` of the boards played on")
+	flags.IntVar(&settings.Games, "games", 100, "number of games of each strategy on each board size")
+	flags.Int64Var(&settings.Seed, "seed", 1, "seed of the first game of each series, the next ones add 1")
+	flags.IntVar(&settings.MaxRounds, "max-rounds", 0,
+		fmt.Sprintf("rounds after which a game stops (default %d per board cell)", gamestate.RoundsPerCell))
+	flags.IntVar(&settings.Workers, "workers", 0, "games played at once (default one per CPU core)")
+	flags.StringVar(&csvFile, "csv", "", "also writes the report to a CSV `file`")
+
+	if err = flags.Parse(args); err != nil {
 		return err
 	}
 
-	if err := createBoardView(userInterface, *boardSize); err != nil {
+	settings.Strategies = strings.Split(strategies, ",")
+
+	for _, size := range strings.Split(sizes, ",") {
+		boardSize, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			return fmt.Errorf("%w: %q", errInvalidBoardSizes, sizes)
+		}
+
+		settings.BoardSizes = append(settings.BoardSizes, boardSize)
+	}
+
+	report, err := tournament.Run(settings)
+	if err != nil {
 		return err
 	}
 
-	if err := clearView(userInterface, boardViewTitle); err != nil {
+	if err := report.WriteText(output); err != nil {
 		return err
 	}
 
-	if err := displayPlayers(gameState, userInterface); err != nil {
+	if csvFile == "" {
+		return nil
+	}
+
+	file, err := os.Create(csvFile)
+	if err != nil {
 		return err
 	}
 
-	if err := createScoreView(gameState, userInterface); err != nil {
+	if err := report.WriteCSV(file); err != nil {
+		file.Close()
 		return err
 	}
 
-	return nil
+	return file.Close()
 }
 
-func startGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer,
-	scrollOver *bool, errChn *error) (err error) {
-
+func botCommand(args []string, output io.Writer) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	gameState.Start()
+	var (
+		settings  = bot.Settings{Stderr: os.Stderr}
+		url       string
+		games     int
+		seed      int64
+		size      int
+		maxRounds int
+		flags     = flag.NewFlagSet("gosnake bot", flag.ContinueOnError)
+	)
 
-	go func() {
-		// If for whatever reason a panic occures we handle, display and chanel it
-		defer handlePanic(userInterface, errChn)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosnake bot [options] command [arguments]")
+		fmt.Fprintln(flags.Output(), "       gosnake bot [options] -battlesnake URL")
+		flags.PrintDefaults()
+	}
 
-		// launch the gameEngine
-		errChan := make(chan error)
+	flags.StringVar(&url, "battlesnake", "", "the `URL` of a Battlesnake server playing instead of a command")
+	flags.IntVar(&games, "games", 1, "number of games played")
+	flags.Int64Var(&seed, "seed", 1, "seed of the first game, the next ones add 1")
+	flags.IntVar(&size, "size", config.Default().Board.DefaultSize, "`size` of the board")
+	flags.IntVar(&maxRounds, "max-rounds", 0,
+		fmt.Sprintf("rounds after which a game stops (default %d per board cell)", gamestate.RoundsPerCell))
+	flags.DurationVar(&settings.Timeout, "timeout", bot.DefaultTimeout, "time budget of each reply")
+	flags.BoolVar(&settings.Forfeit, "forfeit", false,
+		"a late or malformed reply loses the game instead of keeping the snake straight")
+
+	if err = flags.Parse(args); err != nil {
+		return err
+	}
 
-		go gameEngine(gameState, userInterface, errChan)
-		*errChn = <-errChan
+	switch {
+	case flags.NArg() == 0 && url == "":
+		flags.Usage()
+		return bot.ErrNoCommand
+	case flags.NArg() > 0 && url != "":
+		return errTwoBots
+	}
 
-		// Launch the game over animation
-		if *errChn == nil && !gameState.GameInProgress() {
-			errChan := make(chan error)
-			go gameOverAnim(userInterface, scrollOver, errChan)
-			*errChn = <-errChan
-		}
-	}()
+	if size < gamestate.MinBoardSize || games < 1 || maxRounds < 0 {
+		return fmt.Errorf("%w: %d games on a board of %d, %d rounds", errInvalidBotGames, games, size, maxRounds)
+	}
 
-	return err
-}
+	if maxRounds == 0 {
+		maxRounds = gamestate.RoundsPerCell * size * size
+	}
 
-func handlePanic(userInterface uimanager.UIManagerer, errChn *error) {
-	if aPanic := recover(); aPanic != nil {
-		var err error
-		switch val := aPanic.(type) {
-		case string:
-			err = errors.New(val)
-		case error:
-			err = val
-		default: // or simply convert aPanic to string then create a new error...
-			strErr := fmt.Sprint(aPanic)
-			err = errors.New(strErr)
-		}
-		err = fmt.Errorf(common.GetCurrentFuncName()+": %w", err)
-		errChan := make(chan error)
-		go handleRoutineError(userInterface, errChan, &err, "   Start Game")
-		*errChn = <-errChan
+	aBot := newBot(flags.Args(), url, settings)
+	if err = aBot.Start(); err != nil {
+		return err
 	}
-}
+	defer closeBot(aBot)
 
-func gameEngine(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, errChan chan error) {
-	var err error
+	for i := 0; i < games; i++ {
+		if err := playBot(aBot, common.Size{Width: size, Height: size}, seed+int64(i), maxRounds, output); err != nil {
+			return err
+		}
+	}
 
-	defer gameState.SetGameInProgress(false)
-	defer handleRoutineError(userInterface, errChan, &err, "   Game Engine")
+	return nil
+}
+
+// playBot plays a game with the bot, the misses and the outcome are written to output
+func playBot(aBot bot.Boter, boardSize common.Size, seed int64, maxRounds int, output io.Writer) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	// The game loop
-	ticker := time.NewTicker(refreshInterval)
-	for range ticker.C {
-		var spriteList []common.Sprite
+	gameState, err := gamestate.NewSeeded(boardSize, seed)
+	if err != nil {
+		return err
+	}
+
+	outcome := "collision"
 
-		if spriteList, err = gameState.Play(); err != nil {
+	for gameState.GameInProgress() {
+		if gameState.Round() >= maxRounds {
+			outcome = "round limit"
 			break
 		}
 
-		if err = createScoreView(gameState, userInterface); err != nil {
-			break
+		if missErr := botMove(gameState, aBot); missErr != nil {
+			fmt.Fprintf(output, "round %d: %v\n", gameState.Round()+1, missErr)
+
+			if errors.Is(missErr, bot.ErrForfeit) {
+				outcome = "forfeit"
+				break
+			}
 		}
 
-		if err = updateView(userInterface, boardViewTitle, spriteList); err != nil {
-			break
+		roundOutcome, err := gamestate.PlayRound(gameState)
+		if err != nil {
+			return err
 		}
 
-		if !gameState.GameInProgress() {
+		if roundOutcome == gamestate.OutcomeBoardFull {
+			outcome = "board full"
 			break
 		}
 	}
-}
 
-func gameOverAnim(userInterface uimanager.UIManagerer, scrollOver *bool, errChan chan error) {
-	var err error
+	fmt.Fprintf(output, "seed %d: score %d in %d rounds, %s\n", seed, gameState.Score(), gameState.Round(), outcome)
 
-	defer func() { *scrollOver = true }()
-	defer handleRoutineError(userInterface, errChan, &err, "  Game Over Anim")
+	if endErr := aBot.EndGame(gameState); endErr != nil {
+		fmt.Fprintf(output, "end of the game: %v\n", endErr)
+	}
+
+	return nil
+}
+
+func trainCommand(args []string, output io.Writer) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	// These constants could be calculated with the view's available width
-	const (
-		posMax        = 16
-		scrollMessage = "                    GAME OVER!!!              "
-		chunkLength   = 18
+	var (
+		settings = qlearning.DefaultSettings()
+		inFile   string
+		outFile  string
+		flags    = flag.NewFlagSet("gosnake train", flag.ContinueOnError)
 	)
 
-	scrollPosition := 0
+	flags.IntVar(&settings.Episodes, "episodes", settings.Episodes, "number of games played to learn")
+	flags.IntVar(&settings.BoardSize, "size", settings.BoardSize, "`size` of the board")
+	flags.Int64Var(&settings.Seed, "seed", settings.Seed, "seed of the first game, the next ones add 1")
+	flags.IntVar(&settings.MaxRounds, "max-rounds", 0,
+		fmt.Sprintf("rounds after which a game stops (default %d per board cell)", gamestate.RoundsPerCell))
+	flags.Float64Var(&settings.Alpha, "alpha", settings.Alpha, "learning rate")
+	flags.Float64Var(&settings.Gamma, "gamma", settings.Gamma, "discount of the future rewards")
+	flags.Float64Var(&settings.Epsilon, "epsilon", settings.Epsilon,
+		"chance of a random move in the first game, it decreases over the games")
+	flags.StringVar(&inFile, "in", "", "goes on training the Q-table of `file`")
+	flags.StringVar(&outFile, "out", "qtable.json", "writes the Q-table to `file`")
+
+	if err = flags.Parse(args); err != nil {
+		return err
+	}
 
-	*scrollOver = false
+	if settings.MinEpsilon > settings.Epsilon {
+		settings.MinEpsilon = settings.Epsilon
+	}
 
-	// The scroll loop
-	ticker := time.NewTicker(refreshInterval)
-	for range ticker.C {
+	table := qlearning.NewTable()
+	if inFile != "" {
+		if table, err = qlearning.LoadFile(inFile); err != nil {
+			return err
+		}
+	}
 
-		chunk := scrollMessage[scrollPosition : scrollPosition+chunkLength]
+	// The outcomes are summed up ten times over the training
+	var (
+		period = (settings.Episodes + 9) / 10
+		total  int
+		best   int
+	)
+
+	err = qlearning.Train(table, settings, func(episode int, outcome qlearning.Episode) {
+		total += outcome.Score
+		if outcome.Score > best {
+			best = outcome.Score
+		}
+
+		if (episode+1)%period == 0 || episode+1 == settings.Episodes {
+			played := episode%period + 1
+			fmt.Fprintf(output, "episodes %d to %d: mean score %.2f, best %d\n", episode+2-played, episode+1,
+				float64(total)/float64(played), best)
+			total, best = 0, 0
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := table.SaveFile(outFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "Q-table written to %s, play it with gosnake -autopilot %s\n", outFile, outFile)
+
+	return nil
+}
+
+// evolveCommand runs "gosnake evolve": the generations of heuristic bots are evaluated and bred,
+// the population is saved after each one and the fittest genome is written as a bot profile
+func evolveCommand(args []string, output io.Writer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var (
+		settings   = evolve.DefaultSettings()
+		checkpoint string
+		outFile    string
+		flags      = flag.NewFlagSet("gosnake evolve", flag.ContinueOnError)
+	)
+
+	flags.IntVar(&settings.Population, "population", settings.Population, "number of genomes of each generation")
+	flags.IntVar(&settings.Generations, "generations", settings.Generations, "number of generations")
+	flags.IntVar(&settings.Games, "games", settings.Games, "games played by each genome in a generation")
+	flags.IntVar(&settings.BoardSize, "size", settings.BoardSize, "`size` of the board")
+	flags.Int64Var(&settings.Seed, "seed", settings.Seed, "seed of the evolution and of the first game")
+	flags.IntVar(&settings.MaxRounds, "max-rounds", 0,
+		fmt.Sprintf("rounds after which a game stops (default %d per board cell)", gamestate.RoundsPerCell))
+	flags.IntVar(&settings.Workers, "workers", 0, "games played at once (default one per CPU core)")
+	flags.StringVar(&checkpoint, "checkpoint", "",
+		"saves the population to `file` after each generation, the evolution resumes from it when it exists")
+	flags.StringVar(&outFile, "out", "bot.json", "writes the profile of the fittest genome to `file`")
+
+	if err = flags.Parse(args); err != nil {
+		return err
+	}
+
+	population := evolve.NewPopulation(settings)
+	if checkpoint != "" {
+		if _, statErr := os.Stat(checkpoint); statErr == nil {
+			if population, err = evolve.LoadPopulation(checkpoint); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(output, "resuming from generation %d of %s\n", population.Generation, checkpoint)
+		}
+	}
+
+	err = evolve.Evolve(population, settings, func(population *evolve.Population) error {
+		mean := 0.0
+		for _, individual := range population.Individuals {
+			mean += individual.Fitness
+		}
+
+		best := population.Best()
+		weights := make([]string, 0, evolve.FeatureCount)
+
+		for feature, name := range evolve.FeatureNames {
+			weights = append(weights, fmt.Sprintf("%s %.3f", name, best.Genome[feature]))
+		}
+
+		fmt.Fprintf(output, "generation %d: best fitness %.2f, mean %.2f, weights %s\n", population.Generation,
+			best.Fitness, mean/float64(len(population.Individuals)), strings.Join(weights, ", "))
+
+		if checkpoint == "" {
+			return nil
+		}
+
+		return population.SaveFile(checkpoint)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := population.Best().SaveFile(outFile); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "bot profile written to %s, play it with gosnake -autopilot %s\n", outFile, outFile)
+
+	return nil
+}
+
+func loadConfig(configFile string) (cfg config.Config, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The file given on the command line must exist, the default one is optional
+	if configFile != "" {
+		return config.Load(configFile, false)
+	}
+
+	if configFile, err = config.DefaultPath(); err != nil {
+		return config.Default(), nil
+	}
+
+	return config.Load(configFile, true)
+}
+
+func openErrorLog(errLog errorlog.ErrorLogger, opts options) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// No log file was requested
+	if opts.logFile == "" {
+		return nil
+	}
+
+	return errLog.Open(opts.logFile, opts.logLevel)
+}
+
+func closeErrorLog(errLog errorlog.ErrorLogger) {
+	if err := errLog.Close(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func closeRecorder(recorder asciicast.Recorderer) {
+	if err := recorder.Close(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// newBot returns the program of command, or the Battlesnake server at url when there is no command
+func newBot(command []string, url string, settings bot.Settings) bot.Boter {
+	if len(command) == 0 {
+		return battlesnake.New(battlesnake.Settings{URL: url, Timeout: settings.Timeout, Forfeit: settings.Forfeit})
+	}
+
+	settings.Command = command
+
+	return bot.New(settings)
+}
+
+// newAutopilot returns the autopilot playing the strategy named pilot, or the bot profile or the Q-table of the file pilot
+// The greedy strategy plays when pilot is empty
+func newAutopilot(pilot string) (aBot bot.Boter, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if pilot == "" {
+		pilot = "greedy"
+	}
+
+	if aStrategy, err := strategy.New(pilot, 0); err == nil {
+		return autopilot.New(aStrategy), nil
+	}
+
+	// A file which isn't a bot profile may be a Q-table
+	profile, err := evolve.LoadProfile(pilot)
+	if err == nil {
+		return autopilot.New(evolve.NewBot(profile.Genome)), nil
+	} else if !errors.Is(err, evolve.ErrInvalidProfile) {
+		return nil, err
+	}
+
+	table, err := qlearning.LoadFile(pilot)
+	if err != nil {
+		return nil, err
+	}
+
+	return autopilot.New(qlearning.NewAgent(table)), nil
+}
+
+// newDemoPilot returns the autopilot of the demo games, switched on, it plays what the O key would
+func newDemoPilot(pilot string) (aBot bot.Boter, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aBot, err = newAutopilot(pilot); err != nil {
+		return nil, err
+	}
+
+	if anAutopilot, ok := aBot.(autopilot.Autopiloter); ok {
+		anAutopilot.Toggle()
+	}
+
+	return aBot, nil
+}
+
+func closeBot(aBot bot.Boter) {
+	if err := aBot.Close(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func startSpectators(gameState gamestate.GameStater, userInterface uimanager.Mirrorer, aScreen screen,
+	errLog errorlog.ErrorLogger, cfg *config.Config, opts options) (spectators spectator.Spectatorer, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The score view shows the number of spectators, it is updated when they come and go
+	spectators = spectator.New(opts.maxSpectators, func(int) {
+		if err := createScoreView(gameState, userInterface, aScreen, spectators.Count); err != nil {
+			errLog.LogError("spectators", err)
+		}
+	})
+
+	// The frames are sent at the pace of the game engine
+	if err := spectators.Start(opts.spectate, userInterface.Frame, cfg.Timing.RefreshInterval); err != nil {
+		return nil, err
+	}
+
+	if err := errLog.LogMessage(errorlog.LevelInfo, "spectators",
+		"spectators can connect to "+spectators.Address()); err != nil {
+		return spectators, err
+	}
+
+	return spectators, createScoreView(gameState, userInterface, aScreen, spectators.Count)
+}
+
+func initGame(gameState gamestate.GameStater, boardSize common.Size) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return gameState.InitBoard(boardSize)
+
+}
+
+func displayPlayers(gameState gamestate.GameStater, userInterface uimanager.UIManagerer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// Creates the objects
+	var listSprite []common.Sprite
+
+	if listSprite, err = createObjects(gameState); err != nil {
+		return err
+	}
+
+	// Displays the objects to the board view
+	return updateView(userInterface, boardViewTitle, listSprite)
+}
+
+func openUI(userInterface uimanager.UIManagerer, colorMode uimanager.ColorMode) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return userInterface.OpenUIManager(colorMode)
+}
+
+func closeUI(userInterface uimanager.UIManagerer) {
+	userInterface.Close()
+}
+
+func setEventHandler(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, scollOver *bool, boardSize *common.Size, attract *attractMode,
+	errChan *error) (err error) {
+
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	bindings, err := cfg.Controls.Table()
+	if err != nil {
+		return err
+	}
+
+	// We use a closure
+	theHandler := func(key uimanager.Key) error {
+		action := bindings.Action(key)
+
+		if err := errLog.LogMessage(errorlog.LevelDebug, "key press",
+			fmt.Sprintf("key %v: %v", key, action)); err != nil {
+			return err
+		}
+
+		// A key stops the demo game instead of playing, the quit key still quits
+		attract.mutex.Lock()
+		defer attract.mutex.Unlock()
+
+		if stopDemo(attract) && action != keybindings.ActionQuit {
+			return nil
+		}
+
+		// which will have access to the surrounding parameters
+		return handleKeyPress(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot, cfg, action,
+			scollOver, boardSize, errChan)
+	}
+
+	return userInterface.OnKeyPress(bindings.Keys(), theHandler)
+}
+
+func eventLoop(userInterface uimanager.UIManagerer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return userInterface.MainLoop()
+}
+
+func createObjects(gameState gamestate.GameStater) (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// Creates the snake and candy
+	return gameState.CreateObjects()
+}
+
+func updateView(userInterface uimanager.UIManagerer, viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return userInterface.Update(viewName, spriteList)
+}
+
+func handleKeyPress(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, action keybindings.Action, scrollOver *bool, boardSize *common.Size,
+	errChan *error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The moves, the pause and the start are handled as in the browser
+	frontend := engine.Frontend{
+		ShowPause: func(paused bool) error {
+			return showPause(userInterface, paused)
+		},
+		NewBoard: func() error {
+			return prepareGame(gameState, userInterface, aScreen, cfg, boardSize)
+		},
+		StartGame: func() error {
+			return startGame(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot,
+				cfg, scrollOver, errChan)
+		},
+	}
+
+	switch action {
+	case keybindings.ActionQuit:
+		return userInterface.Quit()
+	case keybindings.ActionUp, keybindings.ActionDown, keybindings.ActionLeft, keybindings.ActionRight,
+		keybindings.ActionPause:
+		_, err := engine.HandleAction(gameState, action, frontend)
+		return err
+	case keybindings.ActionTheme:
+		return nextTheme(gameState, userInterface, aScreen, errHistory, cfg)
+	case keybindings.ActionExportGIF:
+		// The game is exported once over
+		if !gameState.GameInProgress() && *scrollOver {
+			return exportGIF(gameState, userInterface, aScreen, errLog, errHistory, cfg)
+		}
+
+		return nil
+	case keybindings.ActionSnapshot:
+		return saveSnapshot(gameState, userInterface, aScreen, errLog, errHistory, cfg)
+	case keybindings.ActionAutopilot:
+		return toggleAutopilot(aBot, userInterface)
+	case keybindings.ActionStart:
+		// The game over is scrolled first
+		if !*scrollOver {
+			return nil
+		}
+
+		_, err := engine.HandleAction(gameState, action, frontend)
+		return err
+	case keybindings.ActionResize:
+		if !gameState.GameInProgress() && *scrollOver {
+			// After a crash the key resumes the game from the last checkpoint
+			if gameSupervisor.CanResume() {
+				return resumeGame(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot,
+					cfg, scrollOver, errChan)
+			}
+
+			toggleBoardViewSize(cfg, boardSize)
+
+			if err := prepareGame(gameState, userInterface, aScreen, cfg, boardSize); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case keybindings.ActionScrollUp:
+		return scrollErrorView(errHistory, userInterface, aScreen, -1)
+	case keybindings.ActionScrollDown:
+		return scrollErrorView(errHistory, userInterface, aScreen, 1)
+	case keybindings.ActionErrorDetails:
+		return toggleErrorOverlay(errHistory, userInterface, aScreen, !errHistory.Expanded())
+	case keybindings.ActionCloseDetails:
+		return toggleErrorOverlay(errHistory, userInterface, aScreen, false)
+	case keybindings.ActionInjectError:
+		injectFault(gameState, false)
+		return nil
+	case keybindings.ActionInjectPanic:
+		injectFault(gameState, true)
+		return nil
+	}
+
+	return nil
+}
+
+// botMove steers the snake as the bot replies, a miss is returned and the snake goes straight
+func botMove(gameState gamestate.GameStater, aBot bot.Boter) (missErr error) {
+	direction, missErr := aBot.NextMove(gameState)
+
+	switch direction {
+	case strategy.Up:
+		engine.Steer(gameState, keybindings.ActionUp)
+	case strategy.Down:
+		engine.Steer(gameState, keybindings.ActionDown)
+	case strategy.Left:
+		engine.Steer(gameState, keybindings.ActionLeft)
+	case strategy.Right:
+		engine.Steer(gameState, keybindings.ActionRight)
+	}
+
+	return missErr
+}
+
+// showPause shows the game paused or resumed in the message view
+func showPause(userInterface uimanager.UIManagerer, paused bool) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if paused {
+		return userInterface.UpdateLn(messageViewTitle, pausedMessage)
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, blankMessage)
+}
+
+func toggleAutopilot(aBot bot.Boter, userInterface uimanager.UIManagerer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The bots can't be switched off
+	anAutopilot, ok := aBot.(autopilot.Autopiloter)
+	if !ok {
+		return nil
+	}
+
+	if anAutopilot.Toggle() {
+		return userInterface.UpdateLn(messageViewTitle, autopilotOnMessage)
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, autopilotOffMessage)
+}
+
+func injectFault(gameState gamestate.GameStater, panicking bool) {
+	// Faults can only be injected in test mode
+	injector, ok := gameState.(gamestate.FaultInjector)
+	if !ok {
+		return
+	}
+
+	if panicking {
+		injector.InjectPanic()
+	} else {
+		injector.InjectError()
+	}
+}
+
+// exportGIF saves the last game to an animated GIF, a failed export is shown in the error view
+func exportGIF(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, cfg *config.Config) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	history := gameState.History()
+	if len(history.Rounds) == 0 {
+		return nil
+	}
+
+	aTheme, err := theme.Get(cfg.Display.Theme)
+	if err != nil {
+		return err
+	}
+
+	// A frame lasts a round
+	fileName, err := gifexport.Save(cfg.Export.Directory, history, aTheme.Palette(),
+		cfg.Export.CellSize, cfg.Timing.RefreshInterval)
+	if err != nil {
+		// The game goes on without its GIF
+		return reportExportError(userInterface, aScreen, errLog, errHistory, "GIF export", err)
+	}
+
+	if err := errLog.LogMessage(errorlog.LevelInfo, "GIF export", "game saved to "+fileName); err != nil {
+		return err
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, gifSavedMessage)
+}
+
+// saveSnapshot writes the board in the formats of the configuration, the files are named after the time
+func saveSnapshot(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, cfg *config.Config) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aTheme, err := theme.Get(cfg.Display.Theme)
+	if err != nil {
+		return err
+	}
+
+	snapshot := gameState.Snapshot()
+	baseName := filepath.Join(cfg.Export.Directory, "gosnake-"+time.Now().Format("20060102-150405"))
+
+	for _, format := range cfg.Export.SnapshotFormats {
+		fileName := baseName + "." + format
+
+		if err := writeSnapshot(fileName, format, snapshot, aTheme.Palette(), cfg.Export.CellSize); err != nil {
+			return reportExportError(userInterface, aScreen, errLog, errHistory, "snapshot", err)
+		}
+
+		if err := errLog.LogMessage(errorlog.LevelInfo, "snapshot", "board saved to "+fileName); err != nil {
+			return err
+		}
+	}
+
+	// The message view is left to the game in progress
+	if gameState.GameInProgress() {
+		return nil
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, snapshotSavedMessage)
+}
+
+func writeSnapshot(fileName, format string, snapshot common.BoardSnapshot, palette gameboard.Palette,
+	cellSize int) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err := gameboard.WriteSnapshot(file, format, snapshot, palette, cellSize); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// reportExportError shows the error of a file export, the game goes on
+func reportExportError(userInterface uimanager.UIManagerer, aScreen screen, errLog errorlog.ErrorLogger,
+	errHistory errorhistory.ErrorHistoryer, routine string, exportErr error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := errLog.LogError(routine, exportErr); err != nil {
+		return err
+	}
+
+	errHistory.Add(routine, exportErr)
+
+	return updateErrorView(errHistory, userInterface, aScreen)
+}
+
+func toggleBoardViewSize(cfg *config.Config, boardSize *common.Size) {
+	// Change the size of the board by cycling threw 10;20;30;40 (default)
+	// The last step is shorter if the default size isn't a multiple of the increment
+	switch {
+	case boardSize.Width >= cfg.Board.DefaultSize:
+		boardSize.Width = cfg.Board.SizeIncrement
+	case boardSize.Width+cfg.Board.SizeIncrement > cfg.Board.DefaultSize:
+		boardSize.Width = cfg.Board.DefaultSize
+	default:
+		boardSize.Width += cfg.Board.SizeIncrement
+	}
+
+	boardSize.Height = boardSize.Width
+}
+
+func prepareGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	cfg *config.Config, boardSize *common.Size) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := initGame(gameState, *boardSize); err != nil {
+		return err
+	}
+
+	if err := createBoardView(userInterface, cfg.BoardViewSize(*boardSize)); err != nil {
+		return err
+	}
+
+	if err := clearView(userInterface, boardViewTitle); err != nil {
+		return err
+	}
+
+	if err := displayPlayers(gameState, userInterface); err != nil {
+		return err
+	}
+
+	if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func startGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, scrollOver *bool, errChn *error) (err error) {
+
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// A new game restores the retry budget
+	gameSupervisor.Reset()
+	gameState.Start()
+
+	if err := errLog.LogMessage(errorlog.LevelInfo, "start game",
+		fmt.Sprintf("game started on a %dx%d board",
+			gameState.BoardSize().Width, gameState.BoardSize().Height)); err != nil {
+		return err
+	}
+
+	// The routines get their own copy of the configuration, the key handler changes the theme of cfg
+	gameCfg := *cfg
+
+	go runGame(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot, &gameCfg, scrollOver,
+		errChn)
+
+	return err
+}
+
+func resumeGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, scrollOver *bool, errChn *error) (err error) {
+
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The game state is set back to the last checkpoint
+	if err := gameSupervisor.Resume(gameState); err != nil {
+		return err
+	}
+
+	// The whole board is redrawn from the restored state
+	if err := clearView(userInterface, boardViewTitle); err != nil {
+		return err
+	}
+
+	if err := updateView(userInterface, boardViewTitle, gameState.Sprites()); err != nil {
+		return err
+	}
+
+	if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
+		return err
+	}
+
+	if err := userInterface.UpdateLn(messageViewTitle, blankMessage); err != nil {
+		return err
+	}
+
+	gameState.Resume()
+
+	if err := errLog.LogMessage(errorlog.LevelWarn, "resume game",
+		fmt.Sprintf("game resumed at round %d, %d retries left",
+			gameState.Round(), gameSupervisor.RetriesLeft())); err != nil {
+		return err
+	}
+
+	// The routines get their own copy of the configuration, the key handler changes the theme of cfg
+	gameCfg := *cfg
+
+	go runGame(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot, &gameCfg, scrollOver,
+		errChn)
+
+	return nil
+}
+
+func runGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, scrollOver *bool, errChn *error) {
+	// If for whatever reason a panic occures we handle, display and chanel it
+	defer handlePanic(userInterface, aScreen, errLog, errHistory, errChn)
+
+	// launch the gameEngine
+	errChan := make(chan error)
+
+	go gameEngine(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, aBot, cfg, nil, errChan)
+	*errChn = <-errChan
+
+	// The supervisor offers to resume a crashed game if the budget allows it
+	if *errChn != nil {
+		if gameSupervisor.Fail(*errChn) {
+			if err := offerResume(userInterface, gameSupervisor); err != nil {
+				*errChn = err
+			}
+		}
+
+		return
+	}
+
+	// Launch the game over animation
+	if !gameState.GameInProgress() {
+		if *errChn = errLog.LogMessage(errorlog.LevelInfo, "game over",
+			fmt.Sprintf("game over after %d rounds with %d candies",
+				gameState.Round(), gameState.Score())); *errChn != nil {
+			return
+		}
+
+		// The bot is told the game is over, it failing to hear it doesn't stop the game
+		if aBot != nil {
+			if endErr := aBot.EndGame(gameState); endErr != nil {
+				if *errChn = errLog.LogMessage(errorlog.LevelWarn, "bot", endErr.Error()); *errChn != nil {
+					return
+				}
+			}
+		}
+
+		errChan := make(chan error)
+		go gameOverAnim(userInterface, aScreen, errLog, errHistory, cfg, scrollOver, errChan)
+		*errChn = <-errChan
+	}
+}
+
+func offerResume(userInterface uimanager.UIManagerer, gameSupervisor supervisor.Supervisorer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return userInterface.UpdateLn(messageViewTitle,
+		fmt.Sprintf(" ENTER resumes (%d)", gameSupervisor.RetriesLeft()))
+}
+
+func handlePanic(userInterface uimanager.UIManagerer, aScreen screen, errLog errorlog.ErrorLogger,
+	errHistory errorhistory.ErrorHistoryer, errChn *error) {
+	if aPanic := recover(); aPanic != nil {
+		err := fmt.Errorf(common.GetCurrentFuncName()+": %w", common.NewPanicError(aPanic))
+		errChan := make(chan error)
+		go handleRoutineError(userInterface, aScreen, errLog, errHistory, errChan, &err, "Start Game")
+		*errChn = <-errChan
+	}
+}
+
+// gameEngine plays the game until it is over or stop is closed, stop is nil for the games played to the end
+func gameEngine(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	aBot bot.Boter, cfg *config.Config, stop <-chan struct{}, errChan chan error) {
+	var err error
+
+	defer gameState.SetGameInProgress(false)
+	defer handleRoutineError(userInterface, aScreen, errLog, errHistory, errChan, &err, "Game Engine")
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var trap trapWarning
+
+	frontend := engine.Frontend{
+		DrawRound: func(spriteList []common.Sprite) error {
+			if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
+				return err
+			}
+
+			if err := updateView(userInterface, boardViewTitle, spriteList); err != nil {
+				return err
+			}
+
+			if !gameState.GameInProgress() {
+				return nil
+			}
+
+			if err := warnTrap(gameState, userInterface, cfg, &trap); err != nil {
+				return err
+			}
+
+			// The round was played without error, it can be resumed from
+			return gameSupervisor.Checkpoint(gameState)
+		},
+	}
+
+	// The bot has its say before each round, the game is over when it forfeits
+	if aBot != nil {
+		frontend.BeforeRound = func() error {
+			missErr := botMove(gameState, aBot)
+			if missErr == nil {
+				return nil
+			}
+
+			if err := errLog.LogMessage(errorlog.LevelWarn, "bot", missErr.Error()); err != nil {
+				return err
+			}
+
+			if errors.Is(missErr, bot.ErrForfeit) {
+				gameState.SetGameInProgress(false)
+			}
+
+			return nil
+		}
+	}
+
+	err = engine.Run(gameState, cfg.Timing.RefreshInterval, stop, frontend)
+}
+
+// warnTrap flashes a message while the snake is trapped, a region smaller than its length,
+// and shades the free cells of the region when the configuration asks for it
+func warnTrap(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, cfg *config.Config,
+	trap *trapWarning) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	region, trapped, err := gameState.TrapRegion()
+	if err != nil {
+		return err
+	}
+
+	// Nothing is shown, nothing is to be erased
+	if !trapped && !trap.trapped {
+		return nil
+	}
+
+	trap.trapped = trapped
+
+	if cfg.Display.TrapShade {
+		if err := shadeTrap(gameState, userInterface, region, trap); err != nil {
+			return err
+		}
+	}
+
+	if trapped && gameState.Round()/trapFlashRounds%2 == 0 {
+		return userInterface.UpdateLn(messageViewTitle, trapMessage)
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, blankMessage)
+}
+
+// shadeTrap draws again the cells shaded the round before, then shades the free cells of region while the snake is trapped
+// The style is the one of the theme displayed, the key handler may change it during the game
+func shadeTrap(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, region []common.Position,
+	trap *trapWarning) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var (
+		trapStyle  = userInterface.Styles().Trap
+		cells      = gameState.Snapshot().Cells
+		spriteList = make([]common.Sprite, 0, len(trap.shaded)+len(region))
+	)
+
+	for _, position := range trap.shaded {
+		spriteList = append(spriteList, common.Sprite{Value: cells[position.X][position.Y], Position: position})
+	}
+
+	trap.shaded = trap.shaded[:0]
+
+	if trap.trapped {
+		for _, position := range region {
+			if cells[position.X][position.Y] == gameboard.FreeSpace {
+				spriteList = append(spriteList,
+					common.Sprite{Value: gameboard.FreeSpace, Position: position, Style: trapStyle})
+				trap.shaded = append(trap.shaded, position)
+			}
+		}
+	}
+
+	return updateView(userInterface, boardViewTitle, spriteList)
+}
+
+func gameOverAnim(userInterface uimanager.UIManagerer, aScreen screen, errLog errorlog.ErrorLogger,
+	errHistory errorhistory.ErrorHistoryer, cfg *config.Config, scrollOver *bool, errChan chan error) {
+	var err error
+
+	defer func() { *scrollOver = true }()
+	defer handleRoutineError(userInterface, aScreen, errLog, errHistory, errChan, &err, "Game Over Anim")
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// These constants could be calculated with the view's available width
+	const (
+		posMax        = 16
+		scrollMessage = "                    GAME OVER!!!              "
+		chunkLength   = 18
+	)
+
+	scrollPosition := 0
+
+	*scrollOver = false
+
+	// The scroll loop
+	ticker := time.NewTicker(cfg.Timing.RefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+
+		chunk := scrollMessage[scrollPosition : scrollPosition+chunkLength]
 
 		if err = userInterface.UpdateLn(messageViewTitle, chunk); err != nil {
 			break
@@ -420,38 +1665,229 @@ func gameOverAnim(userInterface uimanager.UIManagerer, scrollOver *bool, errChan
 	}
 }
 
-func handleRoutineError(userInterface uimanager.UIManagerer, errChan chan error, err *error, title string) {
-	if *err != nil {
-		// For demo purpose only since the error message is truncated
-		// It might not be readable until the user presses CTRL+C
-		err2 := updateErrorView(*err, userInterface, title)
-		// if there is an error from the UpdateErrorView, we report it first
-		if err2 != nil {
-			err = &err2
+// runAttract watches the idle time and plays the demo games, the errors are channeled as the game engine does
+func runAttract(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	cfg *config.Config, scrollOver *bool, attract *attractMode, errChn *error) {
+	defer handlePanic(userInterface, aScreen, errLog, errHistory, errChn)
+
+	errChan := make(chan error)
+
+	go watchIdle(gameState, userInterface, aScreen, errLog, errHistory, gameSupervisor, cfg, scrollOver, attract,
+		errChan)
+	*errChn = <-errChan
+}
+
+// watchIdle starts the demo games once the game sat idle for the attract timeout, they go on until a key is pressed
+func watchIdle(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, gameSupervisor supervisor.Supervisorer,
+	cfg *config.Config, scrollOver *bool, attract *attractMode, errChan chan error) {
+	var err error
+
+	defer handleRoutineError(userInterface, aScreen, errLog, errHistory, errChan, &err, "Attract Mode")
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var demo gamestate.GameStater
+
+	ticker := time.NewTicker(cfg.Timing.RefreshInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		if demo, err = startDemo(gameState, gameSupervisor, cfg, *scrollOver, attract, now); err != nil {
+			break
+		}
+
+		if demo == nil {
+			continue
+		}
+
+		if err = playDemos(gameState, userInterface, aScreen, errLog, errHistory, cfg, attract, demo); err != nil {
+			break
 		}
 	}
-	// The error is channeled out via errChan
-	errChan <- *err
 }
 
-func chunks(str string, lenChunk, nbChunks int) (chunks []string, err error) {
+// startDemo returns a demo game once the game sat idle for the attract timeout: no game played or scrolling,
+// no crashed game to resume and no key pressed. Otherwise it returns nil, the idle time restarts while the game is busy
+func startDemo(gameState gamestate.GameStater, gameSupervisor supervisor.Supervisorer, cfg *config.Config,
+	scrollOver bool, attract *attractMode, now time.Time) (demo gamestate.GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	attract.mutex.Lock()
+	defer attract.mutex.Unlock()
+
+	if gameState.GameInProgress() || !scrollOver || gameSupervisor.CanResume() {
+		attract.idleFrom = now
+		return nil, nil
+	}
+
+	if now.Sub(attract.idleFrom) < cfg.Timing.AttractTimeout {
+		return nil, nil
+	}
+
+	if demo, err = newDemo(gameState.BoardSize()); err != nil {
+		return nil, err
+	}
+
+	attract.demo, attract.stop, attract.stopped = demo, make(chan struct{}), false
+
+	return demo, nil
+}
+
+// newDemo returns a demo game started on a board of boardSize
+func newDemo(boardSize common.Size) (demo gamestate.GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	gameState := gamestate.New()
+	if err := initGame(gameState, boardSize); err != nil {
+		return nil, err
+	}
+
+	if _, err := createObjects(gameState); err != nil {
+		return nil, err
+	}
+
+	gameState.Start()
+
+	return demoGame{GameStater: gameState}, nil
+}
+
+// stopDemo restarts the idle time and stops the demo game, it returns false when no demo is played
+// The caller holds the mutex of attract
+func stopDemo(attract *attractMode) bool {
+	attract.idleFrom = time.Now()
+
+	if attract.demo == nil {
+		return false
+	}
+
+	// The engine stopped by itself once the demo stopped
+	if !attract.stopped {
+		close(attract.stop)
+	}
+
+	attract.stopped = true
+
+	return true
+}
+
+// playDemos plays demo games one after the other until a key stops them, then draws the real game again
+// The keys are ignored until it is drawn
+func playDemos(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, cfg *config.Config, attract *attractMode,
+	demo gamestate.GameStater) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-	// The string str is split in nbChunks strings of size lenChunk
-	chunks = append(chunks, str[0:])
-	i := 0
 
-	for lenChunk >= 0 && len(chunks[i]) > lenChunk {
-		chunks = append(chunks, chunks[i][lenChunk:])
-		chunks[i] = chunks[i][0:lenChunk]
-		i++
+	defer func() {
+		attract.mutex.Lock()
+		defer attract.mutex.Unlock()
+
+		attract.demo, attract.stopped, attract.idleFrom = nil, false, time.Now()
+	}()
+
+	for demo != nil {
+		if err := playDemo(demo, userInterface, aScreen, errLog, errHistory, cfg, attract); err != nil {
+			return err
+		}
+
+		// The snake of the demo died, the next demo starts unless a key was pressed
+		attract.mutex.Lock()
+		demo = nil
+		if !attract.stopped {
+			if demo, err = newDemo(gameState.BoardSize()); err == nil {
+				attract.demo, attract.stop = demo, make(chan struct{})
+			}
+		}
+		attract.mutex.Unlock()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return drawGame(gameState, userInterface, aScreen)
+}
+
+// playDemo draws demo and plays it with the autopilot in the game engine, the marquee scrolls until it is over
+// The engine reports its own errors, a failed demo stops the demos
+func playDemo(demo gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errLog errorlog.ErrorLogger, errHistory errorhistory.ErrorHistoryer, cfg *config.Config,
+	attract *attractMode) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := drawGame(demo, userInterface, aScreen); err != nil {
+		return err
 	}
 
-	// To meet the number of chunks requested, empty strings are added
-	for i := 0; i < nbChunks; i++ {
-		if len(chunks) == i {
-			chunks = append(chunks, "")
+	attract.mutex.Lock()
+	stop := attract.stop
+	attract.mutex.Unlock()
+
+	errChan := make(chan error)
+	go gameEngine(demo, userInterface, aScreen, errLog, errHistory, supervisor.New(0), attract.pilot, cfg, stop,
+		errChan)
+
+	ticker := time.NewTicker(cfg.Timing.RefreshInterval)
+	defer ticker.Stop()
+
+	for position := 0; ; position++ {
+		select {
+		case engineErr := <-errChan:
+			if engineErr != nil {
+				attract.mutex.Lock()
+				attract.stopped = true
+				attract.mutex.Unlock()
+			}
+
+			return nil
+		case <-ticker.C:
+			if err := userInterface.UpdateLn(messageViewTitle, marquee(attractMessage, position)); err != nil {
+				// The engine is stopped before leaving
+				attract.mutex.Lock()
+				stopDemo(attract)
+				attract.mutex.Unlock()
+				<-errChan
+
+				return err
+			}
 		}
 	}
+}
+
+// drawGame draws the whole board of gameState, its score and a blank message
+func drawGame(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := clearView(userInterface, boardViewTitle); err != nil {
+		return err
+	}
+
+	if err := updateView(userInterface, boardViewTitle, gameState.Sprites()); err != nil {
+		return err
+	}
+
+	if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
+		return err
+	}
+
+	return userInterface.UpdateLn(messageViewTitle, blankMessage)
+}
+
+func handleRoutineError(userInterface uimanager.UIManagerer, aScreen screen, errLog errorlog.ErrorLogger,
+	errHistory errorhistory.ErrorHistoryer, errChan chan error, err *error, routine string) {
+	if *err != nil {
+		// The full chain and the stack of a panic are written to the log file
+		errLogged := errLog.LogError(routine, *err)
 
-	return chunks, nil
+		// The error is added to the history displayed by the error view
+		errHistory.Add(routine, *err)
+		err2 := updateErrorView(errHistory, userInterface, aScreen)
+		// if there is an error from the UpdateErrorView, we report it first
+		if err2 != nil {
+			err = &err2
+		} else if errLogged != nil {
+			err = &errLogged
+		}
+	}
+	// The error is channeled out via errChan
+	errChan <- *err
 }
@@ -45,15 +45,38 @@
 // In the case of panics the content is checked, the panic can be an error or a string (cf common.ErrorWrapper test)
 // The errors and unexpected panics can be controlled and logged if needed
 // For example, if an error/panic occurs in the routines gameEngine or gameOverAnim, a red alert is displayed without terminating the program.
+// With the -log option, errors are also written to a log file as JSON entries holding the chain split into frames,
+// and for panics, the recovered value and the stack captured at recovery (cf common.PanicError).
 package main
 
 import (
+	"bufio"
 	"errors"
+	"flag"
+	"fmt"
 	"gosnake/mocks"
+	"gosnake/pkg/autopilot"
+	"gosnake/pkg/bot"
 	"gosnake/pkg/common"
+	"gosnake/pkg/config"
+	"gosnake/pkg/errorhistory"
+	"gosnake/pkg/errorlog"
+	"gosnake/pkg/evolve"
 	"gosnake/pkg/gameboard"
 	"gosnake/pkg/gamestate"
+	"gosnake/pkg/qlearning"
+	"gosnake/pkg/spectator"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/supervisor"
+	"gosnake/pkg/theme"
+	"gosnake/pkg/tournament"
 	"gosnake/pkg/uimanager"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -61,82 +84,38 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_chunks(t *testing.T) {
-	type args struct {
-		str      string
-		lenChunk int
-		nbChunks int
-	}
-	tests := []struct {
-		name       string
-		args       args
-		wantChunks []string
-		wantErr    bool
-	}{
-		{
-			name:       "EmptyString",
-			wantChunks: []string{""},
-		},
-		{
-			name: "EmptyStringChunks3",
-			args: args{
-				nbChunks: 3,
-			},
-			wantChunks: []string{"", "", ""},
-		},
-		{
-			name: "EmptyStringChunks3Len5",
-			args: args{
-				lenChunk: 5,
-				nbChunks: 3,
-			},
-			wantChunks: []string{"", "", ""},
-		},
-		{
-			name: "String10Chunks3Len5",
-			args: args{
-				str:      "0123456789",
-				lenChunk: 5,
-				nbChunks: 3,
-			},
-			wantChunks: []string{"01234", "56789", ""},
-		},
-		{
-			name: "String10Chunks3Len3",
-			args: args{
-				str:      "0123456789",
-				lenChunk: 3,
-				nbChunks: 3,
-			},
-			wantChunks: []string{"012", "345", "678", "9"},
-		},
-		{
-			name: "String10Chunks4Len3",
-			args: args{
-				str:      "0123456789",
-				lenChunk: 3,
-				nbChunks: 4,
-			},
-			wantChunks: []string{"012", "345", "678", "9"},
-		},
-		{
-			name: "String10Chunks3Len-5",
-			args: args{
-				str:      "0123456789",
-				lenChunk: -5,
-				nbChunks: 4,
-			},
-			wantChunks: []string{"0123456789", "", "", ""},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotChunks, err := chunks(tt.args.str, tt.args.lenChunk, tt.args.nbChunks)
-			gotErr := (err != nil)
-			require.Equal(t, tt.wantErr, gotErr)
-			require.Equal(t, tt.wantChunks, gotChunks)
-		})
+// testConfig holds the default settings
+var testConfig = config.Default()
+
+// testScreen places the panel beside the board view of the default settings
+var testScreen = setLayout(testConfig.BoardViewSize(common.Size{Width: testConfig.Board.DefaultSize,
+	Height: testConfig.Board.DefaultSize}))
+
+// testBotEnv makes the test binary play a bot replying its value to each round, instead of running the tests
+const testBotEnv = "GOSNAKE_TEST_BOT"
+
+func TestMain(m *testing.M) {
+	if reply := os.Getenv(testBotEnv); reply != "" {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			fmt.Println(reply)
+		}
+
+		os.Exit(0)
 	}
+
+	os.Exit(m.Run())
+}
+
+// newTestBot starts the test binary as a bot replying reply
+func newTestBot(t *testing.T, reply string, forfeit bool) bot.Boter {
+	t.Setenv(testBotEnv, reply)
+
+	aBot := bot.New(bot.Settings{Command: []string{os.Args[0]}, Timeout: time.Second, Forfeit: forfeit})
+	require.NoError(t, aBot.Start())
+	t.Cleanup(func() { require.NoError(t, aBot.Close()) })
+
+	return aBot
 }
 
 func Test_handleRoutineError(t *testing.T) {
@@ -191,7 +170,7 @@ func Test_handleRoutineError(t *testing.T) {
 			aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(tt.mockDisplayRedLayoutErr)
 			tt.args.userInterface = aUI
 			tt.args.err = &tt.argErr
-			go handleRoutineError(tt.args.userInterface, tt.args.errChan, tt.args.err, tt.args.origin)
+			go handleRoutineError(tt.args.userInterface, testScreen, errorlog.New(), errorhistory.New(), tt.args.errChan, tt.args.err, tt.args.origin)
 			err := <-tt.args.errChan
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -202,6 +181,23 @@ func Test_handleRoutineError(t *testing.T) {
 	}
 }
 
+func Test_handleRoutineErrorLogged(t *testing.T) {
+	errRoutine := errors.New("RoutineError")
+	aUI := &mocks.UIManagerer{}
+	aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(nil)
+	errLog := &mocks.ErrorLogger{}
+	errLog.On("LogError", "Game Engine", errRoutine).Return(nil)
+	errChan := make(chan error)
+
+	errHistory := errorhistory.New()
+	go handleRoutineError(aUI, testScreen, errLog, errHistory, errChan, &errRoutine, "Game Engine")
+	err := <-errChan
+	require.ErrorIs(t, err, errRoutine)
+	errLog.AssertExpectations(t)
+	require.Equal(t, 1, errHistory.Len())
+	require.Equal(t, "Game Engine", errHistory.Entries()[0].Routine)
+}
+
 func Test_gameOverAnim(t *testing.T) {
 	type args struct {
 		userInterface uimanager.UIManagerer
@@ -260,7 +256,8 @@ func Test_gameOverAnim(t *testing.T) {
 			aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(tt.mockUpdateLnErr)
 			aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(tt.mockDisplayRedLayoutErr)
 			tt.args.userInterface = aUI
-			go gameOverAnim(tt.args.userInterface, tt.args.scrollOver, tt.args.errChan)
+			go gameOverAnim(tt.args.userInterface, testScreen, errorlog.New(), errorhistory.New(), &testConfig,
+				tt.args.scrollOver, tt.args.errChan)
 			err := <-tt.args.errChan
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr, err)
@@ -444,6 +441,7 @@ func Test_gameEngine(t *testing.T) {
 			aGmState.On("Play").Return(tt.MockSpriteList, tt.MockPlayErr)
 			aGmState.On("GameInProgress").Return(tt.mockGameInProgess)
 			aGmState.On("SetGameInProgress", false).Return()
+			aGmState.On("Paused").Return(false)
 			aGmState.On("BoardSize").Return(common.Size{
 				Width:  0,
 				Height: 0,
@@ -467,7 +465,8 @@ func Test_gameEngine(t *testing.T) {
 			aUI.On("Update", boardViewTitle, mock.Anything).Return(tt.mockUpdateErr)
 			tt.args.userInterface = aUI
 
-			go gameEngine(tt.args.gameState, tt.args.userInterface, tt.args.errChan)
+			go gameEngine(tt.args.gameState, tt.args.userInterface, testScreen, errorlog.New(), errorhistory.New(),
+				supervisor.New(0), nil, &testConfig, nil, tt.args.errChan)
 			err := <-tt.args.errChan
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr, err)
@@ -631,6 +630,7 @@ func Test_startGame(t *testing.T) {
 			aGmState.On("Play").Return(tt.MockSpriteList, tt.MockPlayErr)
 			aGmState.On("GameInProgress").Return(tt.mockGameInProgess)
 			aGmState.On("SetGameInProgress", false).Return()
+			aGmState.On("Paused").Return(false)
 			aGmState.On("BoardSize").Return(common.Size{
 				Width:  0,
 				Height: 0,
@@ -656,8 +656,8 @@ func Test_startGame(t *testing.T) {
 			// To check that we got out the routines we set errChn to an error
 			*tt.args.errChn = errors.New("Start")
 			*tt.args.scrollOver = true
-			//refreshInterval = time.Millisecond
-			err := startGame(tt.args.gameState, tt.args.userInterface, tt.args.scrollOver, tt.args.errChn)
+			err := startGame(tt.args.gameState, tt.args.userInterface, testScreen, errorlog.New(), errorhistory.New(),
+				supervisor.New(0), nil, &testConfig, tt.args.scrollOver, tt.args.errChn)
 			// Wait for any error to be received from the channel
 			time.Sleep(1 * time.Second)
 			// checks/waits for the gameOverAnim routine to terminate
@@ -678,6 +678,7 @@ func Test_startGame(t *testing.T) {
 
 func Test_toggleBoardViewSize(t *testing.T) {
 	type args struct {
+		board     config.Board
 		boardSize *common.Size
 	}
 	tests := []struct {
@@ -688,34 +689,990 @@ func Test_toggleBoardViewSize(t *testing.T) {
 		{
 			name: "testSize0",
 			args: args{
+				board: testConfig.Board,
 				boardSize: &common.Size{
 					Width:  0,
 					Height: 0,
 				},
 			},
 			wantSize: common.Size{
-				Width:  sizeIncrement,
-				Height: sizeIncrement,
+				Width:  testConfig.Board.SizeIncrement,
+				Height: testConfig.Board.SizeIncrement,
 			},
 		},
 		{
 			name: "testSize40",
 			args: args{
+				board: testConfig.Board,
+				boardSize: &common.Size{
+					Width:  testConfig.Board.DefaultSize,
+					Height: testConfig.Board.DefaultSize,
+				},
+			},
+			wantSize: common.Size{
+				Width:  testConfig.Board.SizeIncrement,
+				Height: testConfig.Board.SizeIncrement,
+			},
+		},
+		{
+			name: "testShorterLastStep",
+			args: args{
+				board: config.Board{
+					DefaultSize:   25,
+					SizeIncrement: 10,
+				},
 				boardSize: &common.Size{
-					Width:  defaultBoardSize,
-					Height: defaultBoardSize,
+					Width:  20,
+					Height: 20,
 				},
 			},
 			wantSize: common.Size{
-				Width:  sizeIncrement,
-				Height: sizeIncrement,
+				Width:  25,
+				Height: 25,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			toggleBoardViewSize(tt.args.boardSize)
+			cfg := config.Default()
+			cfg.Board = tt.args.board
+			toggleBoardViewSize(&cfg, tt.args.boardSize)
 			require.Equal(t, tt.wantSize, *tt.args.boardSize)
 		})
 	}
 }
+
+func Test_parseOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOpts options
+		wantErr  bool
+	}{
+		{
+			name: "TestDefaults",
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestLogFile",
+			args: []string{"-log", "gosnake.log", "-log-level", "debug"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logFile:       "gosnake.log",
+				logLevel:      errorlog.LevelDebug,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestFaultInjection",
+			args: []string{"-fault-injection", "-retries", "1"},
+			wantOpts: options{
+				backend:        uimanager.BackendGocui,
+				logLevel:       errorlog.LevelError,
+				retries:        1,
+				faultInjection: true,
+				maxSpectators:  spectator.DefaultMaxSpectators,
+				botTimeout:     bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestConfigFile",
+			args: []string{"-config", "gosnake.yaml"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				configFile:    "gosnake.yaml",
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestBackend",
+			args: []string{"-backend", "ansi"},
+			wantOpts: options{
+				backend:       uimanager.BackendANSI,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestSpectators",
+			args: []string{"-spectate", ":2323", "-max-spectators", "2"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				spectate:      ":2323",
+				maxSpectators: 2,
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestRecord",
+			args: []string{"-record", "session.cast"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+				record:        "session.cast",
+			},
+		},
+		{
+			name: "TestBot",
+			args: []string{"-bot", "python3 bot.py", "-bot-timeout", "50ms", "-bot-forfeit"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				bot:           "python3 bot.py",
+				botTimeout:    50 * time.Millisecond,
+				botForfeit:    true,
+			},
+		},
+		{
+			name: "TestBattlesnake",
+			args: []string{"-battlesnake", "http://localhost:8000"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				battlesnake:   "http://localhost:8000",
+				botTimeout:    bot.DefaultTimeout,
+			},
+		},
+		{
+			name: "TestAutopilot",
+			args: []string{"-autopilot", "qtable.json"},
+			wantOpts: options{
+				backend:       uimanager.BackendGocui,
+				logLevel:      errorlog.LevelError,
+				retries:       supervisor.DefaultRetries,
+				maxSpectators: spectator.DefaultMaxSpectators,
+				botTimeout:    bot.DefaultTimeout,
+				autopilot:     "qtable.json",
+			},
+		},
+		{
+			name:    "TestTwoBots",
+			args:    []string{"-bot", "python3 bot.py", "-battlesnake", "http://localhost:8000"},
+			wantErr: true,
+		},
+		{
+			name:    "TestBotAutopilot",
+			args:    []string{"-bot", "python3 bot.py", "-autopilot", "qtable.json"},
+			wantErr: true,
+		},
+		{
+			name:    "TestNoSpectators",
+			args:    []string{"-spectate", ":2323", "-max-spectators", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "TestNegativeRetries",
+			args:    []string{"-retries", "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "TestInvalidLevel",
+			args:    []string{"-log-level", "verbose"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOpts, err := parseOptions(tt.args)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if !tt.wantErr {
+				require.Equal(t, tt.wantOpts, gotOpts)
+			}
+		})
+	}
+}
+
+func Test_showPause(t *testing.T) {
+	aUI := &mocks.UIManagerer{}
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+
+	require.NoError(t, showPause(aUI, true))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, pausedMessage)
+
+	require.NoError(t, showPause(aUI, false))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, blankMessage)
+}
+
+func Test_toggleAutopilot(t *testing.T) {
+	aUI := &mocks.UIManagerer{}
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+
+	// The bots can't be switched off
+	require.NoError(t, toggleAutopilot(newTestBot(t, "up", false), aUI))
+	aUI.AssertNotCalled(t, "UpdateLn", messageViewTitle, mock.Anything)
+
+	aBot, err := newAutopilot("")
+	require.NoError(t, err)
+
+	require.NoError(t, toggleAutopilot(aBot, aUI))
+	require.True(t, aBot.(autopilot.Autopiloter).Enabled())
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, autopilotOnMessage)
+
+	require.NoError(t, toggleAutopilot(aBot, aUI))
+	require.False(t, aBot.(autopilot.Autopiloter).Enabled())
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, autopilotOffMessage)
+}
+
+func Test_warnTrap(t *testing.T) {
+	// restore returns a game of 4x4 with the snake of body, from the tail to the head
+	restore := func(round int, body []common.Position) gamestate.GameStater {
+		checkpoint := common.GameCheckpoint{Round: round, Board: common.BoardCheckpoint{
+			Size:           common.Size{Width: 4, Height: 4},
+			Board:          make([][]rune, 4),
+			SnakeBody:      body,
+			SnakeDirection: common.Direction{DX: 0, DY: -1},
+		}}
+
+		for x := range checkpoint.Board.Board {
+			checkpoint.Board.Board[x] = []rune(strings.Repeat(string(gameboard.FreeSpace), 4))
+		}
+
+		for _, position := range body {
+			checkpoint.Board.Board[position.X][position.Y] = gameboard.SnakePart
+		}
+
+		gameState := gamestate.New()
+		require.NoError(t, gameState.Restore(checkpoint))
+
+		return gameState
+	}
+
+	// The head and the cells (1,1) and (2,1) are walled in, the tail is away from them
+	trapped := []common.Position{{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 2},
+		{X: 3, Y: 1}, {X: 3, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2},
+		{X: 1, Y: 2}, {X: 2, Y: 2}}
+	free := []common.Position{{X: 1, Y: 1}, {X: 1, Y: 2}}
+
+	cfg := config.Default()
+	cfg.Display.TrapShade = true
+	trapStyle := func() common.Style {
+		aTheme, err := theme.Get(cfg.Display.Theme)
+		require.NoError(t, err)
+		return aTheme.Trap
+	}()
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+	aUI.On("Update", boardViewTitle, mock.Anything).Return(nil)
+	aUI.On("Styles").Return(uimanager.Styles{Trap: trapStyle})
+
+	var trap trapWarning
+
+	// Nothing is shown while the snake is free
+	require.NoError(t, warnTrap(restore(0, free), aUI, &cfg, &trap))
+	aUI.AssertNotCalled(t, "UpdateLn", messageViewTitle, mock.Anything)
+	aUI.AssertNotCalled(t, "Update", boardViewTitle, mock.Anything)
+
+	// The message flashes and the region is shaded
+	require.NoError(t, warnTrap(restore(0, trapped), aUI, &cfg, &trap))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, trapMessage)
+	aUI.AssertCalled(t, "Update", boardViewTitle, []common.Sprite{
+		{Value: gameboard.FreeSpace, Position: common.Position{X: 2, Y: 1}, Style: trapStyle},
+		{Value: gameboard.FreeSpace, Position: common.Position{X: 1, Y: 1}, Style: trapStyle},
+	})
+	require.True(t, trap.trapped)
+
+	require.NoError(t, warnTrap(restore(trapFlashRounds, trapped), aUI, &cfg, &trap))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, blankMessage)
+
+	// Once out, the message is erased and the cells are drawn as they are
+	aUI.Calls = nil
+	require.NoError(t, warnTrap(restore(0, free), aUI, &cfg, &trap))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, blankMessage)
+	aUI.AssertCalled(t, "Update", boardViewTitle, []common.Sprite{
+		{Value: gameboard.FreeSpace, Position: common.Position{X: 2, Y: 1}},
+		{Value: gameboard.SnakePart, Position: common.Position{X: 1, Y: 1}},
+	})
+	require.False(t, trap.trapped)
+	require.Empty(t, trap.shaded)
+
+	// Without shading, only the message is shown
+	cfg.Display.TrapShade = false
+	aUI.Calls = nil
+	require.NoError(t, warnTrap(restore(0, trapped), aUI, &cfg, &trap))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, trapMessage)
+	aUI.AssertNotCalled(t, "Update", boardViewTitle, mock.Anything)
+}
+
+func Test_startDemo(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	cfg := config.Default()
+	cfg.Timing.AttractTimeout = time.Second
+
+	var (
+		now            = time.Now()
+		gameSupervisor = supervisor.New(0)
+		attract        = attractMode{idleFrom: now}
+	)
+
+	// The game hasn't sat idle long enough
+	demo, err := startDemo(gameState, gameSupervisor, &cfg, true, &attract, now.Add(cfg.Timing.AttractTimeout/2))
+	require.NoError(t, err)
+	require.Nil(t, demo)
+
+	// The idle time restarts while the game over scrolls, or while a game is played
+	demo, err = startDemo(gameState, gameSupervisor, &cfg, false, &attract, now.Add(2*time.Second))
+	require.NoError(t, err)
+	require.Nil(t, demo)
+	require.Equal(t, now.Add(2*time.Second), attract.idleFrom)
+
+	gameState.Start()
+	demo, err = startDemo(gameState, gameSupervisor, &cfg, true, &attract, now.Add(4*time.Second))
+	require.NoError(t, err)
+	require.Nil(t, demo)
+	gameState.SetGameInProgress(false)
+
+	// A demo game starts on a board of the same size, the game is left as it is
+	demo, err = startDemo(gameState, gameSupervisor, &cfg, true, &attract, now.Add(5*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, demo)
+	require.Equal(t, demo, attract.demo)
+	require.True(t, demo.GameInProgress())
+	require.Equal(t, gameState.BoardSize(), demo.BoardSize())
+	require.False(t, gameState.GameInProgress())
+
+	// A key stops its engine, a second key doesn't close the channel again
+	require.True(t, stopDemo(&attract))
+	require.True(t, attract.stopped)
+	_, open := <-attract.stop
+	require.False(t, open)
+	require.True(t, stopDemo(&attract))
+
+	attract.demo = nil
+	require.False(t, stopDemo(&attract))
+}
+
+func Test_playDemos(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("ClearView", boardViewTitle).Return(nil)
+	aUI.On("Update", boardViewTitle, mock.Anything).Return(nil)
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+
+	cfg := config.Default()
+	cfg.Timing.AttractTimeout = time.Millisecond
+
+	pilot, err := newDemoPilot("")
+	require.NoError(t, err)
+
+	attract := attractMode{pilot: pilot}
+	demo, err := startDemo(gameState, supervisor.New(0), &cfg, true, &attract, time.Now())
+	require.NoError(t, err)
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- playDemos(gameState, aUI, testScreen, errorlog.New(), errorhistory.New(), &cfg, &attract, demo)
+	}()
+
+	// The autopilot plays the demo while the marquee scrolls
+	for i := 0; i < 50 && demo.Round() < 3; i++ {
+		time.Sleep(cfg.Timing.RefreshInterval)
+	}
+	require.GreaterOrEqual(t, demo.Round(), 3)
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, marquee(attractMessage, 1))
+
+	// A key stops the demo, the game is drawn again as it was
+	attract.mutex.Lock()
+	require.True(t, stopDemo(&attract))
+	attract.mutex.Unlock()
+	require.NoError(t, <-errChan)
+
+	require.Nil(t, attract.demo)
+	require.False(t, attract.stopped)
+	require.Equal(t, 0, gameState.Round())
+	require.False(t, gameState.GameInProgress())
+	aUI.AssertCalled(t, "Update", boardViewTitle, gameState.Sprites())
+	require.Equal(t, blankMessage, aUI.Calls[len(aUI.Calls)-1].Arguments.Get(1))
+}
+
+func Test_exportGIF(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	cfg := config.Default()
+	cfg.Export.Directory = t.TempDir()
+	errHistory := errorhistory.New()
+	aUI := &mocks.UIManagerer{}
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+	aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(nil)
+
+	// A game not played yet has nothing to export
+	gameState.Start()
+	require.NoError(t, exportGIF(gameState, aUI, testScreen, errorlog.New(), errHistory, &cfg))
+	aUI.AssertNotCalled(t, "UpdateLn", messageViewTitle, mock.Anything)
+
+	for i := 0; i < 3; i++ {
+		_, err := gameState.Play()
+		require.NoError(t, err)
+	}
+
+	gameState.SetGameInProgress(false)
+	require.NoError(t, exportGIF(gameState, aUI, testScreen, errorlog.New(), errHistory, &cfg))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, gifSavedMessage)
+	files, err := filepath.Glob(filepath.Join(cfg.Export.Directory, "gosnake-*.gif"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// A failed export is shown with the errors, the game goes on
+	cfg.Export.Directory = filepath.Join(cfg.Export.Directory, "missing")
+	require.NoError(t, exportGIF(gameState, aUI, testScreen, errorlog.New(), errHistory, &cfg))
+	require.Equal(t, 1, errHistory.Len())
+	aUI.AssertCalled(t, "DisplayRedLayout", errorViewTitle, mock.Anything)
+}
+
+func Test_saveSnapshot(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	cfg := config.Default()
+	cfg.Export.Directory = t.TempDir()
+	errHistory := errorhistory.New()
+	aUI := &mocks.UIManagerer{}
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+	aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(nil)
+
+	// A file per format
+	require.NoError(t, saveSnapshot(gameState, aUI, testScreen, errorlog.New(), errHistory, &cfg))
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, snapshotSavedMessage)
+	for _, format := range gameboard.SnapshotFormats {
+		files, err := filepath.Glob(filepath.Join(cfg.Export.Directory, "gosnake-*."+format))
+		require.NoError(t, err)
+		require.Len(t, files, 1, format)
+	}
+
+	// A failed snapshot is shown with the errors, the game goes on
+	cfg.Export.Directory = filepath.Join(cfg.Export.Directory, "missing")
+	require.NoError(t, saveSnapshot(gameState, aUI, testScreen, errorlog.New(), errHistory, &cfg))
+	require.Equal(t, 1, errHistory.Len())
+	aUI.AssertCalled(t, "DisplayRedLayout", errorViewTitle, mock.Anything)
+}
+
+func Test_gameEnginePaused(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	// The rounds are counted as the engine draws them, the game state is read once the engine stops
+	var rounds int32
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("Update", boardViewTitle, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		atomic.AddInt32(&rounds, 1)
+	})
+
+	// No round is played while the game is paused
+	gameState.Start()
+	gameState.SetPaused(true)
+	errChan := make(chan error)
+	go gameEngine(gameState, aUI, testScreen, errorlog.New(), errorhistory.New(), supervisor.New(0), nil, &testConfig,
+		nil, errChan)
+	time.Sleep(3 * testConfig.Timing.RefreshInterval)
+	require.Equal(t, int32(0), atomic.LoadInt32(&rounds))
+
+	gameState.SetPaused(false)
+	for i := 0; i < 50 && atomic.LoadInt32(&rounds) == 0; i++ {
+		time.Sleep(testConfig.Timing.RefreshInterval)
+	}
+	require.Greater(t, atomic.LoadInt32(&rounds), int32(0))
+
+	gameState.SetGameInProgress(false)
+	require.NoError(t, <-errChan)
+	require.Greater(t, gameState.Round(), 0)
+}
+
+func Test_configCommand(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "gosnake", "config.yaml")
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantErrType error
+		wantErr     bool
+	}{
+		{
+			name:    "TestUnknownCommand",
+			args:    []string{"show"},
+			wantErr: true,
+		},
+		{
+			name: "TestInit",
+			args: []string{"init", "-config", configFile},
+		},
+		{
+			name:        "TestInitExisting",
+			args:        []string{"init", "-config", configFile},
+			wantErrType: config.ErrConfigExists,
+			wantErr:     true,
+		},
+		{
+			name: "TestInitForced",
+			args: []string{"init", "-config", configFile, "-force"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := configCommand(tt.args)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			}
+		})
+	}
+
+	// The file written is read back as the default settings
+	cfg, err := loadConfig(configFile)
+	require.NoError(t, err)
+	require.Equal(t, config.Default(), cfg)
+
+	// A file given on the command line must exist
+	_, err = loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_resumeGame(t *testing.T) {
+	waitFor := func(condition func() bool) {
+		for i := 0; i < 50 && !condition(); i++ {
+			time.Sleep(testConfig.Timing.RefreshInterval)
+		}
+		require.True(t, condition())
+	}
+
+	gameState := gamestate.NewFaultInjector(gamestate.New())
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	// The rounds are counted as the engine draws them, the game state is read while the engine is stopped
+	var (
+		rounds        int32
+		restoredRound int
+	)
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("ClearView", boardViewTitle).Return(nil).Run(func(mock.Arguments) {
+		restoredRound = gameState.Round() // The board is cleared once restored, before the engine is started
+	})
+	aUI.On("Update", boardViewTitle, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		atomic.AddInt32(&rounds, 1)
+	})
+	aUI.On("UpdateLn", messageViewTitle, mock.Anything).Return(nil)
+	aUI.On("DisplayRedLayout", errorViewTitle, mock.Anything).Return(nil)
+
+	var (
+		errHistory     = errorhistory.New()
+		gameSupervisor = supervisor.New(1)
+		scrollOver     = true
+		errChn         error
+	)
+
+	// The game crashes after a few rounds
+	require.NoError(t, startGame(gameState, aUI, testScreen, errorlog.New(), errHistory, gameSupervisor, nil,
+		&testConfig, &scrollOver, &errChn))
+	waitFor(func() bool { return atomic.LoadInt32(&rounds) > 2 })
+	gameState.InjectPanic()
+	waitFor(gameSupervisor.Failed)
+	require.False(t, gameState.GameInProgress())
+	require.True(t, gameSupervisor.CanResume())
+	require.ErrorIs(t, errChn, gamestate.ErrInjectedFault)
+	aUI.AssertCalled(t, "UpdateLn", messageViewTitle, " ENTER resumes (1)")
+
+	// It is resumed from the last checkpoint
+	crashRound, crashRounds := gameState.Round(), atomic.LoadInt32(&rounds)
+	require.NoError(t, resumeGame(gameState, aUI, testScreen, errorlog.New(), errHistory, gameSupervisor, nil,
+		&testConfig, &scrollOver, &errChn))
+	require.True(t, gameState.GameInProgress())
+	require.LessOrEqual(t, restoredRound, crashRound)
+	aUI.AssertCalled(t, "ClearView", boardViewTitle)
+
+	// The budget is exhausted after the second crash, the restored board is drawn before the rounds
+	waitFor(func() bool { return atomic.LoadInt32(&rounds) > crashRounds+2 })
+	gameState.InjectError()
+	waitFor(gameSupervisor.Failed)
+	require.False(t, gameSupervisor.CanResume())
+	require.Equal(t, 2, errHistory.Len())
+	require.ErrorIs(t, resumeGame(gameState, aUI, testScreen, errorlog.New(), errHistory, gameSupervisor, nil,
+		&testConfig, &scrollOver, &errChn), supervisor.ErrRetriesExhausted)
+}
+
+func Test_webCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantErrType error
+	}{
+		{
+			name:        "TestHelp",
+			args:        []string{"-h"},
+			wantErrType: flag.ErrHelp,
+		},
+		{
+			name:        "TestMissingConfig",
+			args:        []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")},
+			wantErrType: os.ErrNotExist,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webCommand(tt.args, errorlog.New())
+			require.ErrorIs(t, err, tt.wantErrType)
+		})
+	}
+}
+
+func Test_tournamentCommand(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "report.csv")
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantOutput  string
+		wantErrType error
+	}{
+		{
+			name:       "TestReport",
+			args:       []string{"-strategies", "greedy", "-sizes", "8, 12", "-games", "4", "-csv", csvFile},
+			wantOutput: "4 games per strategy and board size, seeds 1 to 4",
+		},
+		{
+			name:        "TestHelp",
+			args:        []string{"-h"},
+			wantErrType: flag.ErrHelp,
+		},
+		{
+			name:        "TestInvalidSizes",
+			args:        []string{"-sizes", "10;20"},
+			wantErrType: errInvalidBoardSizes,
+		},
+		{
+			name:        "TestUnknownStrategy",
+			args:        []string{"-strategies", "psychic", "-games", "1"},
+			wantErrType: tournament.ErrInvalidSettings,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			err := tournamentCommand(tt.args, &output)
+			require.ErrorIs(t, err, tt.wantErrType)
+			require.Contains(t, output.String(), tt.wantOutput)
+		})
+	}
+
+	// A line per board size after the header
+	data, err := os.ReadFile(csvFile)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 3)
+}
+
+func Test_webURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{
+			name:    "TestLocalhost",
+			address: "localhost:8080",
+			want:    "http://localhost:8080",
+		},
+		{
+			name:    "TestAnyHost",
+			address: ":8080",
+			want:    "http://localhost:8080",
+		},
+		{
+			name:    "TestIPv6",
+			address: "[::1]:9000",
+			want:    "http://[::1]:9000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, webURL(tt.address))
+		})
+	}
+}
+
+func Test_trainCommand(t *testing.T) {
+	tableFile, invalidFile := filepath.Join(t.TempDir(), "qtable.json"), filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a table"), 0o600))
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantOutput  string
+		wantErrType error
+	}{
+		{
+			name:       "TestTrain",
+			args:       []string{"-episodes", "20", "-size", "8", "-out", tableFile},
+			wantOutput: "episodes 19 to 20: mean score",
+		},
+		{
+			name:       "TestGoOn",
+			args:       []string{"-episodes", "5", "-size", "8", "-epsilon", "0", "-in", tableFile, "-out", tableFile},
+			wantOutput: "episodes 5 to 5: mean score",
+		},
+		{
+			name:        "TestHelp",
+			args:        []string{"-h"},
+			wantErrType: flag.ErrHelp,
+		},
+		{
+			name:        "TestInvalidSettings",
+			args:        []string{"-alpha", "0"},
+			wantErrType: qlearning.ErrInvalidSettings,
+		},
+		{
+			name:        "TestInvalidTable",
+			args:        []string{"-in", invalidFile},
+			wantErrType: qlearning.ErrInvalidTable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			err := trainCommand(tt.args, &output)
+			require.ErrorIs(t, err, tt.wantErrType)
+			require.Contains(t, output.String(), tt.wantOutput)
+		})
+	}
+
+	// The autopilot plays the table written
+	aBot, err := newAutopilot(tableFile)
+	require.NoError(t, err)
+	require.Implements(t, (*autopilot.Autopiloter)(nil), aBot)
+
+	_, err = newAutopilot(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+
+	// The strategies are played by name
+	aBot, err = newAutopilot("mcts")
+	require.NoError(t, err)
+	require.Implements(t, (*autopilot.Autopiloter)(nil), aBot)
+}
+
+func Test_evolveCommand(t *testing.T) {
+	var (
+		profileFile = filepath.Join(t.TempDir(), "bot.json")
+		checkpoint  = filepath.Join(t.TempDir(), "population.json")
+		invalidFile = filepath.Join(t.TempDir(), "invalid.json")
+		small       = []string{"-population", "4", "-games", "1", "-size", "6", "-max-rounds", "100"}
+	)
+
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not a population"), 0o600))
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantOutput  string
+		wantErrType error
+	}{
+		{
+			name:       "TestEvolve",
+			args:       append([]string{"-generations", "2", "-checkpoint", checkpoint, "-out", profileFile}, small...),
+			wantOutput: "generation 1: best fitness",
+		},
+		{
+			name:       "TestResume",
+			args:       append([]string{"-generations", "3", "-checkpoint", checkpoint, "-out", profileFile}, small...),
+			wantOutput: "resuming from generation 1",
+		},
+		{
+			name:        "TestHelp",
+			args:        []string{"-h"},
+			wantErrType: flag.ErrHelp,
+		},
+		{
+			name:        "TestInvalidSettings",
+			args:        []string{"-population", "1"},
+			wantErrType: evolve.ErrInvalidSettings,
+		},
+		{
+			name:        "TestInvalidCheckpoint",
+			args:        []string{"-checkpoint", invalidFile},
+			wantErrType: evolve.ErrInvalidCheckpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			err := evolveCommand(tt.args, &output)
+			require.ErrorIs(t, err, tt.wantErrType)
+			require.Contains(t, output.String(), tt.wantOutput)
+		})
+	}
+
+	profile, err := evolve.LoadProfile(profileFile)
+	require.NoError(t, err)
+	require.Equal(t, 2, profile.Generation)
+
+	// The autopilot plays the profile written
+	aBot, err := newAutopilot(profileFile)
+	require.NoError(t, err)
+	require.Implements(t, (*autopilot.Autopiloter)(nil), aBot)
+
+	_, err = newAutopilot(invalidFile)
+	require.ErrorIs(t, err, qlearning.ErrInvalidTable)
+}
+
+func Test_gameEngineBot(t *testing.T) {
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("Update", boardViewTitle, mock.Anything).Return(nil)
+
+	newGame := func() gamestate.GameStater {
+		gameState := gamestate.New()
+		require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+		_, err := gameState.CreateObjects()
+		require.NoError(t, err)
+		gameState.Start()
+
+		return gameState
+	}
+
+	// The snake starts to the right, the bot turns it up
+	gameState := newGame()
+	errChan := make(chan error)
+	go gameEngine(gameState, aUI, testScreen, errorlog.New(), errorhistory.New(), supervisor.New(0),
+		newTestBot(t, "up", false), &testConfig, nil, errChan)
+	time.Sleep(3 * testConfig.Timing.RefreshInterval)
+	gameState.SetGameInProgress(false)
+	require.NoError(t, <-errChan)
+
+	checkpoint, err := gameState.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, strategy.Up, checkpoint.Board.SnakeDirection)
+
+	// A malformed reply loses the game before its first round
+	gameState = newGame()
+	go gameEngine(gameState, aUI, testScreen, errorlog.New(), errorhistory.New(), supervisor.New(0),
+		newTestBot(t, "north", true), &testConfig, nil, errChan)
+	require.NoError(t, <-errChan)
+	require.False(t, gameState.GameInProgress())
+	require.Equal(t, 0, gameState.Round())
+}
+
+func Test_botCommand(t *testing.T) {
+	// The Battlesnake server goes up and counts the games ended
+	var ends int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/move":
+			w.Write([]byte(`{"move":"up"}`))
+		case "/end":
+			atomic.AddInt32(&ends, 1)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		reply       string
+		args        []string
+		wantOutput  string
+		wantErrType error
+	}{
+		{
+			name:       "TestGames",
+			reply:      "up",
+			args:       []string{"-games", "2", "-seed", "5", "-size", "10", "-max-rounds", "30", os.Args[0]},
+			wantOutput: "seed 6: score ",
+		},
+		{
+			name:       "TestRoundLimit",
+			reply:      "up",
+			args:       []string{"-size", "10", "-max-rounds", "30", os.Args[0]},
+			wantOutput: " in 30 rounds, round limit",
+		},
+		{
+			name:       "TestMalformedReply",
+			reply:      "north",
+			args:       []string{"-size", "10", "-max-rounds", "3", os.Args[0]},
+			wantOutput: "malformed reply from the bot: \"north\"",
+		},
+		{
+			name:       "TestForfeit",
+			reply:      "north",
+			args:       []string{"-forfeit", os.Args[0]},
+			wantOutput: "seed 1: score 0 in 0 rounds, forfeit",
+		},
+		{
+			name:       "TestBattlesnake",
+			args:       []string{"-battlesnake", server.URL, "-games", "2", "-size", "10", "-max-rounds", "30"},
+			wantOutput: "seed 2: score 0 in 30 rounds, round limit",
+		},
+		{
+			name:        "TestNoCommand",
+			args:        []string{"-games", "2"},
+			wantErrType: bot.ErrNoCommand,
+		},
+		{
+			name:        "TestTwoBots",
+			args:        []string{"-battlesnake", server.URL, os.Args[0]},
+			wantErrType: errTwoBots,
+		},
+		{
+			name:        "TestInvalidSize",
+			args:        []string{"-size", "2", os.Args[0]},
+			wantErrType: errInvalidBotGames,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(testBotEnv, tt.reply)
+
+			var output strings.Builder
+			err := botCommand(tt.args, &output)
+			require.ErrorIs(t, err, tt.wantErrType)
+			require.Contains(t, output.String(), tt.wantOutput)
+		})
+	}
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&ends))
+}
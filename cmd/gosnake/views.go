@@ -4,61 +4,108 @@ import (
 	"errors"
 	"fmt"
 	"gosnake/pkg/common"
+	"gosnake/pkg/config"
+	"gosnake/pkg/errorhistory"
 	"gosnake/pkg/gameboard"
 	"gosnake/pkg/gamestate"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/theme"
 	"gosnake/pkg/uimanager"
 	"strconv"
 )
 
 const (
-	boardViewTitle   = "boardView"
-	scoreViewTitle   = "scoreView"
-	messageViewTitle = "messageView"
-	errorViewTitle   = "errorView"
-	helpViewTitle    = "helpView"
-	gameFrameTitle   = "frameView"
-	panelViewTitle   = "panelView"
+	boardViewTitle    = "boardView"
+	scoreViewTitle    = "scoreView"
+	messageViewTitle  = "messageView"
+	errorViewTitle    = "errorView"
+	helpViewTitle     = "helpView"
+	gameFrameTitle    = "frameView"
+	panelViewTitle    = "panelView"
+	errorOverlayTitle = "errorOverlay"
 )
 
+// Messages of the message view, blankMessage erases it
+const (
+	blankMessage         = "                  "
+	pausedMessage        = "      PAUSED      "
+	gifSavedMessage      = "    GIF SAVED     "
+	snapshotSavedMessage = "  SNAPSHOT SAVED  "
+	autopilotOnMessage   = "   AUTOPILOT ON   "
+	autopilotOffMessage  = "   AUTOPILOT OFF  "
+	trapMessage          = "  DANGER: TRAPPED "
+)
+
+// attractMessage scrolls in the message view while the autopilot plays a demo game, cf marquee
+const attractMessage = "PRESS SPACE"
+
+// trapFlashRounds is the number of rounds the trap message stays on, then off, while the snake is trapped
+const trapFlashRounds = 2
+
 const (
 	leftMost       = 0
-	rightPanel     = 43
-	maxX           = 63
+	panelWidth     = 20
 	topMost        = 0
 	topMessageView = 11
 	topErrorView   = 14
 	topHelpView    = 27
-	maxY           = 41
 )
 
-func createViews(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, boardSize common.Size) (err error) {
+// screen is where the panel stands, it grows with the board view, cf setLayout
+type screen struct {
+	rightPanel     int
+	maxX           int
+	maxY           int
+	spectatorCount func() int // The number of spectators shown in the score view, nil without -spectate
+}
+
+// setLayout places the panel beside a board view of boardViewSize terminal cells
+func setLayout(boardViewSize common.Size) (aScreen screen) {
+	aScreen.rightPanel, aScreen.maxY = 43, 41
+
+	// The board view takes its frame and an extra column
+	if boardViewSize.Width+3 > aScreen.rightPanel {
+		aScreen.rightPanel = boardViewSize.Width + 3
+	}
+
+	if boardViewSize.Height+1 > aScreen.maxY {
+		aScreen.maxY = boardViewSize.Height + 1
+	}
+
+	aScreen.maxX = aScreen.rightPanel + panelWidth
+
+	return aScreen
+}
+
+func createViews(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	cfg *config.Config, boardSize common.Size) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	if err := createGameFrame(userInterface); err != nil {
+	if err := createGameFrame(userInterface, aScreen); err != nil {
 		return err
 	}
 
-	if err := createPanelView(userInterface); err != nil {
+	if err := createPanelView(userInterface, aScreen); err != nil {
 		return err
 	}
 
-	if err := createErrorView(userInterface); err != nil {
+	if err := createErrorView(userInterface, aScreen); err != nil {
 		return err
 	}
 
-	if err := createHelpView(userInterface); err != nil {
+	if err := createHelpView(userInterface, aScreen, cfg); err != nil {
 		return err
 	}
 
-	if err := createScoreView(gameState, userInterface); err != nil {
+	if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
 		return err
 	}
 
-	if err := createMessageView(userInterface); err != nil {
+	if err := createMessageView(userInterface, aScreen); err != nil {
 		return err
 	}
 
-	if err := createBoardView(userInterface, boardSize); err != nil {
+	if err := createBoardView(userInterface, cfg.BoardViewSize(boardSize)); err != nil {
 		return err
 	}
 
@@ -71,53 +118,54 @@ func clearView(userInterface uimanager.UIManagerer, viewName string) (err error)
 	return userInterface.ClearView(viewName)
 }
 
-func createGameFrame(userInterface uimanager.UIManagerer) (err error) {
+func createGameFrame(userInterface uimanager.UIManagerer, aScreen screen) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var gameFramePosition = common.ViewPosition{
 		X1: leftMost,
 		Y1: topMost,
-		X2: maxX,
-		Y2: maxY,
+		X2: aScreen.maxX,
+		Y2: aScreen.maxY,
 	}
 
 	return userInterface.SetView(gameFrameTitle, gameFramePosition)
 }
 
-func createPanelView(userInterface uimanager.UIManagerer) (err error) {
+func createPanelView(userInterface uimanager.UIManagerer, aScreen screen) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var panelViewPosition = common.ViewPosition{
-		X1: rightPanel,
+		X1: aScreen.rightPanel,
 		Y1: topMost,
-		X2: maxX,
-		Y2: maxY,
+		X2: aScreen.maxX,
+		Y2: aScreen.maxY,
 	}
 
 	return userInterface.SetView(panelViewTitle, panelViewPosition)
 }
 
-func createErrorView(userInterface uimanager.UIManagerer) (err error) {
+func createErrorView(userInterface uimanager.UIManagerer, aScreen screen) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var errorViewPosition = common.ViewPosition{
-		X1: rightPanel,
+		X1: aScreen.rightPanel,
 		Y1: topErrorView,
-		X2: maxX,
+		X2: aScreen.maxX,
 		Y2: topHelpView - 1,
 	}
 
 	return userInterface.SetView(errorViewTitle, errorViewPosition)
 }
 
-func createBoardView(userInterface uimanager.UIManagerer, boardSize common.Size) (err error) {
+// createBoardView sizes the board view for viewSize terminal cells
+func createBoardView(userInterface uimanager.UIManagerer, viewSize common.Size) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var gameBoardPosition = common.ViewPosition{
 		X1: leftMost,
 		Y1: topMost,
-		X2: boardSize.Width + 1,
-		Y2: boardSize.Height + 1,
+		X2: viewSize.Width + 1,
+		Y2: viewSize.Height + 1,
 	}
 	// takes the frame into account and avoids scrolling issues (!workaround)
 	gameBoardPosition.X2++
@@ -125,59 +173,92 @@ func createBoardView(userInterface uimanager.UIManagerer, boardSize common.Size)
 	return userInterface.SetView(boardViewTitle, gameBoardPosition)
 }
 
-func createMessageView(userInterface uimanager.UIManagerer) (err error) {
+func createMessageView(userInterface uimanager.UIManagerer, aScreen screen) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var messageViewPosition = common.ViewPosition{
-		X1: rightPanel,
+		X1: aScreen.rightPanel,
 		Y1: topMessageView,
-		X2: maxX,
+		X2: aScreen.maxX,
 		Y2: topErrorView - 1,
 	}
 
 	return userInterface.SetView(messageViewTitle, messageViewPosition)
 }
 
-func createHelpView(userInterface uimanager.UIManagerer) (err error) {
+func createHelpView(userInterface uimanager.UIManagerer, aScreen screen, cfg *config.Config) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var helpViewPosition = common.ViewPosition{
-		X1: rightPanel,
+		X1: aScreen.rightPanel,
 		Y1: topHelpView,
-		X2: maxX,
-		Y2: maxY,
+		X2: aScreen.maxX,
+		Y2: aScreen.maxY,
 	}
 
 	if err := userInterface.SetView(helpViewTitle, helpViewPosition); err != nil {
 		return err
 	}
 
-	helpViewLayout := []string{
-		"  The Snake Game",
-		"GRAB the * CANDIES",
-		"",
-		"select board size",
-		"   with ENTER",
-		"",
-		" SPACEBAR to start",
-		"",
-		"Keys:  BOTTOM, UP",
-		"      LEFT, RIGHT",
-		"",
-		"",
-		"  Ctrl+C to Quit",
+	helpViewLayout, err := helpLayout(cfg)
+	if err != nil {
+		return err
 	}
 
 	return userInterface.SetViewLayout(helpViewTitle, helpViewLayout)
 }
 
-func createScoreView(gameState gamestate.GameStater, userInterface uimanager.UIManagerer) (err error) {
+func helpLayout(cfg *config.Config) (layout []string, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The width of the view without its frame
+	const width = panelWidth - 2
+
+	bindings, err := cfg.Controls.Table()
+	if err != nil {
+		return layout, err
+	}
+
+	// The moves are named after the keys of the presets: arrow keys, WASD, ZQSD...
+	moves := "arrow keys"
+	if bindings.Label(keybindings.ActionUp) != "UP" {
+		moves = bindings.Label(keybindings.ActionUp) + bindings.Label(keybindings.ActionLeft) +
+			bindings.Label(keybindings.ActionDown) + bindings.Label(keybindings.ActionRight)
+	}
+
+	layout = []string{
+		"  The Snake Game",
+		fmt.Sprintf("GRAB the %c CANDIES", cfg.Glyphs.Candy),
+		fmt.Sprintf("%s: board size", bindings.Label(keybindings.ActionResize)),
+		fmt.Sprintf("%s to start", bindings.Label(keybindings.ActionStart)),
+		fmt.Sprintf("%s to pause", bindings.Label(keybindings.ActionPause)),
+		fmt.Sprintf("%s: autopilot", bindings.Label(keybindings.ActionAutopilot)),
+		fmt.Sprintf("%s: color theme", bindings.Label(keybindings.ActionTheme)),
+		fmt.Sprintf("%s: save a GIF", bindings.Label(keybindings.ActionExportGIF)),
+		"Move: " + moves,
+		fmt.Sprintf("%s: snapshot", bindings.Label(keybindings.ActionSnapshot)),
+		fmt.Sprintf("Errors: %s/%s", bindings.Label(keybindings.ActionScrollUp),
+			bindings.Label(keybindings.ActionScrollDown)),
+		fmt.Sprintf("%s: error details", bindings.Label(keybindings.ActionErrorDetails)),
+		fmt.Sprintf("%s to Quit", bindings.Label(keybindings.ActionQuit)),
+	}
+
+	// Long key names are cut
+	for i := range layout {
+		layout[i] = uimanager.TruncateText(layout[i], width)
+	}
+
+	return layout, nil
+}
+
+func createScoreView(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	spectatorCount func() int) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	var scoreViewPosition = common.ViewPosition{
-		X1: rightPanel,
+		X1: aScreen.rightPanel,
 		Y1: topMost,
-		X2: maxX,
+		X2: aScreen.maxX,
 		Y2: topMessageView - 1,
 	}
 
@@ -210,9 +291,21 @@ func createScoreView(gameState gamestate.GameStater, userInterface uimanager.UIM
 		snkSize = strconv.Itoa(snakeSize)
 	}
 
+	// The margin is dropped for the boards of 100 cells and more
+	boardLine := " GAME BOARD " + boardSize
+	if len(boardLine) > panelWidth-2 {
+		boardLine = boardLine[1:]
+	}
+
+	// The spectators are counted under the board size
+	spectatorLine := ""
+	if spectatorCount != nil {
+		spectatorLine = "SPECTATORS: " + strconv.Itoa(spectatorCount())
+	}
+
 	scoreViewLayout := []string{
-		" GAME BOARD " + boardSize,
-		"",
+		boardLine,
+		spectatorLine,
 		"ROUND: " + strconv.Itoa(gameState.Round()),
 		"",
 		"CANDIES: " + strconv.Itoa(gameState.Score()),
@@ -225,32 +318,162 @@ func createScoreView(gameState gamestate.GameStater, userInterface uimanager.UIM
 	return userInterface.SetViewLayout(scoreViewTitle, scoreViewLayout)
 }
 
-func updateErrorView(errMsg error, userInterface uimanager.UIManagerer, title string) (err error) {
+func createErrorOverlay(userInterface uimanager.UIManagerer, aScreen screen) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var errorOverlayPosition = common.ViewPosition{
+		X1: leftMost,
+		Y1: topMost,
+		X2: aScreen.maxX,
+		Y2: aScreen.maxY,
+	}
+
+	return userInterface.SetView(errorOverlayTitle, errorOverlayPosition)
+}
+
+func updateErrorView(errHistory errorhistory.ErrorHistoryer, userInterface uimanager.UIManagerer,
+	aScreen screen) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	const (
-		errorViewWidth = maxX - rightPanel - 2
-		nbErrorLines   = 4
+	// The sizes exclude the frames
+	var (
+		errorViewSize = common.Size{
+			Width:  aScreen.maxX - aScreen.rightPanel - 2,
+			Height: topHelpView - topErrorView - 2,
+		}
+		errorOverlaySize = common.Size{
+			Width:  aScreen.maxX - leftMost - 2,
+			Height: aScreen.maxY - topMost - 1,
+		}
 	)
 
-	str := errMsg.Error()
-	chunks, err := chunks(str, errorViewWidth, nbErrorLines)
+	// The error view stays blank until an error occurs
+	if errHistory.Len() == 0 {
+		return nil
+	}
+
+	if !errHistory.Expanded() {
+		return userInterface.DisplayRedLayout(errorViewTitle, errHistory.Layout(errorViewSize))
+	}
+
+	// The full chains are displayed over the whole game
+	if err := createErrorOverlay(userInterface, aScreen); err != nil {
+		return err
+	}
+
+	return userInterface.DisplayRedLayout(errorOverlayTitle, errHistory.Layout(errorOverlaySize))
+}
+
+// setErrorKeys shows the keys bound to the error panel in its title lines
+func setErrorKeys(errHistory errorhistory.ErrorHistoryer, cfg *config.Config) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
+	bindings, err := cfg.Controls.Table()
 	if err != nil {
 		return err
 	}
 
-	return userInterface.DisplayRedLayout(errorViewTitle, []string{
-		"",
-		"",
-		"   Program Error",
-		"",
-		title,
-		"     crashed",
-		"",
-		chunks[0],
-		chunks[1],
-		chunks[2],
-		chunks[3],
+	errHistory.SetKeys(errorhistory.Keys{
+		ScrollUp:   bindings.Label(keybindings.ActionScrollUp),
+		ScrollDown: bindings.Label(keybindings.ActionScrollDown),
+		Details:    bindings.Label(keybindings.ActionErrorDetails),
+		Close:      bindings.Label(keybindings.ActionCloseDetails),
+	})
+
+	return nil
+}
+
+func toggleErrorOverlay(errHistory errorhistory.ErrorHistoryer, userInterface uimanager.UIManagerer, aScreen screen,
+	expanded bool) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if errHistory.Len() == 0 || errHistory.Expanded() == expanded {
+		return nil
+	}
+
+	errHistory.SetExpanded(expanded)
+
+	// Dismissing the overlay shows the game again
+	if !expanded {
+		if err := userInterface.DeleteView(errorOverlayTitle); err != nil {
+			return err
+		}
+	}
+
+	return updateErrorView(errHistory, userInterface, aScreen)
+}
+
+func scrollErrorView(errHistory errorhistory.ErrorHistoryer, userInterface uimanager.UIManagerer, aScreen screen,
+	delta int) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	errHistory.Scroll(delta)
+
+	return updateErrorView(errHistory, userInterface, aScreen)
+}
+
+func setTheme(userInterface uimanager.UIManagerer, themeName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aTheme, err := theme.Get(themeName)
+	if err != nil {
+		return err
+	}
+
+	userInterface.SetTheme(uimanager.Styles{
+		Screen: aTheme.Panel,
+		Views: map[string]common.Style{
+			gameFrameTitle:   aTheme.Panel,
+			panelViewTitle:   aTheme.Panel,
+			scoreViewTitle:   aTheme.Panel,
+			messageViewTitle: aTheme.Panel,
+			errorViewTitle:   aTheme.Panel,
+			helpViewTitle:    aTheme.Panel,
+			boardViewTitle:   aTheme.Board,
+		},
+		Cells: aTheme.Cells(),
+		Alert: aTheme.Alert,
+		Trap:  aTheme.Trap,
 	})
+
+	return nil
+}
+
+func nextTheme(gameState gamestate.GameStater, userInterface uimanager.UIManagerer, aScreen screen,
+	errHistory errorhistory.ErrorHistoryer, cfg *config.Config) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	cfg.Display.Theme = theme.Next(cfg.Display.Theme)
+
+	if err := setTheme(userInterface, cfg.Display.Theme); err != nil {
+		return err
+	}
+
+	// The text keeps the style it was written with, the views are written again
+	if err := clearView(userInterface, boardViewTitle); err != nil {
+		return err
+	}
+
+	if err := updateView(userInterface, boardViewTitle, gameState.Sprites()); err != nil {
+		return err
+	}
+
+	if err := createScoreView(gameState, userInterface, aScreen, aScreen.spectatorCount); err != nil {
+		return err
+	}
+
+	if err := createHelpView(userInterface, aScreen, cfg); err != nil {
+		return err
+	}
+
+	return updateErrorView(errHistory, userInterface, aScreen)
+}
+
+// marquee returns what the message view shows at step position of message scrolling from right to left
+// The message enters on the right side of a blank view and comes back once it left
+func marquee(message string, position int) string {
+	track := blankMessage + message
+	position %= len(track)
+
+	return (track + track)[position : position+len(blankMessage)]
 }
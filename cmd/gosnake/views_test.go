@@ -1,28 +1,242 @@
 package main
 
 import (
+	"errors"
+	"gosnake/mocks"
+	"gosnake/pkg/common"
+	"gosnake/pkg/config"
+	"gosnake/pkg/errorhistory"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/theme"
 	"gosnake/pkg/uimanager"
 	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_updateErrorView(t *testing.T) {
-	type args struct {
-		errMsg        error
-		userInterface uimanager.UIManagerer
-		origin        string
+	tests := []struct {
+		name                    string
+		errMsgs                 []error
+		expanded                bool
+		mockDisplayRedLayoutErr error
+		mockSetViewErr          error
+		wantView                string // The view expected to be displayed, none if empty
+		wantErrType             error
+	}{
+		{
+			name: "TestNoError",
+		},
+		{
+			name:     "TestErrorView",
+			errMsgs:  []error{errors.New("an error")},
+			wantView: errorViewTitle,
+		},
+		{
+			name:     "TestErrorOverlay",
+			errMsgs:  []error{errors.New("an error")},
+			expanded: true,
+			wantView: errorOverlayTitle,
+		},
+		{
+			name:           "TestSetViewError",
+			errMsgs:        []error{errors.New("an error")},
+			expanded:       true,
+			mockSetViewErr: errors.New("SetViewError"),
+			wantErrType:    errors.New("SetViewError"),
+		},
+		{
+			name:                    "TestDisplayError",
+			errMsgs:                 []error{errors.New("an error")},
+			mockDisplayRedLayoutErr: errors.New("DisplayError"),
+			wantView:                errorViewTitle,
+			wantErrType:             errors.New("DisplayError"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errHistory := errorhistory.New()
+			for _, errMsg := range tt.errMsgs {
+				errHistory.Add("Game Engine", errMsg)
+			}
+			errHistory.SetExpanded(tt.expanded)
+
+			aUI := &mocks.UIManagerer{}
+			aUI.On("SetView", errorOverlayTitle, mock.Anything).Return(tt.mockSetViewErr)
+			aUI.On("DisplayRedLayout", mock.Anything, mock.Anything).Return(tt.mockDisplayRedLayoutErr)
+
+			err := updateErrorView(errHistory, aUI, testScreen)
+			if tt.wantErrType != nil {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErrType.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantView == "" {
+				aUI.AssertNotCalled(t, "DisplayRedLayout", mock.Anything, mock.Anything)
+			} else {
+				aUI.AssertCalled(t, "DisplayRedLayout", tt.wantView, mock.Anything)
+			}
+		})
 	}
+}
+
+func Test_toggleErrorOverlay(t *testing.T) {
+	errHistory := errorhistory.New()
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", errorOverlayTitle, mock.Anything).Return(nil)
+	aUI.On("DeleteView", errorOverlayTitle).Return(nil)
+	aUI.On("DisplayRedLayout", mock.Anything, mock.Anything).Return(nil)
+
+	// Nothing to expand without errors
+	require.NoError(t, toggleErrorOverlay(errHistory, aUI, testScreen, true))
+	require.False(t, errHistory.Expanded())
+
+	errHistory.Add("Game Engine", errors.New("an error"))
+	require.NoError(t, toggleErrorOverlay(errHistory, aUI, testScreen, true))
+	require.True(t, errHistory.Expanded())
+	aUI.AssertCalled(t, "DisplayRedLayout", errorOverlayTitle, mock.Anything)
+
+	require.NoError(t, toggleErrorOverlay(errHistory, aUI, testScreen, false))
+	require.False(t, errHistory.Expanded())
+	aUI.AssertCalled(t, "DeleteView", errorOverlayTitle)
+	aUI.AssertCalled(t, "DisplayRedLayout", errorViewTitle, mock.Anything)
+}
+
+func Test_helpLayout(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		preset    string
+		candy     config.Glyph
+		wantLines []string
 	}{
-		// TODO: Add test cases.
+		{
+			name:   "TestDefault",
+			preset: keybindings.DefaultPreset,
+			candy:  '*',
+			wantLines: []string{"GRAB the * CANDIES", "SPACE to start", "P to pause", "O: autopilot",
+				"Move: arrow keys"},
+		},
+		{
+			name:      "TestZQSD",
+			preset:    "zqsd",
+			candy:     'o',
+			wantLines: []string{"GRAB the o CANDIES", "Move: ZQSD", "CTRL+C to Quit"},
+		},
+		{
+			name:      "TestWideCandy", // The line is cut after the last column
+			preset:    keybindings.DefaultPreset,
+			candy:     '🍎',
+			wantLines: []string{"GRAB the 🍎 CANDIE"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := updateErrorView(tt.args.errMsg, tt.args.userInterface, tt.args.origin); (err != nil) != tt.wantErr {
-				t.Errorf("updateErrorView() error = %v, wantErr %v", err, tt.wantErr)
+			cfg := config.Default()
+			cfg.Controls.Preset = tt.preset
+			cfg.Glyphs.Candy = tt.candy
+			layout, err := helpLayout(&cfg)
+			require.NoError(t, err)
+			for _, line := range tt.wantLines {
+				require.Contains(t, layout, line)
+			}
+			for _, line := range layout {
+				require.LessOrEqual(t, uimanager.TextWidth(line), testScreen.maxX-testScreen.rightPanel-2)
 			}
 		})
 	}
 }
+
+func Test_setErrorKeys(t *testing.T) {
+	cfg := config.Default()
+	cfg.Controls.Bindings = keybindings.Bindings{
+		keybindings.ActionScrollUp:   {uimanager.KeyRune('k')},
+		keybindings.ActionScrollDown: {uimanager.KeyRune('j')},
+	}
+
+	errHistory := errorhistory.New()
+	require.NoError(t, setErrorKeys(errHistory, &cfg))
+	require.Equal(t, "0 K/J TAB", errHistory.Layout(common.Size{Width: 18, Height: 2})[1])
+
+	errHistory.SetExpanded(true)
+	require.Equal(t, " K/J to scroll, TAB or ESC to close", errHistory.Layout(common.Size{Width: 60, Height: 2})[1])
+}
+
+func Test_nextTheme(t *testing.T) {
+	cfg := config.Default()
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetTheme", mock.Anything).Return()
+	aUI.On("ClearView", boardViewTitle).Return(nil)
+	aUI.On("Update", boardViewTitle, gameState.Sprites()).Return(nil)
+	aUI.On("SetView", mock.Anything, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", mock.Anything, mock.Anything).Return(nil)
+
+	// The views are written again with the styles of the next theme
+	require.NoError(t, nextTheme(gameState, aUI, testScreen, errorhistory.New(), &cfg))
+	require.Equal(t, theme.Next(theme.DefaultTheme), cfg.Display.Theme)
+	wantTheme, err := theme.Get(cfg.Display.Theme)
+	require.NoError(t, err)
+	aUI.AssertCalled(t, "SetTheme", mock.MatchedBy(func(styles uimanager.Styles) bool {
+		return styles.Alert == wantTheme.Alert && styles.Views[boardViewTitle] == wantTheme.Board
+	}))
+	aUI.AssertCalled(t, "Update", boardViewTitle, gameState.Sprites())
+}
+
+func Test_setLayout(t *testing.T) {
+	// The default layout has room for a 40x40 board view
+	aScreen := setLayout(common.Size{Width: 20, Height: 10})
+	require.Equal(t, screen{rightPanel: 43, maxX: 63, maxY: 41}, aScreen)
+
+	// The panel moves beside larger views
+	aScreen = setLayout(common.Size{Width: 80, Height: 40})
+	require.Equal(t, screen{rightPanel: 83, maxX: 103, maxY: 41}, aScreen)
+	require.Equal(t, panelWidth, aScreen.maxX-aScreen.rightPanel)
+}
+
+func Test_createScoreViewSpectators(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+
+	aUI := &mocks.UIManagerer{}
+	aUI.On("SetView", scoreViewTitle, mock.Anything).Return(nil)
+	aUI.On("SetViewLayout", scoreViewTitle, mock.Anything).Return(nil)
+
+	// Without spectators the line stays blank
+	require.NoError(t, createScoreView(gameState, aUI, testScreen, nil))
+	aUI.AssertCalled(t, "SetViewLayout", scoreViewTitle, mock.MatchedBy(func(layout []string) bool {
+		return layout[1] == ""
+	}))
+
+	require.NoError(t, createScoreView(gameState, aUI, testScreen, func() int { return 3 }))
+	aUI.AssertCalled(t, "SetViewLayout", scoreViewTitle, mock.MatchedBy(func(layout []string) bool {
+		return layout[1] == "SPECTATORS: 3"
+	}))
+}
+
+func Test_marquee(t *testing.T) {
+	tests := []struct {
+		name     string
+		position int
+		want     string
+	}{
+		{name: "TestBlank", position: 0, want: blankMessage},
+		{name: "TestEntering", position: 4, want: "              PRES"},
+		{name: "TestLeaving", position: 24, want: "SPACE             "},
+		{name: "TestAgain", position: len(blankMessage) + len(attractMessage), want: blankMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := marquee(attractMessage, tt.position)
+			require.Equal(t, tt.want, got)
+			require.Len(t, got, len(blankMessage))
+		})
+	}
+}
@@ -2,6 +2,7 @@
 
 package mocks
 
+import candy "gosnake/pkg/candy"
 import common "gosnake/pkg/common"
 import mock "github.com/stretchr/testify/mock"
 
@@ -24,6 +25,22 @@ func (_m *Candyer) Alive() bool {
 	return r0
 }
 
+// Clone provides a mock function with given fields:
+func (_m *Candyer) Clone() candy.Candyer {
+	ret := _m.Called()
+
+	var r0 candy.Candyer
+	if rf, ok := ret.Get(0).(func() candy.Candyer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(candy.Candyer)
+		}
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: newPosition
 func (_m *Candyer) Init(newPosition common.Position) {
 	_m.Called(newPosition)
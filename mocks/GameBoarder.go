@@ -3,6 +3,7 @@
 package mocks
 
 import common "gosnake/pkg/common"
+import grid "gosnake/pkg/grid"
 
 import mock "github.com/stretchr/testify/mock"
 
@@ -53,6 +54,27 @@ func (_m *GameBoarder) CandyPosition() common.Position {
 	return r0
 }
 
+// Checkpoint provides a mock function with given fields:
+func (_m *GameBoarder) Checkpoint() (common.BoardCheckpoint, error) {
+	ret := _m.Called()
+
+	var r0 common.BoardCheckpoint
+	if rf, ok := ret.Get(0).(func() common.BoardCheckpoint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.BoardCheckpoint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateCandy provides a mock function with given fields:
 func (_m *GameBoarder) CreateCandy() (common.Sprite, error) {
 	ret := _m.Called()
@@ -95,6 +117,38 @@ func (_m *GameBoarder) CreateSnake(position common.Position, direction common.Di
 	return r0, r1
 }
 
+// FloodFill provides a mock function with given fields: start
+func (_m *GameBoarder) FloodFill(start common.Position) []common.Position {
+	ret := _m.Called(start)
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func(common.Position) []common.Position); ok {
+		r0 = rf(start)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	return r0
+}
+
+// Grid provides a mock function with given fields:
+func (_m *GameBoarder) Grid() grid.Grider {
+	ret := _m.Called()
+
+	var r0 grid.Grider
+	if rf, ok := ret.Get(0).(func() grid.Grider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(grid.Grider)
+		}
+	}
+
+	return r0
+}
+
 // InitGameBoard provides a mock function with given fields: size
 func (_m *GameBoarder) InitGameBoard(size common.Size) error {
 	ret := _m.Called(size)
@@ -188,11 +242,44 @@ func (_m *GameBoarder) RandomFreePosition() (common.Position, error) {
 	return r0, r1
 }
 
+// Reachable provides a mock function with given fields: from, to
+func (_m *GameBoarder) Reachable(from common.Position, to common.Position) bool {
+	ret := _m.Called(from, to)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(common.Position, common.Position) bool); ok {
+		r0 = rf(from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // RemoveCandy provides a mock function with given fields:
 func (_m *GameBoarder) RemoveCandy() {
 	_m.Called()
 }
 
+// Restore provides a mock function with given fields: checkpoint
+func (_m *GameBoarder) Restore(checkpoint common.BoardCheckpoint) error {
+	ret := _m.Called(checkpoint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.BoardCheckpoint) error); ok {
+		r0 = rf(checkpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSeed provides a mock function with given fields: seed
+func (_m *GameBoarder) SetSeed(seed int64) {
+	_m.Called(seed)
+}
+
 // SetSnakeDirection provides a mock function with given fields: direction
 func (_m *GameBoarder) SetSnakeDirection(direction common.Direction) {
 	_m.Called(direction)
@@ -239,3 +326,63 @@ func (_m *GameBoarder) SnakeSize() (int, error) {
 
 	return r0, r1
 }
+
+// Snapshot provides a mock function with given fields:
+func (_m *GameBoarder) Snapshot() common.BoardSnapshot {
+	ret := _m.Called()
+
+	var r0 common.BoardSnapshot
+	if rf, ok := ret.Get(0).(func() common.BoardSnapshot); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.BoardSnapshot)
+	}
+
+	return r0
+}
+
+// Sprites provides a mock function with given fields:
+func (_m *GameBoarder) Sprites() []common.Sprite {
+	ret := _m.Called()
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func() []common.Sprite); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	return r0
+}
+
+// TrapRegion provides a mock function with given fields:
+func (_m *GameBoarder) TrapRegion() ([]common.Position, bool, error) {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
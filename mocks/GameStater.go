@@ -25,6 +25,27 @@ func (_m *GameStater) BoardSize() common.Size {
 	return r0
 }
 
+// Checkpoint provides a mock function with given fields:
+func (_m *GameStater) Checkpoint() (common.GameCheckpoint, error) {
+	ret := _m.Called()
+
+	var r0 common.GameCheckpoint
+	if rf, ok := ret.Get(0).(func() common.GameCheckpoint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.GameCheckpoint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateObjects provides a mock function with given fields:
 func (_m *GameStater) CreateObjects() ([]common.Sprite, error) {
 	ret := _m.Called()
@@ -90,6 +111,20 @@ func (_m *GameStater) HighScore() int {
 	return r0
 }
 
+// History provides a mock function with given fields:
+func (_m *GameStater) History() common.GameHistory {
+	ret := _m.Called()
+
+	var r0 common.GameHistory
+	if rf, ok := ret.Get(0).(func() common.GameHistory); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.GameHistory)
+	}
+
+	return r0
+}
+
 // InitBoard provides a mock function with given fields: size
 func (_m *GameStater) InitBoard(size common.Size) error {
 	ret := _m.Called(size)
@@ -124,6 +159,20 @@ func (_m *GameStater) MoveUp() {
 	_m.Called()
 }
 
+// Paused provides a mock function with given fields:
+func (_m *GameStater) Paused() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Play provides a mock function with given fields:
 func (_m *GameStater) Play() ([]common.Sprite, error) {
 	ret := _m.Called()
@@ -147,6 +196,25 @@ func (_m *GameStater) Play() ([]common.Sprite, error) {
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: checkpoint
+func (_m *GameStater) Restore(checkpoint common.GameCheckpoint) error {
+	ret := _m.Called(checkpoint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.GameCheckpoint) error); ok {
+		r0 = rf(checkpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resume provides a mock function with given fields:
+func (_m *GameStater) Resume() {
+	_m.Called()
+}
+
 // Round provides a mock function with given fields:
 func (_m *GameStater) Round() int {
 	ret := _m.Called()
@@ -180,6 +248,16 @@ func (_m *GameStater) SetGameInProgress(_a0 bool) {
 	_m.Called(_a0)
 }
 
+// SetPaused provides a mock function with given fields: _a0
+func (_m *GameStater) SetPaused(_a0 bool) {
+	_m.Called(_a0)
+}
+
+// SetSeed provides a mock function with given fields: seed
+func (_m *GameStater) SetSeed(seed int64) {
+	_m.Called(seed)
+}
+
 // SnakePosition provides a mock function with given fields:
 func (_m *GameStater) SnakePosition() (common.Position, error) {
 	ret := _m.Called()
@@ -222,7 +300,67 @@ func (_m *GameStater) SnakeSize() (int, error) {
 	return r0, r1
 }
 
+// Snapshot provides a mock function with given fields:
+func (_m *GameStater) Snapshot() common.BoardSnapshot {
+	ret := _m.Called()
+
+	var r0 common.BoardSnapshot
+	if rf, ok := ret.Get(0).(func() common.BoardSnapshot); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.BoardSnapshot)
+	}
+
+	return r0
+}
+
+// Sprites provides a mock function with given fields:
+func (_m *GameStater) Sprites() []common.Sprite {
+	ret := _m.Called()
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func() []common.Sprite); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *GameStater) Start() {
 	_m.Called()
 }
+
+// TrapRegion provides a mock function with given fields:
+func (_m *GameStater) TrapRegion() ([]common.Position, bool, error) {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
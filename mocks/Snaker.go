@@ -4,12 +4,59 @@ package mocks
 
 import common "gosnake/pkg/common"
 import mock "github.com/stretchr/testify/mock"
+import snake "gosnake/pkg/snake"
 
 // Snaker is an autogenerated mock type for the Snaker type
 type Snaker struct {
 	mock.Mock
 }
 
+// Body provides a mock function with given fields:
+func (_m *Snaker) Body() []common.Position {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	return r0
+}
+
+// Clone provides a mock function with given fields:
+func (_m *Snaker) Clone() snake.Snaker {
+	ret := _m.Called()
+
+	var r0 snake.Snaker
+	if rf, ok := ret.Get(0).(func() snake.Snaker); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(snake.Snaker)
+		}
+	}
+
+	return r0
+}
+
+// Direction provides a mock function with given fields:
+func (_m *Snaker) Direction() common.Direction {
+	ret := _m.Called()
+
+	var r0 common.Direction
+	if rf, ok := ret.Get(0).(func() common.Direction); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.Direction)
+	}
+
+	return r0
+}
+
 // GrowTo provides a mock function with given fields: newPosition
 func (_m *Snaker) GrowTo(newPosition common.Position) error {
 	ret := _m.Called(newPosition)
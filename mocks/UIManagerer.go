@@ -30,6 +30,20 @@ func (_m *UIManagerer) Close() {
 	_m.Called()
 }
 
+// DeleteView provides a mock function with given fields: viewName
+func (_m *UIManagerer) DeleteView(viewName string) error {
+	ret := _m.Called(viewName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(viewName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DisplayRedLayout provides a mock function with given fields: viewName, layout
 func (_m *UIManagerer) DisplayRedLayout(viewName string, layout []string) error {
 	ret := _m.Called(viewName, layout)
@@ -58,13 +72,13 @@ func (_m *UIManagerer) MainLoop() error {
 	return r0
 }
 
-// OnKeyPress provides a mock function with given fields: fn
-func (_m *UIManagerer) OnKeyPress(fn func(uimanager.Key) error) error {
-	ret := _m.Called(fn)
+// OnKeyPress provides a mock function with given fields: keys, fn
+func (_m *UIManagerer) OnKeyPress(keys []uimanager.Key, fn func(uimanager.Key) error) error {
+	ret := _m.Called(keys, fn)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(func(uimanager.Key) error) error); ok {
-		r0 = rf(fn)
+	if rf, ok := ret.Get(0).(func([]uimanager.Key, func(uimanager.Key) error) error); ok {
+		r0 = rf(keys, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -72,13 +86,13 @@ func (_m *UIManagerer) OnKeyPress(fn func(uimanager.Key) error) error {
 	return r0
 }
 
-// OpenUIManager provides a mock function with given fields:
-func (_m *UIManagerer) OpenUIManager() error {
-	ret := _m.Called()
+// OpenUIManager provides a mock function with given fields: colorMode
+func (_m *UIManagerer) OpenUIManager(colorMode uimanager.ColorMode) error {
+	ret := _m.Called(colorMode)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(uimanager.ColorMode) error); ok {
+		r0 = rf(colorMode)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -100,6 +114,21 @@ func (_m *UIManagerer) Quit() error {
 	return r0
 }
 
+// SetBoardMode provides a mock function with given fields: boardMode
+func (_m *UIManagerer) SetBoardMode(boardMode uimanager.BoardMode) {
+	_m.Called(boardMode)
+}
+
+// SetGlyphs provides a mock function with given fields: glyphs
+func (_m *UIManagerer) SetGlyphs(glyphs map[rune]rune) {
+	_m.Called(glyphs)
+}
+
+// SetTheme provides a mock function with given fields: styles
+func (_m *UIManagerer) SetTheme(styles uimanager.Styles) {
+	_m.Called(styles)
+}
+
 // SetView provides a mock function with given fields: viewName, position
 func (_m *UIManagerer) SetView(viewName string, position common.ViewPosition) error {
 	ret := _m.Called(viewName, position)
@@ -128,6 +157,20 @@ func (_m *UIManagerer) SetViewLayout(viewName string, layout []string) error {
 	return r0
 }
 
+// Styles provides a mock function with given fields:
+func (_m *UIManagerer) Styles() uimanager.Styles {
+	ret := _m.Called()
+
+	var r0 uimanager.Styles
+	if rf, ok := ret.Get(0).(func() uimanager.Styles); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uimanager.Styles)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: viewName, spriteList
 func (_m *UIManagerer) Update(viewName string, spriteList []common.Sprite) error {
 	ret := _m.Called(viewName, spriteList)
@@ -0,0 +1,163 @@
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"gosnake/pkg/common"
+	"gosnake/pkg/uimanager"
+	"math"
+	"os"
+	"time"
+)
+
+// version of the asciicast format written
+const version = 2
+
+// screenOpen starts the recording on a cleared screen with the cursor hidden, as the game displays it
+const screenOpen = "\x1b[2J\x1b[?25l"
+
+// header is the first line of an asciicast v2 file
+type header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorderer is the interface for recorder
+type Recorderer interface {
+	Start(fileName string, size common.Size, frame func() []string, interval time.Duration) (err error)
+	Close() (err error)
+}
+
+// recorder writes the frames of the screen to an asciicast v2 file, each line after the header
+// is an event [seconds since the start, "o", the escape sequences written to the terminal]
+type recorder struct {
+	file    *os.File
+	output  *bufio.Writer
+	encoder *json.Encoder
+	frame   func() []string
+	start   time.Time
+	screen  []string // The rows last recorded, the others are recorded again
+	err     error    // The error which stopped the recording routine
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// New returns an instance of recorder
+func New() Recorderer {
+	return &recorder{}
+}
+
+// Start creates the file and records the rows returned by frame every interval, when they change
+// The timestamps are the times of the ticks
+func (aRecorder *recorder) Start(fileName string, size common.Size, frame func() []string,
+	interval time.Duration) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	aRecorder.output = bufio.NewWriter(file)
+	aRecorder.encoder = json.NewEncoder(aRecorder.output)
+	aRecorder.encoder.SetEscapeHTML(false)
+	aRecorder.frame = frame
+	aRecorder.start = time.Now()
+	aRecorder.stop, aRecorder.stopped = make(chan struct{}), make(chan struct{})
+
+	if err := aRecorder.encoder.Encode(header{
+		Version:   version,
+		Width:     size.Width,
+		Height:    size.Height,
+		Timestamp: aRecorder.start.Unix(),
+		Title:     "GoSnake",
+		Env:       map[string]string{"TERM": os.Getenv("TERM")},
+	}); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := aRecorder.record(aRecorder.start, screenOpen); err != nil {
+		file.Close()
+		return err
+	}
+
+	// The file is only closed by Close once the recording has started
+	aRecorder.file = file
+
+	go aRecorder.run(interval)
+
+	return nil
+}
+
+func (aRecorder *recorder) run(interval time.Duration) {
+	defer close(aRecorder.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-aRecorder.stop:
+			return
+		case tick := <-ticker.C:
+			if aRecorder.err = aRecorder.recordFrame(tick); aRecorder.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// recordFrame records the rows which changed since the last frame
+func (aRecorder *recorder) recordFrame(at time.Time) error {
+	rows := aRecorder.frame()
+
+	changes := uimanager.FrameChanges(aRecorder.screen, rows)
+	if changes == "" {
+		return nil
+	}
+
+	aRecorder.screen = rows
+
+	return aRecorder.record(at, changes)
+}
+
+func (aRecorder *recorder) record(at time.Time, data string) error {
+	// asciinema writes the times with a precision of a microsecond
+	seconds := math.Round(at.Sub(aRecorder.start).Seconds()*1e6) / 1e6
+
+	return aRecorder.encoder.Encode([]interface{}{seconds, "o", data})
+}
+
+// Close records the last frame and closes the file
+func (aRecorder *recorder) Close() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aRecorder.file == nil {
+		return nil
+	}
+
+	// The routine is stopped before the file is written
+	close(aRecorder.stop)
+	<-aRecorder.stopped
+
+	if err = aRecorder.err; err == nil {
+		err = aRecorder.recordFrame(time.Now())
+	}
+
+	if flushErr := aRecorder.output.Flush(); err == nil {
+		err = flushErr
+	}
+
+	if closeErr := aRecorder.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	aRecorder.file = nil
+
+	return err
+}
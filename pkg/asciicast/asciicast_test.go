@@ -0,0 +1,86 @@
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"gosnake/pkg/common"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		rows     = []string{"┌──┐", "│S │", "└──┘"}
+		fileName = filepath.Join(t.TempDir(), "session.cast")
+	)
+
+	frame := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return rows
+	}
+
+	aRecorder := New()
+	require.NoError(t, aRecorder.Start(fileName, common.Size{Width: 63, Height: 42}, frame, 5*time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+
+	mutex.Lock()
+	rows = []string{"┌──┐", "│ S│", "└──┘"}
+	mutex.Unlock()
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, aRecorder.Close())
+	require.NoError(t, aRecorder.Close())
+
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	// The header gives the size of the terminal
+	require.True(t, scanner.Scan())
+	var aHeader header
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &aHeader))
+	require.Equal(t, 2, aHeader.Version)
+	require.Equal(t, 63, aHeader.Width)
+	require.Equal(t, 42, aHeader.Height)
+
+	// Then the output events, the unchanged frames aren't recorded
+	var (
+		events   [][]interface{}
+		previous float64
+	)
+
+	for scanner.Scan() {
+		var event []interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+		require.Equal(t, "o", event[1])
+		require.GreaterOrEqual(t, event[0].(float64), previous)
+		previous = event[0].(float64)
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 3)
+	require.Equal(t, screenOpen, events[0][2])
+	require.Contains(t, events[1][2], "┌──┐")
+	require.Contains(t, events[2][2], "│ S│")
+	require.False(t, strings.Contains(events[2][2].(string), "┌"))
+	require.Greater(t, events[2][0].(float64), events[1][0].(float64))
+}
+
+func TestRecorderInvalidFile(t *testing.T) {
+	aRecorder := New()
+	err := aRecorder.Start(filepath.Join(t.TempDir(), "missing", "session.cast"), common.Size{}, nil, time.Second)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.NoError(t, aRecorder.Close())
+}
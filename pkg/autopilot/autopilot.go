@@ -0,0 +1,77 @@
+package autopilot
+
+import (
+	"gosnake/pkg/bot"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"sync"
+)
+
+// Autopiloter is the interface for autopilot, a bot which can be switched on and off during the game
+type Autopiloter interface {
+	bot.Boter
+	Toggle() (enabled bool)
+	Enabled() bool
+}
+
+// autopilot steers the snake with a strategy while it is enabled, the keys steer it otherwise
+type autopilot struct {
+	strategy strategy.Strategyer
+	enabled  bool
+	mutex    sync.Mutex
+}
+
+// New returns an instance of autopilot playing aStrategy, it starts disabled
+func New(aStrategy strategy.Strategyer) Autopiloter {
+	return &autopilot{strategy: aStrategy}
+}
+
+// Start does nothing, the strategy is played in process
+func (anAutopilot *autopilot) Start() error {
+	return nil
+}
+
+// NextMove returns the move of the strategy, or no direction while the autopilot is disabled
+func (anAutopilot *autopilot) NextMove(gameState gamestate.GameStater) (direction common.Direction, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if !anAutopilot.Enabled() {
+		return direction, nil
+	}
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return direction, err
+	}
+
+	return anAutopilot.strategy.NextMove(state), nil
+}
+
+// EndGame does nothing, the autopilot stays as it is for the next game
+func (anAutopilot *autopilot) EndGame(gamestate.GameStater) error {
+	return nil
+}
+
+// Close does nothing
+func (anAutopilot *autopilot) Close() error {
+	return nil
+}
+
+// Toggle switches the autopilot on or off
+func (anAutopilot *autopilot) Toggle() (enabled bool) {
+	anAutopilot.mutex.Lock()
+	defer anAutopilot.mutex.Unlock()
+
+	anAutopilot.enabled = !anAutopilot.enabled
+
+	return anAutopilot.enabled
+}
+
+// Enabled tells whether the autopilot steers the snake
+func (anAutopilot *autopilot) Enabled() bool {
+	anAutopilot.mutex.Lock()
+	defer anAutopilot.mutex.Unlock()
+
+	return anAutopilot.enabled
+}
@@ -0,0 +1,49 @@
+package autopilot
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutopilot(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	gameState.SetSeed(3)
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+	gameState.Start()
+
+	greedy, err := strategy.New("greedy", 1)
+	require.NoError(t, err)
+
+	anAutopilot := New(greedy)
+	require.NoError(t, anAutopilot.Start())
+	require.False(t, anAutopilot.Enabled())
+
+	// Disabled, the autopilot leaves the snake to the keys
+	direction, err := anAutopilot.NextMove(gameState)
+	require.NoError(t, err)
+	require.Equal(t, common.Direction{}, direction)
+
+	// Enabled, it plays the strategy until the candy is eaten
+	require.True(t, anAutopilot.Toggle())
+	for round := 0; round < 20 && gameState.Score() == 0; round++ {
+		direction, err := anAutopilot.NextMove(gameState)
+		require.NoError(t, err)
+		strategy.Steer(gameState, direction)
+
+		_, err = gameState.Play()
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, gameState.Score())
+	require.NoError(t, anAutopilot.EndGame(gameState))
+	require.True(t, anAutopilot.Enabled(), "the autopilot stays on for the next game")
+
+	require.False(t, anAutopilot.Toggle())
+	require.NoError(t, anAutopilot.Close())
+}
@@ -0,0 +1,341 @@
+package battlesnake
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/bot"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ruleset is the ruleset announced to the snake servers, the sides of the board lead to the other sides
+const Ruleset = "wrapped"
+
+// Health is the health of the snake, it doesn't starve in GoSnake
+const Health = 100
+
+// snakeID and snakeName name the only snake of the board
+const (
+	snakeID   = "gosnake"
+	snakeName = "GoSnake"
+)
+
+// Defines custom errors
+var (
+	ErrNoURL       = errors.New("no snake server URL")
+	ErrUnreachable = errors.New("the snake server can't be reached")
+	ErrNotStarted  = errors.New("the snake server is not started")
+)
+
+// directionNames are the moves of the Battlesnake API, the rows are sent from the bottom and up stays up
+var directionNames = map[string]common.Direction{
+	"up":    strategy.Up,
+	"down":  strategy.Down,
+	"left":  strategy.Left,
+	"right": strategy.Right,
+}
+
+// Settings of a snake server
+type Settings struct {
+	URL     string        // The root of the API of the snake, e.g. http://localhost:8000
+	Timeout time.Duration // Time budget of a move, the latency included
+	Forfeit bool          // A late or malformed move loses the game instead of keeping the snake straight
+}
+
+// Coord is a cell of the board, from the bottom left corner
+type Coord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Snake is a snake of the board, its body goes from the head to the tail
+type Snake struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Health         int               `json:"health"`
+	Body           []Coord           `json:"body"`
+	Latency        string            `json:"latency"`
+	Head           Coord             `json:"head"`
+	Length         int               `json:"length"`
+	Shout          string            `json:"shout"`
+	Customizations map[string]string `json:"customizations"`
+}
+
+// Board holds the snakes and the food
+type Board struct {
+	Height  int     `json:"height"`
+	Width   int     `json:"width"`
+	Food    []Coord `json:"food"`
+	Hazards []Coord `json:"hazards"`
+	Snakes  []Snake `json:"snakes"`
+}
+
+// Game describes the game and its rules
+type Game struct {
+	ID      string      `json:"id"`
+	Ruleset RulesetInfo `json:"ruleset"`
+	Map     string      `json:"map"`
+	Timeout int64       `json:"timeout"`
+	Source  string      `json:"source"`
+}
+
+// RulesetInfo names the rules
+type RulesetInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// GameState is the body of the requests to /start, /move and /end
+type GameState struct {
+	Game  Game  `json:"game"`
+	Turn  int   `json:"turn"`
+	Board Board `json:"board"`
+	You   Snake `json:"you"`
+}
+
+// Info is the body of the reply of the root of the API, the snake tells how it looks
+type Info struct {
+	APIVersion string `json:"apiversion"`
+	Author     string `json:"author"`
+	Color      string `json:"color"`
+	Head       string `json:"head"`
+	Tail       string `json:"tail"`
+	Version    string `json:"version"`
+}
+
+// MoveResponse is the body of the replies of /move
+type MoveResponse struct {
+	Move  string `json:"move"`
+	Shout string `json:"shout,omitempty"`
+}
+
+// client plays a snake server as a bot, a game of GoSnake is a game with a single snake
+type client struct {
+	settings       Settings
+	httpClient     *http.Client
+	customizations map[string]string
+	started        bool
+	gameID         string
+	inGame         bool
+	turn           int
+	latency        time.Duration
+	games          int
+	mutex          sync.Mutex
+}
+
+// New returns an instance of client, Start checks the server answers
+func New(settings Settings) bot.Boter {
+	if settings.Timeout <= 0 {
+		settings.Timeout = bot.DefaultTimeout
+	}
+
+	settings.URL = strings.TrimSuffix(settings.URL, "/")
+
+	return &client{settings: settings, httpClient: &http.Client{Timeout: settings.Timeout}}
+}
+
+// Start reads the customizations of the snake from the root of its API, fails with bot.ErrMalformedReply on a reply which
+// isn't JSON
+func (aClient *client) Start() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aClient.settings.URL == "" {
+		return ErrNoURL
+	}
+
+	response, err := aClient.httpClient.Get(aClient.settings.URL + "/")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnreachable, response.Status)
+	}
+
+	// The customizations are optional, a snake without them plays as well
+	var info Info
+	if err := json.NewDecoder(response.Body).Decode(&info); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %v", bot.ErrMalformedReply, err)
+	}
+
+	aClient.mutex.Lock()
+	aClient.customizations = map[string]string{"color": info.Color, "head": info.Head, "tail": info.Tail}
+	aClient.started = true
+	aClient.mutex.Unlock()
+
+	return nil
+}
+
+// NextMove asks /move for the direction, a game starting is announced to /start first
+// A miss keeps the snake straight with an error telling why, or fails with bot.ErrForfeit when the snake forfeits
+func (aClient *client) NextMove(gameState gamestate.GameStater) (direction common.Direction, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aClient.mutex.Lock()
+	defer aClient.mutex.Unlock()
+
+	if !aClient.started {
+		return direction, ErrNotStarted
+	}
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return direction, err
+	}
+
+	// A round going back is a new game, the one before was lost without its end
+	if !aClient.inGame || gameState.Round() < aClient.turn {
+		aClient.games++
+		aClient.gameID = fmt.Sprintf("gosnake-%d-%d", time.Now().UnixNano(), aClient.games)
+		aClient.inGame = true
+
+		// The servers are free to ignore /start, as the engines of Battlesnake do
+		aClient.post("/start", aClient.gameState(state, gameState.Round()), nil)
+	}
+
+	aClient.turn = gameState.Round()
+
+	direction, err = aClient.move(aClient.gameState(state, gameState.Round()))
+	if err == nil {
+		return direction, nil
+	}
+
+	if aClient.settings.Forfeit {
+		return state.Direction, fmt.Errorf("%w: %v", bot.ErrForfeit, err)
+	}
+
+	return state.Direction, err
+}
+
+func (aClient *client) move(request GameState) (direction common.Direction, err error) {
+	var response MoveResponse
+
+	started := time.Now()
+	err = aClient.post("/move", request, &response)
+	aClient.latency = time.Since(started)
+
+	if err != nil {
+		return direction, err
+	}
+
+	direction, ok := directionNames[strings.ToLower(response.Move)]
+	if !ok {
+		return direction, fmt.Errorf("%w: move %q, expected up, down, left or right", bot.ErrMalformedReply, response.Move)
+	}
+
+	return direction, nil
+}
+
+// EndGame sends the last state to /end
+func (aClient *client) EndGame(gameState gamestate.GameStater) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aClient.mutex.Lock()
+	defer aClient.mutex.Unlock()
+
+	if !aClient.inGame {
+		return nil
+	}
+
+	aClient.inGame = false
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return err
+	}
+
+	return aClient.post("/end", aClient.gameState(state, gameState.Round()), nil)
+}
+
+// Close does nothing, the server keeps running for the next games
+func (aClient *client) Close() error {
+	return nil
+}
+
+// gameState returns the game as the Battlesnake API describes it, the rows are counted from the bottom
+func (aClient *client) gameState(state strategy.State, turn int) GameState {
+	coord := func(position common.Position) Coord {
+		return Coord{X: position.X, Y: state.Size.Height - 1 - position.Y}
+	}
+
+	you := Snake{
+		ID:             snakeID,
+		Name:           snakeName,
+		Health:         Health,
+		Latency:        strconv.FormatInt(aClient.latency.Milliseconds(), 10),
+		Length:         len(state.Body),
+		Customizations: aClient.customizations,
+	}
+
+	for i := len(state.Body) - 1; i >= 0; i-- {
+		you.Body = append(you.Body, coord(state.Body[i]))
+	}
+
+	if len(you.Body) > 0 {
+		you.Head = you.Body[0]
+	}
+
+	board := Board{Height: state.Size.Height, Width: state.Size.Width, Food: []Coord{}, Hazards: []Coord{},
+		Snakes: []Snake{you}}
+	if state.CandyAlive {
+		board.Food = append(board.Food, coord(state.Candy))
+	}
+
+	return GameState{
+		Game: Game{
+			ID:      aClient.gameID,
+			Ruleset: RulesetInfo{Name: Ruleset, Version: "gosnake"},
+			Map:     "standard",
+			Timeout: aClient.settings.Timeout.Milliseconds(),
+			Source:  "custom",
+		},
+		Turn:  turn,
+		Board: board,
+		You:   you,
+	}
+}
+
+// post sends request as JSON to path, the reply is decoded into response when it isn't nil
+func (aClient *client) post(path string, request GameState, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	reply, err := aClient.httpClient.Post(aClient.settings.URL+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("%w: %v", bot.ErrTimeout, aClient.settings.Timeout)
+		}
+
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	defer reply.Body.Close()
+
+	if reply.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %s", bot.ErrMalformedReply, path, reply.Status)
+	}
+
+	if response == nil {
+		_, err := io.Copy(io.Discard, reply.Body)
+		return err
+	}
+
+	if err := json.NewDecoder(reply.Body).Decode(response); err != nil {
+		return fmt.Errorf("%w: %v", bot.ErrMalformedReply, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package battlesnake
+
+import (
+	"encoding/json"
+	"gosnake/pkg/bot"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate/gamestatetest"
+	"gosnake/pkg/strategy"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// standIn is a snake server for the tests, it records the requests and replies with move
+// The move "chase" heads for the food as a Battlesnake would, from the coordinates of the requests
+type standIn struct {
+	move     string
+	status   int
+	delay    time.Duration
+	mutex    sync.Mutex
+	requests map[string][]GameState
+}
+
+func newStandIn(t *testing.T, move string) (*standIn, string) {
+	aStandIn := &standIn{move: move, status: http.StatusOK, requests: make(map[string][]GameState)}
+
+	server := httptest.NewServer(aStandIn)
+	t.Cleanup(server.Close)
+
+	return aStandIn, server.URL
+}
+
+func (aStandIn *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		json.NewEncoder(w).Encode(Info{APIVersion: "1", Color: "#00ff00", Head: "smile", Tail: "bolt"})
+		return
+	}
+
+	var request GameState
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	aStandIn.mutex.Lock()
+	aStandIn.requests[r.URL.Path] = append(aStandIn.requests[r.URL.Path], request)
+	aStandIn.mutex.Unlock()
+
+	if r.URL.Path != "/move" {
+		return
+	}
+
+	time.Sleep(aStandIn.delay)
+
+	if aStandIn.status != http.StatusOK {
+		w.WriteHeader(aStandIn.status)
+		return
+	}
+
+	move := aStandIn.move
+	if move == "chase" {
+		move = chase(request)
+	}
+
+	json.NewEncoder(w).Encode(MoveResponse{Move: move})
+}
+
+// chase moves along the columns first, then along the rows
+func chase(request GameState) string {
+	head, food := request.You.Head, request.Board.Food[0]
+
+	switch {
+	case food.X > head.X:
+		return "right"
+	case food.X < head.X:
+		return "left"
+	case food.Y > head.Y:
+		return "up"
+	}
+
+	return "down"
+}
+
+func (aStandIn *standIn) count(path string) int {
+	aStandIn.mutex.Lock()
+	defer aStandIn.mutex.Unlock()
+
+	return len(aStandIn.requests[path])
+}
+
+func TestClient_Game(t *testing.T) {
+	aStandIn, url := newStandIn(t, "chase")
+
+	aClient := New(Settings{URL: url + "/", Timeout: time.Second})
+	require.NoError(t, aClient.Start())
+
+	// The snake follows the server to the candy
+	gameState := gamestatetest.New(t, common.Size{Width: 10, Height: 8}, 3)
+	for round := 0; round < 20 && gameState.Score() == 0; round++ {
+		direction, err := aClient.NextMove(gameState)
+		require.NoError(t, err)
+		strategy.Steer(gameState, direction)
+
+		_, err = gameState.Play()
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, gameState.Score())
+	require.NoError(t, aClient.EndGame(gameState))
+	require.NoError(t, aClient.EndGame(gameState), "the game already ended")
+
+	require.Equal(t, 1, aStandIn.count("/start"))
+	require.Equal(t, 1, aStandIn.count("/end"))
+
+	// The requests describe the game from the bottom left corner, the head first
+	start, moves, end := aStandIn.requests["/start"][0], aStandIn.requests["/move"], aStandIn.requests["/end"][0]
+	require.Equal(t, Ruleset, start.Game.Ruleset.Name)
+	require.Equal(t, int64(1000), start.Game.Timeout)
+	require.Equal(t, Board{Height: 8, Width: 10, Food: moves[0].Board.Food, Hazards: []Coord{},
+		Snakes: []Snake{start.You}}, start.Board)
+	require.Equal(t, Coord{X: 5, Y: 3}, start.You.Head)
+	require.Equal(t, map[string]string{"color": "#00ff00", "head": "smile", "tail": "bolt"}, start.You.Customizations)
+	require.Equal(t, Health, start.You.Health)
+	require.Equal(t, start.Game.ID, end.Game.ID)
+	require.Equal(t, len(moves)-1, moves[len(moves)-1].Turn)
+	require.Equal(t, 2, end.You.Length)
+	require.Equal(t, end.You.Head, end.You.Body[0])
+
+	// The next game is started with another id
+	_, err := aClient.NextMove(gamestatetest.New(t, common.Size{Width: 10, Height: 8}, 3))
+	require.NoError(t, err)
+	require.Equal(t, 2, aStandIn.count("/start"))
+	require.NotEqual(t, start.Game.ID, aStandIn.requests["/start"][1].Game.ID)
+	require.NoError(t, aClient.Close())
+}
+
+func TestClient_NextMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		move    string
+		status  int
+		delay   time.Duration
+		forfeit bool
+		want    common.Direction
+		wantErr error
+	}{
+		{name: "TestUp", move: "up", want: strategy.Up},
+		{name: "TestCase", move: "Left", want: strategy.Left},
+		{name: "TestUnknownMove", move: "north", want: strategy.Right, wantErr: bot.ErrMalformedReply},
+		{name: "TestServerError", move: "up", status: http.StatusInternalServerError, want: strategy.Right,
+			wantErr: bot.ErrMalformedReply},
+		{name: "TestTimeout", move: "up", delay: 200 * time.Millisecond, want: strategy.Right,
+			wantErr: bot.ErrTimeout},
+		{name: "TestForfeit", move: "north", forfeit: true, want: strategy.Right, wantErr: bot.ErrForfeit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aStandIn, url := newStandIn(t, tt.move)
+			aStandIn.delay = tt.delay
+			if tt.status != 0 {
+				aStandIn.status = tt.status
+			}
+
+			aClient := New(Settings{URL: url, Timeout: 50 * time.Millisecond, Forfeit: tt.forfeit})
+			require.NoError(t, aClient.Start())
+
+			// The snake starts to the right, a miss keeps it straight
+			got, err := aClient.NextMove(gamestatetest.New(t, common.Size{Width: 10, Height: 8}, 3))
+			require.Equal(t, tt.want, got)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestClient_Start(t *testing.T) {
+	require.ErrorIs(t, New(Settings{}).Start(), ErrNoURL)
+
+	_, err := New(Settings{URL: "http://localhost:1"}).NextMove(gamestatetest.New(t, common.Size{Width: 10, Height: 8}, 3))
+	require.ErrorIs(t, err, ErrNotStarted)
+
+	// An empty root is a snake without customizations, a root which isn't JSON is malformed
+	empty := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer empty.Close()
+	require.NoError(t, New(Settings{URL: empty.URL}).Start())
+
+	garbage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>snake</html>"))
+	}))
+	defer garbage.Close()
+	require.ErrorIs(t, New(Settings{URL: garbage.URL}).Start(), bot.ErrMalformedReply)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	require.ErrorIs(t, New(Settings{URL: server.URL}).Start(), ErrUnreachable)
+
+	server.Close()
+	require.ErrorIs(t, New(Settings{URL: server.URL}).Start(), ErrUnreachable)
+}
@@ -0,0 +1,309 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the time a bot is given to reply when none is configured
+const DefaultTimeout = 100 * time.Millisecond
+
+// closeTimeout is the time a bot is given to quit once its input is closed, then it is killed
+const closeTimeout = time.Second
+
+// Defines custom errors
+var (
+	ErrNoCommand      = errors.New("no bot command")
+	ErrNotStarted     = errors.New("the bot is not started")
+	ErrTimeout        = errors.New("the bot didn't reply in time")
+	ErrMalformedReply = errors.New("malformed reply from the bot")
+	ErrBotExited      = errors.New("the bot exited")
+	ErrForfeit        = errors.New("the bot forfeits the game")
+)
+
+// directionNames are the replies of the bots, and the directions of the snake in the messages
+var directionNames = map[string]common.Direction{
+	"up":    strategy.Up,
+	"down":  strategy.Down,
+	"left":  strategy.Left,
+	"right": strategy.Right,
+}
+
+// Settings of a bot
+type Settings struct {
+	Command []string      // The program and its arguments
+	Timeout time.Duration // Time budget of a reply
+	Forfeit bool          // A late or malformed reply loses the game instead of keeping the snake straight
+	Stderr  io.Writer     // Receives the errors written by the bot, discarded when nil
+}
+
+// Message is the line of JSON sent to the bot before each round
+type Message struct {
+	Round     int        `json:"round"`
+	Score     int        `json:"score"`
+	Width     int        `json:"width"`
+	Height    int        `json:"height"`
+	Snake     []Position `json:"snake"` // From the tail to the head
+	Direction string     `json:"direction"`
+	Candy     *Position  `json:"candy,omitempty"` // Omitted while there is no candy
+	TimeoutMS int64      `json:"timeout_ms"`
+}
+
+// Position is a cell of the board in the messages, from the top left corner
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Boter is the interface for bot
+type Boter interface {
+	Start() (err error)
+	NextMove(gameState gamestate.GameStater) (direction common.Direction, err error)
+	EndGame(gameState gamestate.GameStater) (err error)
+	Close() (err error)
+}
+
+// bot is a program reading the game on its standard input and writing a direction per round on its standard output
+// The replies are matched with the messages in order, a reply coming after the time budget is never taken for the
+// move of a later round
+type bot struct {
+	settings Settings
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	replies  chan reply
+	exited   chan struct{}
+	sent     int        // Messages written, the reply of the last one has the same index
+	writing  chan error // The result of the message being written, nil once it is written
+	mutex    sync.Mutex
+}
+
+// reply is a line written by the bot, the reply of index n answers the message n
+type reply struct {
+	index int
+	line  string
+}
+
+// New returns an instance of bot, Start spawns the program
+func New(settings Settings) Boter {
+	if settings.Timeout <= 0 {
+		settings.Timeout = DefaultTimeout
+	}
+
+	return &bot{settings: settings}
+}
+
+// Start spawns the program of the bot
+func (aBot *bot) Start() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if len(aBot.settings.Command) == 0 {
+		return ErrNoCommand
+	}
+
+	cmd := exec.Command(aBot.settings.Command[0], aBot.settings.Command[1:]...)
+	cmd.Stderr = aBot.settings.Stderr
+
+	if aBot.stdin, err = cmd.StdinPipe(); err != nil {
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	aBot.cmd, aBot.replies, aBot.exited = cmd, make(chan reply, 1), make(chan struct{})
+	aBot.sent, aBot.writing = 0, nil
+
+	go aBot.read(stdout)
+
+	return nil
+}
+
+// read passes the lines of the bot to NextMove, a line not waited for replaces the previous one
+func (aBot *bot) read(stdout io.Reader) {
+	defer close(aBot.exited)
+
+	scanner := bufio.NewScanner(stdout)
+	for index := 1; scanner.Scan(); index++ {
+		select {
+		case <-aBot.replies:
+		default:
+		}
+
+		aBot.replies <- reply{index: index, line: scanner.Text()}
+	}
+}
+
+// NextMove sends the game to the bot and returns its reply
+// A miss keeps the snake straight with an error telling why, or fails with ErrForfeit when the bot forfeits
+func (aBot *bot) NextMove(gameState gamestate.GameStater) (direction common.Direction, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aBot.mutex.Lock()
+	defer aBot.mutex.Unlock()
+
+	if aBot.cmd == nil {
+		return direction, ErrNotStarted
+	}
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return direction, err
+	}
+
+	direction, err = aBot.ask(aBot.message(state, gameState.Round(), gameState.Score()))
+	if err == nil {
+		return direction, nil
+	}
+
+	if aBot.settings.Forfeit {
+		return state.Direction, fmt.Errorf("%w: %v", ErrForfeit, err)
+	}
+
+	return state.Direction, err
+}
+
+func (aBot *bot) message(state strategy.State, round, score int) Message {
+	message := Message{
+		Round:     round,
+		Score:     score,
+		Width:     state.Size.Width,
+		Height:    state.Size.Height,
+		Snake:     make([]Position, len(state.Body)),
+		TimeoutMS: aBot.settings.Timeout.Milliseconds(),
+	}
+
+	for i, position := range state.Body {
+		message.Snake[i] = Position{X: position.X, Y: position.Y}
+	}
+
+	for name, direction := range directionNames {
+		if direction == state.Direction {
+			message.Direction = name
+		}
+	}
+
+	if state.CandyAlive {
+		message.Candy = &Position{X: state.Candy.X, Y: state.Candy.Y}
+	}
+
+	return message
+}
+
+// ask writes the message and waits for its reply within the time budget, the late replies of the previous rounds
+// are dropped
+func (aBot *bot) ask(message Message) (direction common.Direction, err error) {
+	line, err := json.Marshal(message)
+	if err != nil {
+		return direction, err
+	}
+
+	timer := time.NewTimer(aBot.settings.Timeout)
+	defer timer.Stop()
+
+	if err := aBot.write(append(line, '\n'), timer.C); err != nil {
+		return direction, err
+	}
+
+	for {
+		select {
+		case aReply := <-aBot.replies:
+			if aReply.index == aBot.sent {
+				return parseReply(aReply.line)
+			}
+		case <-aBot.exited:
+			return direction, ErrBotExited
+		case <-timer.C:
+			return direction, fmt.Errorf("%w: %v", ErrTimeout, aBot.settings.Timeout)
+		}
+	}
+}
+
+// write sends a line to the bot before the deadline, a bot which doesn't read its input can't hold up the game
+// The message of a previous round still being written is waited for first, the lines are never mixed up
+func (aBot *bot) write(line []byte, deadline <-chan time.Time) error {
+	if aBot.writing != nil {
+		if err := aBot.waitWrite(deadline); err != nil {
+			return err
+		}
+	}
+
+	writing := make(chan error, 1)
+	go func(stdin io.Writer) {
+		_, err := stdin.Write(line)
+		writing <- err
+	}(aBot.stdin)
+
+	aBot.sent, aBot.writing = aBot.sent+1, writing
+
+	return aBot.waitWrite(deadline)
+}
+
+func (aBot *bot) waitWrite(deadline <-chan time.Time) error {
+	select {
+	case err := <-aBot.writing:
+		aBot.writing = nil
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrBotExited, err)
+		}
+
+		return nil
+	case <-deadline:
+		return fmt.Errorf("%w: %v, the bot doesn't read its input", ErrTimeout, aBot.settings.Timeout)
+	}
+}
+
+// parseReply reads a direction, the case and the spaces around don't matter
+func parseReply(reply string) (direction common.Direction, err error) {
+	direction, ok := directionNames[strings.ToLower(strings.TrimSpace(reply))]
+	if !ok {
+		return direction, fmt.Errorf("%w: %q, expected up, down, left or right", ErrMalformedReply, reply)
+	}
+
+	return direction, nil
+}
+
+// EndGame does nothing, the bot learns a game is over from the round 0 of the next one
+func (aBot *bot) EndGame(gamestate.GameStater) error {
+	return nil
+}
+
+// Close closes the input of the bot and waits for it to quit, it is killed if it doesn't
+func (aBot *bot) Close() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aBot.mutex.Lock()
+	defer aBot.mutex.Unlock()
+
+	if aBot.cmd == nil {
+		return nil
+	}
+
+	aBot.stdin.Close()
+
+	select {
+	case <-aBot.exited:
+	case <-time.After(closeTimeout):
+		aBot.cmd.Process.Kill()
+	}
+
+	aBot.cmd.Wait()
+	aBot.cmd, aBot.writing = nil, nil
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate/gamestatetest"
+	"gosnake/pkg/strategy"
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// helperEnv makes the test binary play the bot named by its value, instead of running the tests
+const helperEnv = "GOSNAKE_TEST_BOT"
+
+func TestMain(m *testing.M) {
+	if name := os.Getenv(helperEnv); name != "" {
+		helperBot(name)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// helperBot replies to each message as the bot name does
+func helperBot(name string) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for rounds := 0; scanner.Scan(); rounds++ {
+		var message Message
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			fmt.Println("bad message")
+			continue
+		}
+
+		switch name {
+		case "up":
+			fmt.Println(" UP ")
+		case "garbage":
+			fmt.Println("north")
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+			fmt.Println("up")
+		case "lagging":
+			// The reply of the first round comes while the second one is played
+			if rounds == 0 {
+				time.Sleep(300 * time.Millisecond)
+				fmt.Println("up")
+				continue
+			}
+
+			fmt.Println("down")
+		case "exit":
+			return
+		case "echo":
+			// The reply tells the message was read: the direction of the snake and the length of its body
+			if message.Candy != nil && len(message.Snake) == 1 && message.Round == 0 && message.TimeoutMS == 5000 {
+				fmt.Println(message.Direction)
+			}
+		}
+	}
+}
+
+// newTestBot starts the test binary as the bot name, the slow bot is given less time than it takes
+func newTestBot(t *testing.T, name string, forfeit bool) Boter {
+	t.Setenv(helperEnv, name)
+
+	timeout := 5 * time.Second
+	switch name {
+	case "slow":
+		timeout = 50 * time.Millisecond
+	case "lagging":
+		timeout = 200 * time.Millisecond
+	}
+
+	aBot := New(Settings{Command: []string{os.Args[0]}, Timeout: timeout, Forfeit: forfeit})
+
+	require.NoError(t, aBot.Start())
+	t.Cleanup(func() { require.NoError(t, aBot.Close()) })
+
+	return aBot
+}
+
+func TestBot_NextMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		forfeit bool
+		want    common.Direction
+		wantErr error
+	}{
+		{name: "up", want: strategy.Up},
+		{name: "echo", want: strategy.Right},
+		{name: "garbage", want: strategy.Right, wantErr: ErrMalformedReply},
+		{name: "slow", want: strategy.Right, wantErr: ErrTimeout},
+		{name: "exit", want: strategy.Right, wantErr: ErrBotExited},
+		{name: "garbage", forfeit: true, want: strategy.Right, wantErr: ErrForfeit},
+	}
+	for _, tt := range tests {
+		t.Run("Test_"+tt.name, func(t *testing.T) {
+			aBot := newTestBot(t, tt.name, tt.forfeit)
+
+			// The snake starts to the right, a miss keeps it straight
+			got, err := aBot.NextMove(gamestatetest.New(t, common.Size{Width: 10, Height: 10}, 1))
+			require.Equal(t, tt.want, got)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBot_NextMoveLateReply(t *testing.T) {
+	aBot := newTestBot(t, "slow", false)
+	gameState := gamestatetest.New(t, common.Size{Width: 10, Height: 10}, 1)
+
+	_, err := aBot.NextMove(gameState)
+	require.ErrorIs(t, err, ErrTimeout)
+
+	// The late reply of the first round isn't taken for the reply of the second one
+	time.Sleep(250 * time.Millisecond)
+
+	_, err = aBot.NextMove(gameState)
+	require.ErrorIs(t, err, ErrTimeout)
+
+	// The late reply coming while the next round is played isn't taken for its move
+	aBot = newTestBot(t, "lagging", false)
+
+	_, err = aBot.NextMove(gameState)
+	require.ErrorIs(t, err, ErrTimeout)
+
+	got, err := aBot.NextMove(gameState)
+	require.NoError(t, err)
+	require.Equal(t, strategy.Down, got)
+}
+
+func TestBot_NextMoveBlockedInput(t *testing.T) {
+	// The bot doesn't read its input, the message can't be written
+	reader, writer := io.Pipe()
+	aBot := &bot{settings: Settings{Timeout: 50 * time.Millisecond}, cmd: &exec.Cmd{}, stdin: writer,
+		replies: make(chan reply, 1), exited: make(chan struct{})}
+	gameState := gamestatetest.New(t, common.Size{Width: 10, Height: 10}, 1)
+
+	for round := 0; round < 2; round++ {
+		started := time.Now()
+		got, err := aBot.NextMove(gameState)
+		require.ErrorIs(t, err, ErrTimeout)
+		require.Equal(t, strategy.Right, got)
+		require.Less(t, time.Since(started), time.Second)
+	}
+
+	// The message blocked fails once the input is closed
+	require.NoError(t, reader.Close())
+	_, err := aBot.NextMove(gameState)
+	require.ErrorIs(t, err, ErrBotExited)
+}
+
+func TestBot_Errors(t *testing.T) {
+	_, err := New(Settings{}).NextMove(gamestatetest.New(t, common.Size{Width: 10, Height: 10}, 1))
+	require.ErrorIs(t, err, ErrNotStarted)
+	require.ErrorIs(t, New(Settings{}).Start(), ErrNoCommand)
+	require.Error(t, New(Settings{Command: []string{"/nonexistent/bot"}}).Start())
+	require.NoError(t, New(Settings{}).Close())
+}
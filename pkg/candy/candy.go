@@ -10,6 +10,7 @@ type Candyer interface {
 	Init(newPosition common.Position)
 	Position() common.Position
 	Alive() bool
+	Clone() Candyer
 }
 
 // candy has the properties of a candy
@@ -43,3 +44,9 @@ func (aCandy *candy) Position() common.Position {
 func (aCandy *candy) Alive() bool {
 	return aCandy.alive
 }
+
+// Clone returns a copy of the candy
+func (aCandy *candy) Clone() Candyer {
+	aClone := *aCandy
+	return &aClone
+}
@@ -226,3 +226,16 @@ func TestNew(t *testing.T) {
 	var got = New()
 	require.IsType(t, wantType, got)
 }
+
+func TestCandy_Clone(t *testing.T) {
+	aCandy := &candy{}
+	aCandy.Init(common.Position{X: 3, Y: 4})
+
+	aClone := aCandy.Clone()
+	require.True(t, aClone.Alive())
+	require.Equal(t, common.Position{X: 3, Y: 4}, aClone.Position())
+
+	// The clone is eaten on its own
+	aClone.Remove()
+	require.True(t, aCandy.Alive())
+}
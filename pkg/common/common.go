@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"runtime/debug"
+	"strings"
 )
 
 // ViewPosition holds coordinates of a view rectangle
@@ -32,10 +34,74 @@ type Size struct {
 	Height int
 }
 
-// Sprite holds a rune and its position
+// Color is a terminal color: the default color, one of the 8 basic colors or a color of the 256 colors palette
+type Color int
+
+// The default color and the basic colors, they are the first colors of the palette
+const (
+	ColorDefault Color = iota
+	ColorBlack
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// Color256 returns the color of index n in the 256 colors palette
+func Color256(n uint8) Color {
+	return Color(int(n) + 1)
+}
+
+// Style holds the colors and the weight of a character
+type Style struct {
+	Fg   Color
+	Bg   Color
+	Bold bool
+}
+
+// Sprite holds a rune, its position and its style
+// The zero style lets the user interface choose the style of the value
 type Sprite struct {
 	Value    rune
 	Position Position
+	Style    Style
+}
+
+// BoardCheckpoint holds a copy of a game board and of its objects
+type BoardCheckpoint struct {
+	Size           Size
+	Board          [][]rune
+	SnakeBody      []Position
+	SnakeDirection Direction
+	CandyPosition  Position
+	CandyAlive     bool
+}
+
+// GameCheckpoint holds a copy of a game state which can be restored to resume a game
+type GameCheckpoint struct {
+	Round     int
+	Score     int
+	HighScore int
+	Board     BoardCheckpoint
+}
+
+// GameHistory holds a game as played, it can be replayed round after round
+type GameHistory struct {
+	BoardSize Size
+	Start     []Sprite   // The snake and the candy when the game started
+	Rounds    [][]Sprite // The sprites changed by each round
+}
+
+// BoardSnapshot holds a copy of the cells of a board and what is needed to tell the game apart
+type BoardSnapshot struct {
+	Score int
+	Round int
+	Size  Size
+	Seed  int64    // The seed of the candy positions
+	Cells [][]rune // Cells[x][y], as the board
 }
 
 // GetCurrentFuncName returns the caller's function name
@@ -44,22 +110,78 @@ func GetCurrentFuncName() string {
 	return runtime.FuncForPC(pc).Name()
 }
 
+// PanicError holds a recovered panic and the stack of the goroutine at recovery
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+	err   error
+}
+
+// Error returns the message of the panic
+func (panicError *PanicError) Error() string {
+	return panicError.err.Error()
+}
+
+// Unwrap returns the panic as an error so errors.Is() can identify it
+func (panicError *PanicError) Unwrap() error {
+	return panicError.err
+}
+
+// NewPanicError converts a recovered panic to an error. It must be called while recovering
+// so the captured stack includes the function which panicked
+func NewPanicError(aPanic interface{}) error {
+	var err error
+
+	// aPanic may be of type error or of type string
+	// We may switch aPanic.(type)
+	switch val := aPanic.(type) {
+	case string:
+		err = errors.New(val)
+	case error:
+		err = val
+	default: // or simply convert aPanic to string then create a new error...
+		strErr := fmt.Sprint(aPanic)
+		err = errors.New(strErr)
+	}
+
+	return &PanicError{
+		Value: aPanic,
+		Stack: debug.Stack(),
+		err:   err,
+	}
+}
+
 // ErrorWrapper is called by functions in defer. It catches panics and wraps err, if it is not nil, with the function name
 func ErrorWrapper(funcName string, err *error) {
 	if aPanic := recover(); aPanic != nil {
-		// aPanic may be of type error or of type string
-		// We may switch aPanic.(type)
-		switch val := aPanic.(type) {
-		case string:
-			*err = errors.New(val)
-		case error:
-			*err = val
-		default: // or simply convert aPanic to string then create a new error...
-			strErr := fmt.Sprint(aPanic)
-			*err = errors.New(strErr)
-		}
+		*err = NewPanicError(aPanic)
 	}
 	if *err != nil {
 		*err = fmt.Errorf(funcName+": %w", *err)
 	}
 }
+
+// ErrorFrames splits a chain built by ErrorWrapper into its frames
+// The outermost function comes first and the original error last
+func ErrorFrames(err error) (frames []string) {
+	for err != nil {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return append(frames, err.Error())
+		}
+
+		msg := err.Error()
+		if !strings.HasSuffix(msg, inner.Error()) {
+			// The wrapper doesn't follow the "funcName: err" pattern, the whole message is kept
+			return append(frames, msg)
+		}
+
+		if frame := strings.TrimSuffix(strings.TrimSuffix(msg, inner.Error()), ": "); frame != "" {
+			frames = append(frames, frame)
+		}
+
+		err = inner
+	}
+
+	return frames
+}
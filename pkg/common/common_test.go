@@ -2,6 +2,7 @@ package common
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -94,3 +95,55 @@ func TestErrorWrapper(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorWrapper_PanicError(t *testing.T) {
+	var err error
+	func() {
+		defer ErrorWrapper("TestPanicStack", &err)
+		panic("a string panic")
+	}()
+
+	var panicError *PanicError
+	require.ErrorAs(t, err, &panicError)
+	require.Equal(t, "a string panic", panicError.Value)
+	require.Contains(t, string(panicError.Stack), "TestErrorWrapper_PanicError")
+	require.Equal(t, "TestPanicStack: a string panic", err.Error())
+}
+
+func TestErrorFrames(t *testing.T) {
+	errBase := errors.New("base error")
+	tests := []struct {
+		name       string
+		err        error
+		wantFrames []string
+	}{
+		{
+			name: "TestNil",
+		},
+		{
+			name:       "TestNotWrapped",
+			err:        errBase,
+			wantFrames: []string{"base error"},
+		},
+		{
+			name:       "TestChain",
+			err:        fmt.Errorf("main.a: %w", fmt.Errorf("pkg.b: %w", errBase)),
+			wantFrames: []string{"main.a", "pkg.b", "base error"},
+		},
+		{
+			name:       "TestPanicInChain",
+			err:        fmt.Errorf("main.a: %w", NewPanicError(errBase)),
+			wantFrames: []string{"main.a", "base error"},
+		},
+		{
+			name:       "TestOtherWrapper",
+			err:        fmt.Errorf("main.a: %w", fmt.Errorf("failed with %w here", errBase)),
+			wantFrames: []string{"main.a", "failed with base error here"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantFrames, ErrorFrames(tt.err))
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/gifexport"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/theme"
+	"gosnake/pkg/uimanager"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limits of the settings
+const (
+	MinBoardSize       = gamestate.MinBoardSize
+	MaxBoardColumns    = 80 // The board view must fit beside the panel
+	MaxBoardRows       = 40
+	MinRefreshInterval = 10 * time.Millisecond
+	MaxRefreshInterval = 2 * time.Second
+)
+
+// Defines custom errors
+var (
+	ErrInvalidConfig = errors.New("invalid configuration")
+	ErrInvalidGlyph  = errors.New("a glyph must be a single printable character, one or two columns wide")
+	ErrConfigExists  = errors.New("the configuration file already exists")
+)
+
+// header is written at the top of the files created by Write
+const header = "# GoSnake configuration, every setting is optional\n"
+
+// Config holds the settings read from the configuration file
+type Config struct {
+	Controls Controls `yaml:"controls"`
+	Timing   Timing   `yaml:"timing"`
+	Board    Board    `yaml:"board"`
+	Glyphs   Glyphs   `yaml:"glyphs"`
+	Display  Display  `yaml:"display"`
+	Export   Export   `yaml:"export"`
+}
+
+// Controls are the keys bound to each action: a preset where some actions can be rebound
+type Controls struct {
+	Preset   string               `yaml:"preset"`
+	Bindings keybindings.Bindings `yaml:"bindings,omitempty"`
+}
+
+// Timing holds the animations refresh rate and the idle time before the demo game
+type Timing struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	AttractTimeout  time.Duration `yaml:"attract_timeout"` // The autopilot plays a demo once the game sat idle that long, never when 0
+}
+
+// Board holds the board sizes, ENTER cycles from SizeIncrement to DefaultSize
+type Board struct {
+	DefaultSize   int `yaml:"default_size"`
+	SizeIncrement int `yaml:"size_increment"`
+}
+
+// Glyphs are the characters displayed for each type of cell
+type Glyphs struct {
+	Free  Glyph `yaml:"free"`
+	Snake Glyph `yaml:"snake"`
+	Candy Glyph `yaml:"candy"`
+}
+
+// Display holds the colors and the way the board is drawn, the theme can be changed while playing
+type Display struct {
+	Theme     string              `yaml:"theme"`
+	Colors    uimanager.ColorMode `yaml:"colors"`
+	BoardMode uimanager.BoardMode `yaml:"board_mode"`
+	TrapShade bool                `yaml:"trap_shade"` // Shades the region the snake is trapped in, not in the packed modes
+}
+
+// Export holds the settings of the GIFs of the games, a frame lasts the refresh interval,
+// and of the snapshots of the board
+type Export struct {
+	CellSize        int      `yaml:"cell_size"` // In pixels
+	Directory       string   `yaml:"directory"` // The current directory when empty
+	SnapshotFormats []string `yaml:"snapshot_formats,flow"`
+}
+
+// Glyph is a character written as a one character string in the configuration
+type Glyph rune
+
+// Default returns the settings used when there is no configuration file
+func Default() Config {
+	return Config{
+		Controls: Controls{
+			Preset: keybindings.DefaultPreset,
+		},
+		Timing: Timing{
+			RefreshInterval: 100 * time.Millisecond,
+			AttractTimeout:  30 * time.Second,
+		},
+		Board: Board{
+			DefaultSize:   40,
+			SizeIncrement: 10,
+		},
+		Glyphs: Glyphs{
+			Free:  Glyph(gameboard.FreeSpace),
+			Snake: Glyph(gameboard.SnakePart),
+			Candy: Glyph(gameboard.CandyBody),
+		},
+		Display: Display{
+			Theme:     theme.DefaultTheme,
+			Colors:    uimanager.ColorsAuto,
+			BoardMode: uimanager.BoardNormal,
+		},
+		Export: Export{
+			CellSize:        8,
+			SnapshotFormats: append([]string(nil), gameboard.SnapshotFormats...),
+		},
+	}
+}
+
+// DefaultPath returns the path of the configuration file in the user's configuration directory
+// ($XDG_CONFIG_HOME/gosnake/config.yaml on Linux)
+func DefaultPath() (fileName string, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return fileName, err
+	}
+
+	return filepath.Join(dir, "gosnake", "config.yaml"), nil
+}
+
+// Load reads fileName over the default settings and validates the result
+// When optional is set, a missing file gives the default settings
+func Load(fileName string, optional bool) (cfg Config, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	cfg = Default()
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+
+		return cfg, err
+	}
+
+	// Unknown settings are rejected, they are most likely typos
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		// The errors about the keys, the display modes and the glyphs are kept for errors.Is()
+		if !errors.Is(err, uimanager.ErrUnknownKey) && !errors.Is(err, uimanager.ErrUnknownColorMode) &&
+			!errors.Is(err, uimanager.ErrUnknownBoardMode) && !errors.Is(err, ErrInvalidGlyph) {
+			err = fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+
+		return cfg, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	return cfg, nil
+}
+
+// Write creates fileName with the settings, an existing file is only replaced when force is set
+func Write(fileName string, cfg Config, force bool) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if _, err := os.Stat(fileName); err == nil && !force {
+		return fmt.Errorf("%w: %s", ErrConfigExists, fileName)
+	}
+
+	data := bytes.NewBufferString(header)
+	encoder := yaml.NewEncoder(data)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(cfg); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, data.Bytes(), 0o644)
+}
+
+// Validate checks the settings, the error tells which one is wrong
+func (cfg Config) Validate() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if _, err := cfg.Controls.Table(); err != nil {
+		return fmt.Errorf("%w: controls: %v", ErrInvalidConfig, err)
+	}
+
+	if cfg.Timing.RefreshInterval < MinRefreshInterval || cfg.Timing.RefreshInterval > MaxRefreshInterval {
+		return fmt.Errorf("%w: timing.refresh_interval must be between %v and %v, got %v",
+			ErrInvalidConfig, MinRefreshInterval, MaxRefreshInterval, cfg.Timing.RefreshInterval)
+	}
+
+	if cfg.Timing.AttractTimeout < 0 {
+		return fmt.Errorf("%w: timing.attract_timeout can't be negative, got %v",
+			ErrInvalidConfig, cfg.Timing.AttractTimeout)
+	}
+
+	if cfg.Board.SizeIncrement < MinBoardSize {
+		return fmt.Errorf("%w: board.size_increment must be at least %d, got %d",
+			ErrInvalidConfig, MinBoardSize, cfg.Board.SizeIncrement)
+	}
+
+	if cfg.Board.DefaultSize < cfg.Board.SizeIncrement {
+		return fmt.Errorf("%w: board.default_size must be at least board.size_increment (%d), got %d",
+			ErrInvalidConfig, cfg.Board.SizeIncrement, cfg.Board.DefaultSize)
+	}
+
+	// The largest board depends on the board mode and on the width of the glyphs
+	viewSize := cfg.BoardViewSize(common.Size{Width: cfg.Board.DefaultSize, Height: cfg.Board.DefaultSize})
+	if viewSize.Width > MaxBoardColumns || viewSize.Height > MaxBoardRows {
+		return fmt.Errorf("%w: board.default_size %d takes %dx%d terminal cells in the %v board mode, more than %dx%d",
+			ErrInvalidConfig, cfg.Board.DefaultSize, viewSize.Width, viewSize.Height, cfg.Display.BoardMode,
+			MaxBoardColumns, MaxBoardRows)
+	}
+
+	// The packed board modes draw the snake and the candy only, a shaded free cell wouldn't show
+	if cfg.Display.TrapShade && cfg.Display.BoardMode.Packed() {
+		return fmt.Errorf("%w: display.trap_shade only shades the normal and square board modes, not %v",
+			ErrInvalidConfig, cfg.Display.BoardMode)
+	}
+
+	if _, err := theme.Get(cfg.Display.Theme); err != nil {
+		return fmt.Errorf("%w: display: %v", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.Export.validate(); err != nil {
+		return err
+	}
+
+	return cfg.Glyphs.validate()
+}
+
+// CellWidth returns the number of columns of a board cell, the glyphs aren't displayed in the packed board modes
+func (cfg Config) CellWidth() int {
+	switch {
+	case cfg.Display.BoardMode == uimanager.BoardSquare:
+		return 2
+	case cfg.Display.BoardMode.Packed():
+		return 1
+	}
+
+	return cfg.Glyphs.Width()
+}
+
+// BoardViewSize returns the number of terminal columns and rows of a board of boardSize cells
+func (cfg Config) BoardViewSize(boardSize common.Size) common.Size {
+	return cfg.Display.BoardMode.ViewSize(boardSize, cfg.CellWidth())
+}
+
+// Table returns the bindings of the preset with the actions rebound
+func (controls Controls) Table() (bindings keybindings.Bindings, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if bindings, err = keybindings.Preset(controls.Preset); err != nil {
+		return bindings, err
+	}
+
+	bindings.Override(controls.Bindings)
+
+	return bindings, bindings.Validate()
+}
+
+func (glyphs Glyphs) validate() (err error) {
+	if glyphs.Free == glyphs.Snake || glyphs.Free == glyphs.Candy || glyphs.Snake == glyphs.Candy {
+		return fmt.Errorf("%w: the glyphs must be different", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Map returns the glyph displayed for each value of the board cells
+func (glyphs Glyphs) Map() map[rune]rune {
+	return map[rune]rune{
+		gameboard.FreeSpace: rune(glyphs.Free),
+		gameboard.SnakePart: rune(glyphs.Snake),
+		gameboard.CandyBody: rune(glyphs.Candy),
+	}
+}
+
+// Width returns the number of columns of a board cell: the width of the widest glyph
+func (glyphs Glyphs) Width() int {
+	width := 1
+
+	for _, glyph := range glyphs.Map() {
+		if uimanager.RuneWidth(glyph) > width {
+			width = uimanager.RuneWidth(glyph)
+		}
+	}
+
+	return width
+}
+
+// MarshalText writes the glyph as a string
+func (glyph Glyph) MarshalText() ([]byte, error) {
+	return []byte(string(rune(glyph))), nil
+}
+
+// UnmarshalText reads a single character displayed in one or two columns
+func (glyph *Glyph) UnmarshalText(text []byte) error {
+	value, size := utf8.DecodeRune(text)
+	if value == utf8.RuneError || size != len(text) || uimanager.RuneWidth(value) == 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidGlyph, text)
+	}
+
+	*glyph = Glyph(value)
+
+	return nil
+}
+
+func (export Export) validate() (err error) {
+	if export.CellSize < gifexport.MinCellSize || export.CellSize > gifexport.MaxCellSize {
+		return fmt.Errorf("%w: export.cell_size must be between %d and %d, got %d",
+			ErrInvalidConfig, gifexport.MinCellSize, gifexport.MaxCellSize, export.CellSize)
+	}
+
+	if len(export.SnapshotFormats) == 0 {
+		return fmt.Errorf("%w: export.snapshot_formats needs a format at least", ErrInvalidConfig)
+	}
+
+	for _, format := range export.SnapshotFormats {
+		known := false
+		for _, snapshotFormat := range gameboard.SnapshotFormats {
+			known = known || format == snapshotFormat
+		}
+
+		if !known {
+			return fmt.Errorf("%w: export.snapshot_formats: unknown format %q, expected %s",
+				ErrInvalidConfig, format, strings.Join(gameboard.SnapshotFormats, ", "))
+		}
+	}
+
+	return nil
+}
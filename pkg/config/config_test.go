@@ -0,0 +1,282 @@
+package config
+
+import (
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/uimanager"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) (fileName string) {
+	fileName = filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+
+	return fileName
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantConfig  func(t *testing.T, cfg *Config)
+		wantErrType error
+	}{
+		{
+			name:       "TestEmptyFile",
+			wantConfig: func(t *testing.T, cfg *Config) {},
+		},
+		{
+			name: "TestPartialFile", // The missing settings keep their default value
+			content: `
+controls:
+  preset: wasd
+  bindings:
+    up: [w, F1]
+    start: [ENTER]
+    resize: [space]
+timing:
+  refresh_interval: 50ms
+glyphs:
+  candy: "@"
+display:
+  theme: monochrome
+  colors: 256
+export:
+  cell_size: 4
+  directory: /tmp
+  snapshot_formats: [svg]
+`,
+			wantConfig: func(t *testing.T, cfg *Config) {
+				f1, err := uimanager.ParseKey("F1")
+				require.NoError(t, err)
+				cfg.Controls.Preset = "wasd"
+				cfg.Controls.Bindings = keybindings.Bindings{
+					keybindings.ActionUp:     {uimanager.KeyRune('w'), f1},
+					keybindings.ActionStart:  {uimanager.KeyEnter},
+					keybindings.ActionResize: {uimanager.KeySpace},
+				}
+				cfg.Timing.RefreshInterval = 50 * time.Millisecond
+				cfg.Glyphs.Candy = '@'
+				cfg.Display.Theme = "monochrome"
+				cfg.Display.Colors = uimanager.Colors256
+				cfg.Export.CellSize = 4
+				cfg.Export.Directory = "/tmp"
+				cfg.Export.SnapshotFormats = []string{"svg"}
+			},
+		},
+		{
+			name:        "TestUnknownSetting",
+			content:     "timing:\n  refresh: 50ms\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnknownKey",
+			content:     "controls:\n  bindings:\n    up: [ctrl+x]\n",
+			wantErrType: uimanager.ErrUnknownKey,
+		},
+		{
+			name:        "TestUnknownAction",
+			content:     "controls:\n  bindings:\n    jump: [j]\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnknownPreset",
+			content:     "controls:\n  preset: dvorak\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestDuplicateKey",
+			content:     "controls:\n  bindings:\n    up: [down]\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnboundAction",
+			content:     "controls:\n  bindings:\n    quit: []\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestRefreshTooFast",
+			content:     "timing:\n  refresh_interval: 1ms\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:    "TestAttractDisabled",
+			content: "timing:\n  attract_timeout: 0s\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Timing.AttractTimeout = 0
+			},
+		},
+		{
+			name:        "TestNegativeAttractTimeout",
+			content:     "timing:\n  attract_timeout: -1s\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestBoardTooLarge",
+			content:     "board:\n  default_size: 80\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestIncrementTooLarge",
+			content:     "board:\n  size_increment: 50\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:    "TestWideGlyph",
+			content: "board:\n  default_size: 20\nglyphs:\n  candy: 🍎\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Board.DefaultSize = 20
+				cfg.Glyphs.Candy = '🍎'
+			},
+		},
+		{
+			name:        "TestWideGlyphBoardTooLarge",
+			content:     "board:\n  default_size: 41\nglyphs:\n  candy: 🍎\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:    "TestSquareBoard",
+			content: "board:\n  default_size: 20\ndisplay:\n  board_mode: square\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Board.DefaultSize = 20
+				cfg.Display.BoardMode = uimanager.BoardSquare
+			},
+		},
+		{
+			name:    "TestHalfBlockBoard",
+			content: "board:\n  default_size: 80\ndisplay:\n  board_mode: half-block\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Board.DefaultSize = 80
+				cfg.Display.BoardMode = uimanager.BoardHalfBlock
+			},
+		},
+		{
+			name:    "TestBrailleBoard",
+			content: "board:\n  default_size: 160\ndisplay:\n  board_mode: braille\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Board.DefaultSize = 160
+				cfg.Display.BoardMode = uimanager.BoardBraille
+			},
+		},
+		{
+			name:    "TestTrapShade",
+			content: "display:\n  trap_shade: true\n",
+			wantConfig: func(t *testing.T, cfg *Config) {
+				cfg.Display.TrapShade = true
+			},
+		},
+		{
+			name:        "TestTrapShadePacked",
+			content:     "display:\n  trap_shade: true\n  board_mode: braille\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestHalfBlockBoardTooLarge",
+			content:     "board:\n  default_size: 90\ndisplay:\n  board_mode: half-block\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnknownBoardMode",
+			content:     "display:\n  board_mode: round\n",
+			wantErrType: uimanager.ErrUnknownBoardMode,
+		},
+		{
+			name:        "TestCombiningGlyph",
+			content:     "glyphs:\n  candy: \"\\u0301\"\n",
+			wantErrType: ErrInvalidGlyph,
+		},
+		{
+			name:        "TestSeveralCharacters",
+			content:     "glyphs:\n  snake: SS\n",
+			wantErrType: ErrInvalidGlyph,
+		},
+		{
+			name:        "TestUnknownTheme",
+			content:     "display:\n  theme: neon\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnknownColorMode",
+			content:     "display:\n  colors: 16\n",
+			wantErrType: uimanager.ErrUnknownColorMode,
+		},
+		{
+			name:        "TestCellTooLarge",
+			content:     "export:\n  cell_size: 64\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestUnknownSnapshotFormat",
+			content:     "export:\n  snapshot_formats: [png, bmp]\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestNoSnapshotFormat",
+			content:     "export:\n  snapshot_formats: []\n",
+			wantErrType: ErrInvalidConfig,
+		},
+		{
+			name:        "TestSameGlyphs",
+			content:     "glyphs:\n  snake: \"*\"\n",
+			wantErrType: ErrInvalidConfig,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeFile(t, tt.content), false)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			wantConfig := Default()
+			tt.wantConfig(t, &wantConfig)
+			require.Equal(t, wantConfig, cfg)
+		})
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+
+	cfg, err := Load(fileName, true)
+	require.NoError(t, err)
+	require.Equal(t, Default(), cfg)
+
+	_, err = Load(fileName, false)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestWrite(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "gosnake", "config.yaml")
+	cfg := Default()
+	cfg.Glyphs.Snake = 'o'
+
+	require.NoError(t, Write(fileName, cfg, false))
+	require.ErrorIs(t, Write(fileName, cfg, false), ErrConfigExists)
+	require.NoError(t, Write(fileName, cfg, true))
+
+	gotCfg, err := Load(fileName, false)
+	require.NoError(t, err)
+	require.Equal(t, cfg, gotCfg)
+}
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	require.NoError(t, cfg.Validate())
+	bindings, err := cfg.Controls.Table()
+	require.NoError(t, err)
+	require.Equal(t, keybindings.ActionPause, bindings.Action(uimanager.KeyRune('p')))
+	require.Equal(t, 'S', cfg.Glyphs.Map()[gameboard.SnakePart])
+	require.Equal(t, 1, cfg.Glyphs.Width())
+	cfg.Glyphs.Candy = '🍎'
+	require.Equal(t, 2, cfg.Glyphs.Width())
+	cfg.Glyphs.Candy = '*'
+	cfg.Display.BoardMode = uimanager.BoardSquare
+	require.Equal(t, 2, cfg.CellWidth())
+}
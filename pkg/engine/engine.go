@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/keybindings"
+	"time"
+)
+
+// Frontend is what a front-end does for the engine: the terminal, a browser. The nil functions are skipped
+type Frontend struct {
+	BeforeRound func() (err error)                           // Has its say before each round, e.g. the move of a bot
+	DrawRound   func(spriteList []common.Sprite) (err error) // Draws the cells of the round played
+	ShowPause   func(paused bool) (err error)                // Shows the game paused or resumed
+	NewBoard    func() (err error)                           // Sets a new board up before a game starts on a played one
+	StartGame   func() (err error)                           // Starts the game and runs it, cf Run
+}
+
+// Run plays a round of gameState every interval until the game is over, stop is closed or a round fails
+// The rounds are skipped while the game is paused
+func Run(gameState gamestate.GameStater, interval time.Duration, stop <-chan struct{},
+	frontend Frontend) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		if gameState.Paused() {
+			continue
+		}
+
+		// The front-end may end the game before the round, e.g. a bot forfeits
+		if frontend.BeforeRound != nil {
+			if err := frontend.BeforeRound(); err != nil {
+				return err
+			}
+
+			if !gameState.GameInProgress() {
+				return nil
+			}
+		}
+
+		spriteList, err := gameState.Play()
+		if err != nil {
+			return err
+		}
+
+		if frontend.DrawRound != nil {
+			if err := frontend.DrawRound(spriteList); err != nil {
+				return err
+			}
+		}
+
+		if !gameState.GameInProgress() {
+			return nil
+		}
+	}
+}
+
+// HandleAction steers, pauses or starts gameState for action, handled is false for the actions left to the front-end
+func HandleAction(gameState gamestate.GameStater, action keybindings.Action,
+	frontend Frontend) (handled bool, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	switch action {
+	case keybindings.ActionUp, keybindings.ActionDown, keybindings.ActionLeft, keybindings.ActionRight:
+		Steer(gameState, action)
+	case keybindings.ActionPause:
+		// Only a game in progress can be paused
+		if !gameState.GameInProgress() {
+			return true, nil
+		}
+
+		gameState.SetPaused(!gameState.Paused())
+
+		if frontend.ShowPause != nil {
+			return true, frontend.ShowPause(gameState.Paused())
+		}
+	case keybindings.ActionStart:
+		if gameState.GameInProgress() {
+			return true, nil
+		}
+
+		if gameState.Dirty() && frontend.NewBoard != nil {
+			if err := frontend.NewBoard(); err != nil {
+				return true, err
+			}
+		}
+
+		if frontend.StartGame != nil {
+			return true, frontend.StartGame()
+		}
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Steer turns the snake for the move actions, for the keys and the bots
+func Steer(gameState gamestate.GameStater, action keybindings.Action) {
+	switch action {
+	case keybindings.ActionUp:
+		gameState.MoveUp()
+	case keybindings.ActionDown:
+		gameState.MoveDown()
+	case keybindings.ActionLeft:
+		gameState.MoveLeft()
+	case keybindings.ActionRight:
+		gameState.MoveRight()
+	}
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"errors"
+	"gosnake/mocks"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/keybindings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInterval = time.Millisecond
+
+func TestRun(t *testing.T) {
+	playErr := errors.New("play error")
+	drawErr := errors.New("draw error")
+
+	tests := []struct {
+		name       string
+		playErr    error
+		drawErr    error
+		forfeit    bool
+		wantRounds int
+		wantDrawn  int
+		wantErr    error
+	}{
+		{
+			name:       "TestGameOver",
+			wantRounds: 1,
+			wantDrawn:  1,
+		},
+		{
+			name:       "TestPlayError",
+			playErr:    playErr,
+			wantRounds: 1,
+			wantErr:    playErr,
+		},
+		{
+			name:       "TestDrawError",
+			drawErr:    drawErr,
+			wantRounds: 1,
+			wantDrawn:  1,
+			wantErr:    drawErr,
+		},
+		{
+			name:    "TestForfeit",
+			forfeit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := &mocks.GameStater{}
+			aGameState.On("Paused").Return(false)
+			aGameState.On("GameInProgress").Return(false)
+			aGameState.On("Play").Return([]common.Sprite{}, tt.playErr)
+
+			drawn := 0
+			frontend := Frontend{
+				DrawRound: func([]common.Sprite) error {
+					drawn++
+					return tt.drawErr
+				},
+			}
+
+			if tt.forfeit {
+				frontend.BeforeRound = func() error { return nil }
+			}
+
+			err := Run(aGameState, testInterval, nil, frontend)
+			require.ErrorIs(t, err, tt.wantErr)
+			aGameState.AssertNumberOfCalls(t, "Play", tt.wantRounds)
+			require.Equal(t, tt.wantDrawn, drawn)
+		})
+	}
+}
+
+func TestRunStop(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+	gameState.Start()
+	gameState.SetPaused(true)
+
+	// A paused game is stopped without playing
+	stop := make(chan struct{})
+	close(stop)
+
+	require.NoError(t, Run(gameState, testInterval, stop, Frontend{}))
+	require.Equal(t, 0, gameState.Round())
+}
+
+func TestHandleAction(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      keybindings.Action
+		inProgress  bool
+		dirty       bool
+		wantHandled bool
+		wantPaused  []bool
+		wantBoards  int
+		wantStarts  int
+	}{
+		{
+			name:        "TestMove",
+			action:      keybindings.ActionUp,
+			inProgress:  true,
+			wantHandled: true,
+		},
+		{
+			name:        "TestPauseNoGame",
+			action:      keybindings.ActionPause,
+			wantHandled: true,
+		},
+		{
+			name:        "TestPause",
+			action:      keybindings.ActionPause,
+			inProgress:  true,
+			wantHandled: true,
+			wantPaused:  []bool{true},
+		},
+		{
+			name:        "TestStartInProgress",
+			action:      keybindings.ActionStart,
+			inProgress:  true,
+			wantHandled: true,
+		},
+		{
+			name:        "TestStart",
+			action:      keybindings.ActionStart,
+			wantHandled: true,
+			wantStarts:  1,
+		},
+		{
+			name:        "TestStartDirty",
+			action:      keybindings.ActionStart,
+			dirty:       true,
+			wantHandled: true,
+			wantBoards:  1,
+			wantStarts:  1,
+		},
+		{
+			name:   "TestFrontendAction",
+			action: keybindings.ActionTheme,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := &mocks.GameStater{}
+			aGameState.On("GameInProgress").Return(tt.inProgress)
+			aGameState.On("Dirty").Return(tt.dirty)
+			aGameState.On("Paused").Return(false).Once()
+			aGameState.On("SetPaused", true).Return()
+			aGameState.On("Paused").Return(true)
+			aGameState.On("MoveUp").Return()
+
+			var (
+				paused []bool
+				boards int
+				starts int
+			)
+
+			handled, err := HandleAction(aGameState, tt.action, Frontend{
+				ShowPause: func(isPaused bool) error {
+					paused = append(paused, isPaused)
+					return nil
+				},
+				NewBoard: func() error {
+					boards++
+					return nil
+				},
+				StartGame: func() error {
+					starts++
+					return nil
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantHandled, handled)
+			require.Equal(t, tt.wantPaused, paused)
+			require.Equal(t, tt.wantBoards, boards)
+			require.Equal(t, tt.wantStarts, starts)
+
+			if tt.action == keybindings.ActionUp {
+				aGameState.AssertCalled(t, "MoveUp")
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package errorhistory
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/uimanager"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Entry is an error raised by one of the routines
+type Entry struct {
+	Time    time.Time
+	Routine string
+	Err     error
+}
+
+// Keys are the labels of the keys bound to the panel, as shown in its title lines
+type Keys struct {
+	ScrollUp   string
+	ScrollDown string
+	Details    string // Opens and closes the full chains
+	Close      string // Closes the full chains
+}
+
+// ErrorHistoryer is the interface for errorHistory
+type ErrorHistoryer interface {
+	Add(routine string, errMsg error)
+	Len() int
+	Entries() []Entry
+	Scroll(delta int)
+	Expanded() bool
+	SetExpanded(expanded bool)
+	SetKeys(keys Keys)
+	Layout(size common.Size) []string
+}
+
+// errorHistory keeps the errors, the scroll position and the display mode of the error panel
+// Errors are added by the routines while the keys scroll it, hence the mutex
+type errorHistory struct {
+	mutex    sync.Mutex
+	entries  []Entry
+	offset   int
+	expanded bool
+	keys     Keys
+}
+
+// New returns an instance of errorHistory
+func New() ErrorHistoryer {
+	return new(errorHistory)
+}
+
+// Add records an error, the newest error is displayed first
+func (history *errorHistory) Add(routine string, errMsg error) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	history.entries = append(history.entries, Entry{
+		Time:    time.Now(),
+		Routine: routine,
+		Err:     errMsg,
+	})
+	history.offset = 0
+}
+
+// Len returns the number of errors recorded
+func (history *errorHistory) Len() int {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	return len(history.entries)
+}
+
+// Entries returns a copy of the errors, oldest first
+func (history *errorHistory) Entries() []Entry {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	return append([]Entry(nil), history.entries...)
+}
+
+// Scroll moves the first displayed line by delta, it is kept in range by Layout
+func (history *errorHistory) Scroll(delta int) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	history.offset += delta
+	if history.offset < 0 {
+		history.offset = 0
+	}
+}
+
+// Expanded tells whether the full chains are displayed
+func (history *errorHistory) Expanded() bool {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	return history.expanded
+}
+
+// SetExpanded switches between the panel and the full chains, the scroll is reset
+func (history *errorHistory) SetExpanded(expanded bool) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	if history.expanded != expanded {
+		history.offset = 0
+	}
+
+	history.expanded = expanded
+}
+
+// SetKeys defines the labels of the keys shown in the title lines, they follow the bindings of the configuration
+func (history *errorHistory) SetKeys(keys Keys) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	history.keys = keys
+}
+
+// Layout returns exactly size.Height lines of at most size.Width runes from the scroll position
+func (history *errorHistory) Layout(size common.Size) []string {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	var lines []string
+
+	if history.expanded {
+		lines = history.detailLines(size.Width)
+	} else {
+		lines = history.panelLines(size.Width)
+	}
+
+	// The title lines always stay visible
+	const nbTitleLines = 2
+
+	body := lines[nbTitleLines:]
+	maxOffset := len(body) - (size.Height - nbTitleLines)
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+
+	if history.offset > maxOffset {
+		history.offset = maxOffset
+	}
+
+	layout := make([]string, 0, size.Height)
+	layout = append(layout, lines[:nbTitleLines]...)
+	layout = append(layout, body[history.offset:]...)
+
+	for len(layout) < size.Height {
+		layout = append(layout, "")
+	}
+
+	return layout[:size.Height]
+}
+
+func (history *errorHistory) panelLines(width int) (lines []string) {
+	lines = []string{
+		"   Program Error",
+		fmt.Sprintf("%d %s/%s %s", len(history.entries), history.keys.ScrollUp, history.keys.ScrollDown,
+			history.keys.Details),
+	}
+
+	for i := len(history.entries) - 1; i >= 0; i-- {
+		entry := history.entries[i]
+
+		lines = append(lines, "")
+		lines = append(lines, wrap(fmt.Sprintf("#%d %s", i+1, entry.Time.Format("15:04:05")), width)...)
+		lines = append(lines, wrap(entry.Routine, width)...)
+		lines = append(lines, wrap(entry.Err.Error(), width)...)
+	}
+
+	return lines
+}
+
+func (history *errorHistory) detailLines(width int) (lines []string) {
+	const indent = "    "
+
+	lines = []string{
+		fmt.Sprintf(" Program Errors (%d)", len(history.entries)),
+		fmt.Sprintf(" %s/%s to scroll, %s or %s to close", history.keys.ScrollUp, history.keys.ScrollDown,
+			history.keys.Details, history.keys.Close),
+	}
+
+	for i := len(history.entries) - 1; i >= 0; i-- {
+		entry := history.entries[i]
+
+		lines = append(lines, "")
+		lines = append(lines, wrap(fmt.Sprintf(" #%d %s  %s", i+1,
+			entry.Time.Format("2006-01-02 15:04:05"), entry.Routine), width)...)
+
+		// One line per function of the chain built by common.ErrorWrapper
+		for _, frame := range common.ErrorFrames(entry.Err) {
+			lines = append(lines, wrap(indent+frame, width)...)
+		}
+
+		var panicError *common.PanicError
+		if errors.As(entry.Err, &panicError) {
+			lines = append(lines, wrap(fmt.Sprintf("%spanic: %v", indent, panicError.Value), width)...)
+		}
+	}
+
+	return lines
+}
+
+// wrap splits str in lines of width columns
+func wrap(str string, width int) (lines []string) {
+	if width <= 0 {
+		return []string{str}
+	}
+
+	for uimanager.TextWidth(str) > width {
+		line := uimanager.TruncateText(str, width)
+		if line == "" { // A wide rune in a single column
+			_, size := utf8.DecodeRuneInString(str)
+			line = str[:size]
+		}
+
+		lines = append(lines, line)
+		str = str[len(line):]
+	}
+
+	return append(lines, str)
+}
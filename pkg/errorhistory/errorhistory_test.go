@@ -0,0 +1,157 @@
+package errorhistory
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/uimanager"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var panelSize = common.Size{
+	Width:  18,
+	Height: 11,
+}
+
+// testKeys are the labels of the keys of the default bindings
+var testKeys = Keys{ScrollUp: "PGUP", ScrollDown: "PGDN", Details: "TAB", Close: "ESC"}
+
+func TestErrorHistory_Add(t *testing.T) {
+	history := New()
+	require.Equal(t, 0, history.Len())
+
+	history.Add("Game Engine", errors.New("first"))
+	history.Add("Game Over Anim", errors.New("second"))
+
+	entries := history.Entries()
+	require.Len(t, entries, 2)
+	require.Equal(t, "Game Engine", entries[0].Routine)
+	require.Equal(t, "second", entries[1].Err.Error())
+	require.False(t, entries[1].Time.Before(entries[0].Time))
+}
+
+func TestErrorHistory_Layout(t *testing.T) {
+	tests := []struct {
+		name      string
+		errors    []error
+		expanded  bool
+		scroll    int
+		keys      Keys     // testKeys when empty
+		wantLines []string // lines expected in the layout, in order
+		notLines  []string // lines which must not be displayed
+	}{
+		{
+			name:      "TestEmpty",
+			wantLines: []string{"   Program Error", "0 PGUP/PGDN TAB"},
+		},
+		{
+			name:      "TestRemappedKeys",
+			keys:      Keys{ScrollUp: "K", ScrollDown: "J", Details: "E", Close: "X"},
+			wantLines: []string{"   Program Error", "0 K/J E"},
+		},
+		{
+			name:      "TestExpandedKeys",
+			expanded:  true,
+			keys:      Keys{ScrollUp: "K", ScrollDown: "J", Details: "E", Close: "X"},
+			wantLines: []string{" Program Errors (0)", " K/J to scroll, E or X to close"},
+		},
+		{
+			name:      "TestNewestFirst",
+			errors:    []error{errors.New("first"), errors.New("second")},
+			wantLines: []string{"#2", "Game Engine", "second", "#1", "first"},
+		},
+		{
+			name:      "TestLongMessageWrapped",
+			errors:    []error{errors.New("0123456789012345678901234")},
+			wantLines: []string{"012345678901234567", "8901234"},
+		},
+		{
+			name:      "TestWideRunesWrapped", // 18 columns hold 9 wide runes
+			errors:    []error{errors.New("蛇蛇蛇蛇蛇蛇蛇蛇蛇蛇蛇")},
+			wantLines: []string{"蛇蛇蛇蛇蛇蛇蛇蛇蛇", "蛇蛇"},
+		},
+		{
+			name:      "TestNotScrollable",
+			errors:    []error{errors.New("first"), errors.New("second")},
+			scroll:    4,
+			wantLines: []string{"   Program Error", "second", "first"},
+		},
+		{
+			name:      "TestScrolled",
+			errors:    []error{errors.New("first"), errors.New("second"), errors.New("third")},
+			scroll:    2,
+			wantLines: []string{"   Program Error", "3 PGUP/PGDN TAB", "Game Engine", "third"},
+			notLines:  []string{"first"},
+		},
+		{
+			name:      "TestScrollClamped",
+			errors:    []error{errors.New("first"), errors.New("second"), errors.New("third")},
+			scroll:    100,
+			wantLines: []string{"3 PGUP/PGDN TAB", "third", "second", "first"},
+		},
+		{
+			name: "TestExpandedChain",
+			errors: []error{fmt.Errorf("main.gameEngine: %w",
+				fmt.Errorf("gamestate.Play: %w", errors.New("boom")))},
+			expanded:  true,
+			wantLines: []string{"    main.gameEngine", "    gamestate.Play", "    boom"},
+		},
+		{
+			name:      "TestExpandedPanic",
+			errors:    []error{fmt.Errorf("main.gameEngine: %w", common.NewPanicError(5))},
+			expanded:  true,
+			wantLines: []string{"    main.gameEngine", "    5", "    panic: 5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := New()
+			history.SetKeys(testKeys)
+			if tt.keys != (Keys{}) {
+				history.SetKeys(tt.keys)
+			}
+
+			for _, err := range tt.errors {
+				history.Add("Game Engine", err)
+			}
+			history.SetExpanded(tt.expanded)
+			history.Scroll(tt.scroll)
+
+			size := panelSize
+			if tt.expanded {
+				size = common.Size{Width: 60, Height: 40}
+			}
+
+			layout := history.Layout(size)
+			require.Len(t, layout, size.Height)
+			for _, line := range layout {
+				require.LessOrEqual(t, uimanager.TextWidth(line), size.Width)
+			}
+
+			// The wanted lines must appear in order
+			joined := "\n" + strings.Join(layout, "\n") + "\n"
+			position := 0
+			for _, line := range tt.wantLines {
+				index := strings.Index(joined[position:], "\n"+line)
+				require.GreaterOrEqual(t, index, 0, "%q not found in %q", line, layout)
+				position += index + 1
+			}
+			for _, line := range tt.notLines {
+				require.NotContains(t, layout, line)
+			}
+		})
+	}
+}
+
+func TestErrorHistory_SetExpanded(t *testing.T) {
+	history := New()
+	history.Scroll(3)
+	history.Scroll(-5)
+	history.SetExpanded(true)
+	require.True(t, history.Expanded())
+	history.SetExpanded(false)
+	require.False(t, history.Expanded())
+}
@@ -0,0 +1,189 @@
+package errorlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+// Available levels, an entry is written when its level is at least the level of the logger
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// Defines custom errors
+var (
+	ErrInvalidLevel = errors.New("invalid log level")
+	ErrAlreadyOpen  = errors.New("the log file is already open")
+)
+
+// Entry is a structured record of the log file
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+	Frames  []string  `json:"frames,omitempty"`
+	Panic   string    `json:"panic,omitempty"`
+	Stack   []string  `json:"stack,omitempty"`
+}
+
+// ErrorLogger is the interface for errorLogger
+type ErrorLogger interface {
+	Open(fileName string, level Level) (err error)
+	Close() (err error)
+	Enabled(level Level) bool
+	LogError(source string, errMsg error) (err error)
+	LogMessage(level Level, source, msg string) (err error)
+}
+
+// errorLogger writes entries as JSON lines. It does nothing until a file is opened
+type errorLogger struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	level   Level
+}
+
+// New returns an instance of errorLogger
+func New() ErrorLogger {
+	return new(errorLogger)
+}
+
+// ParseLevel returns the level matching name
+func ParseLevel(name string) (level Level, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	for i := range levelNames {
+		if strings.EqualFold(levelNames[i], name) {
+			return Level(i), nil
+		}
+	}
+
+	return level, fmt.Errorf("%w: %q", ErrInvalidLevel, name)
+}
+
+func (level Level) String() string {
+	if level < LevelDebug || level > LevelError {
+		return fmt.Sprint(int(level))
+	}
+
+	return levelNames[level]
+}
+
+// Open creates or appends to the log file, entries below level are ignored
+func (logger *errorLogger) Open(fileName string, level Level) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.file != nil {
+		return ErrAlreadyOpen
+	}
+
+	file, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	logger.file = file
+	logger.encoder = json.NewEncoder(file)
+	logger.level = level
+
+	return nil
+}
+
+// Close closes the log file if any
+func (logger *errorLogger) Close() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.file == nil {
+		return nil
+	}
+
+	err = logger.file.Close()
+	logger.file = nil
+	logger.encoder = nil
+
+	return err
+}
+
+// Enabled tells if an entry of this level would be written
+func (logger *errorLogger) Enabled(level Level) bool {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	return logger.file != nil && level >= logger.level
+}
+
+// LogError writes the error chain, and if the error comes from a panic its value and stack
+func (logger *errorLogger) LogError(source string, errMsg error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if errMsg == nil {
+		return nil
+	}
+
+	entry := Entry{
+		Level:   LevelError.String(),
+		Source:  source,
+		Message: errMsg.Error(),
+		Frames:  common.ErrorFrames(errMsg),
+	}
+
+	var panicError *common.PanicError
+	if errors.As(errMsg, &panicError) {
+		entry.Panic = fmt.Sprint(panicError.Value)
+		entry.Stack = strings.Split(strings.TrimSpace(string(panicError.Stack)), "\n")
+	}
+
+	return logger.write(LevelError, entry)
+}
+
+// LogMessage writes a simple message
+func (logger *errorLogger) LogMessage(level Level, source, msg string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return logger.write(level, Entry{
+		Level:   level.String(),
+		Source:  source,
+		Message: msg,
+	})
+}
+
+func (logger *errorLogger) write(level Level, entry Entry) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.file == nil || level < logger.level {
+		return nil
+	}
+
+	entry.Time = time.Now()
+
+	return logger.encoder.Encode(entry)
+}
@@ -0,0 +1,162 @@
+package errorlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readEntries(t *testing.T, fileName string) (entries []Entry) {
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	return entries
+}
+
+func panicking() (err error) {
+	defer common.ErrorWrapper("panicking", &err)
+
+	var a, b int
+	a = a / b //nolint
+
+	return nil
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		levelName   string
+		wantLevel   Level
+		wantErrType error
+	}{
+		{
+			name:      "TestDebug",
+			levelName: "debug",
+			wantLevel: LevelDebug,
+		},
+		{
+			name:      "TestUpperCase",
+			levelName: "WARN",
+			wantLevel: LevelWarn,
+		},
+		{
+			name:        "TestUnknown",
+			levelName:   "verbose",
+			wantErrType: ErrInvalidLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLevel, err := ParseLevel(tt.levelName)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantLevel, gotLevel)
+		})
+	}
+}
+
+func TestErrorLogger_LogError(t *testing.T) {
+	tests := []struct {
+		name       string
+		errMsg     error
+		wantFrames []string
+		wantPanic  string
+	}{
+		{
+			name:       "TestWrappedError",
+			errMsg:     fmt.Errorf("main.gameEngine: %w", fmt.Errorf("gamestate.Play: %w", errors.New("boom"))),
+			wantFrames: []string{"main.gameEngine", "gamestate.Play", "boom"},
+		},
+		{
+			name:       "TestPanic",
+			errMsg:     fmt.Errorf("main.gameEngine: %w", panicking()),
+			wantFrames: []string{"main.gameEngine", "panicking", "runtime error: integer divide by zero"},
+			wantPanic:  "runtime error: integer divide by zero",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "gosnake.log")
+			logger := New()
+			require.NoError(t, logger.Open(fileName, LevelError))
+			require.NoError(t, logger.LogError("Game Engine", tt.errMsg))
+			require.NoError(t, logger.Close())
+
+			entries := readEntries(t, fileName)
+			require.Len(t, entries, 1)
+			require.Equal(t, "error", entries[0].Level)
+			require.Equal(t, "Game Engine", entries[0].Source)
+			require.Equal(t, tt.wantFrames, entries[0].Frames)
+			require.Equal(t, tt.wantPanic, entries[0].Panic)
+			if tt.wantPanic != "" {
+				require.NotEmpty(t, entries[0].Stack)
+				require.Contains(t, entries[0].Stack[0], "goroutine")
+			} else {
+				require.Empty(t, entries[0].Stack)
+			}
+			require.False(t, entries[0].Time.IsZero())
+		})
+	}
+}
+
+func TestErrorLogger_LogMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       Level
+		msgLevels   []Level
+		wantEntries int
+	}{
+		{
+			name:        "TestLevelDebug",
+			level:       LevelDebug,
+			msgLevels:   []Level{LevelDebug, LevelInfo, LevelWarn},
+			wantEntries: 3,
+		},
+		{
+			name:        "TestLevelWarn",
+			level:       LevelWarn,
+			msgLevels:   []Level{LevelDebug, LevelInfo, LevelWarn},
+			wantEntries: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), "gosnake.log")
+			logger := New()
+			require.NoError(t, logger.Open(fileName, tt.level))
+			require.ErrorIs(t, logger.Open(fileName, tt.level), ErrAlreadyOpen)
+			for _, level := range tt.msgLevels {
+				require.NoError(t, logger.LogMessage(level, "test", "a message"))
+			}
+			require.NoError(t, logger.Close())
+			require.Len(t, readEntries(t, fileName), tt.wantEntries)
+		})
+	}
+}
+
+func TestErrorLogger_NotOpen(t *testing.T) {
+	logger := New()
+	require.False(t, logger.Enabled(LevelError))
+	require.NoError(t, logger.LogError("test", errors.New("ignored")))
+	require.NoError(t, logger.LogMessage(LevelError, "test", "ignored"))
+	require.NoError(t, logger.Close())
+}
@@ -0,0 +1,311 @@
+package evolve
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/tournament"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// populationFormat and populationVersion head the checkpoints of the populations
+const (
+	populationFormat  = "gosnake-population"
+	populationVersion = 2
+)
+
+var (
+	// ErrInvalidSettings is returned when the evolution can't run with the settings
+	ErrInvalidSettings = errors.New("invalid evolution settings")
+	// ErrInvalidCheckpoint is returned when a file doesn't hold a population of these features
+	ErrInvalidCheckpoint = errors.New("invalid population checkpoint")
+)
+
+// Settings of an evolution
+type Settings struct {
+	Population     int // Genomes of each generation
+	Generations    int
+	Games          int // Games played by each genome in a generation, the same ones for all the genomes
+	BoardSize      int
+	MaxRounds      int   // Rounds per game, gamestate.RoundsPerCell for each cell of the board when 0
+	Seed           int64 // The generation g plays the games Seed+g*Games to Seed+(g+1)*Games-1
+	Workers        int   // Games played at once, one per CPU core when 0
+	Elite          int   // Fittest genomes kept as they are in the next generation
+	TournamentSize int   // Genomes drawn to select each parent, the fittest of them is the parent
+	MutationRate   float64
+	MutationScale  float64 // Standard deviation of the mutations
+}
+
+// DefaultSettings evolves a bot beating greedy in a few minutes
+func DefaultSettings() Settings {
+	return Settings{
+		Population:     16,
+		Generations:    10,
+		Games:          4,
+		BoardSize:      10,
+		Seed:           1,
+		Elite:          2,
+		TournamentSize: 3,
+		MutationRate:   0.25,
+		MutationScale:  0.3,
+	}
+}
+
+// Individual is a genome and its fitness in the games of its generation
+type Individual struct {
+	Genome  Genome  `json:"genome"`
+	Fitness float64 `json:"fitness"` // Mean score, plus the share of the rounds survived
+}
+
+// Population is a generation of genomes, the fittest first once evaluated
+type Population struct {
+	Seed        int64 // The seed of the settings, a population resumed with another one wouldn't play the same games
+	Generation  int
+	Evaluated   bool
+	Individuals []Individual
+}
+
+// populationFile is the content of the checkpoints
+type populationFile struct {
+	Format      string       `json:"format"`
+	Version     int          `json:"version"`
+	Features    []string     `json:"features"`
+	Seed        int64        `json:"seed"`
+	Generation  int          `json:"generation"`
+	Evaluated   bool         `json:"evaluated"`
+	Individuals []Individual `json:"individuals"`
+}
+
+// NewPopulation returns the first generation, weights drawn between -1 and 1
+func NewPopulation(settings Settings) *Population {
+	rnd := rand.New(rand.NewSource(settings.Seed))
+	population := &Population{Seed: settings.Seed, Individuals: make([]Individual, settings.Population)}
+
+	for i := range population.Individuals {
+		for feature := range population.Individuals[i].Genome {
+			population.Individuals[i].Genome[feature] = rnd.Float64()*2 - 1
+		}
+	}
+
+	return population
+}
+
+// Best returns the profile of the fittest genome
+func (population *Population) Best() Profile {
+	return Profile{
+		Genome:     population.Individuals[0].Genome,
+		Fitness:    population.Individuals[0].Fitness,
+		Generation: population.Generation,
+	}
+}
+
+// Evolve evaluates and breeds population until the last generation is evaluated
+// progress is called after the evaluation of each generation when it isn't nil
+// A population read from a checkpoint evolves as it would have without interruption
+func Evolve(population *Population, settings Settings, progress func(population *Population) error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := settings.validate(); err != nil {
+		return err
+	}
+
+	if population.Seed != settings.Seed {
+		return fmt.Errorf("%w: the population evolves with the seed %d, not %d", ErrInvalidSettings,
+			population.Seed, settings.Seed)
+	}
+
+	if settings.Workers == 0 {
+		settings.Workers = runtime.NumCPU()
+	}
+
+	for {
+		if !population.Evaluated {
+			if err := evaluate(population, settings); err != nil {
+				return err
+			}
+
+			if progress != nil {
+				if err := progress(population); err != nil {
+					return err
+				}
+			}
+		}
+
+		if population.Generation+1 >= settings.Generations {
+			return nil
+		}
+
+		breed(population, settings)
+	}
+}
+
+func (settings Settings) validate() error {
+	if settings.Population < 2 || settings.Generations < 1 || settings.Games < 1 ||
+		settings.BoardSize < gamestate.MinBoardSize || settings.MaxRounds < 0 || settings.Workers < 0 {
+		return fmt.Errorf("%w: %d genomes, %d generations, %d games on a board of %d, %d rounds, %d workers",
+			ErrInvalidSettings, settings.Population, settings.Generations, settings.Games, settings.BoardSize,
+			settings.MaxRounds, settings.Workers)
+	}
+
+	if settings.Elite < 0 || settings.Elite >= settings.Population || settings.TournamentSize < 1 ||
+		settings.MutationRate < 0 || settings.MutationRate > 1 || settings.MutationScale < 0 {
+		return fmt.Errorf("%w: elite %d, tournament %d, mutation rate %v and scale %v", ErrInvalidSettings,
+			settings.Elite, settings.TournamentSize, settings.MutationRate, settings.MutationScale)
+	}
+
+	return nil
+}
+
+// evaluate plays the games of the generation with each genome, then sorts the genomes the fittest first
+func evaluate(population *Population, settings Settings) error {
+	type job struct {
+		individual int
+		game       int
+	}
+
+	var (
+		results = make([][]tournament.Result, len(population.Individuals))
+		jobs    = make(chan job)
+		wait    sync.WaitGroup
+		seed    = settings.Seed + int64(population.Generation*settings.Games)
+	)
+
+	for i := range results {
+		results[i] = make([]tournament.Result, settings.Games)
+	}
+
+	// Each worker plays the games it takes, the results keep their place
+	for worker := 0; worker < settings.Workers; worker++ {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			for aJob := range jobs {
+				results[aJob.individual][aJob.game] = tournament.Play(
+					NewBot(population.Individuals[aJob.individual].Genome), settings.BoardSize,
+					seed+int64(aJob.game), settings.MaxRounds)
+			}
+		}()
+	}
+
+	for individual := range results {
+		for game := 0; game < settings.Games; game++ {
+			jobs <- job{individual: individual, game: game}
+		}
+	}
+
+	close(jobs)
+	wait.Wait()
+
+	maxRounds := settings.MaxRounds
+	if maxRounds == 0 {
+		maxRounds = gamestate.RoundsPerCell * settings.BoardSize * settings.BoardSize
+	}
+
+	for i, games := range results {
+		fitness := 0.0
+
+		for _, result := range games {
+			if result.Err != nil {
+				return result.Err
+			}
+
+			fitness += float64(result.Score) + float64(result.Rounds)/float64(maxRounds+1)
+		}
+
+		population.Individuals[i].Fitness = fitness / float64(settings.Games)
+	}
+
+	sort.SliceStable(population.Individuals, func(i, j int) bool {
+		return population.Individuals[i].Fitness > population.Individuals[j].Fitness
+	})
+
+	population.Evaluated = true
+
+	return nil
+}
+
+// breed replaces population with the next generation: the elite, then children of parents selected by tournament
+// The draws only depend on the seed and the generation
+func breed(population *Population, settings Settings) {
+	rnd := rand.New(rand.NewSource(settings.Seed + int64(population.Generation) + 1))
+	parents := population.Individuals
+
+	// selectParent returns the fittest of genomes drawn at random, the parents are sorted the fittest first
+	selectParent := func() Genome {
+		best := rnd.Intn(len(parents))
+		for i := 1; i < settings.TournamentSize; i++ {
+			if drawn := rnd.Intn(len(parents)); drawn < best {
+				best = drawn
+			}
+		}
+
+		return parents[best].Genome
+	}
+
+	children := make([]Individual, 0, settings.Population)
+	for i := 0; i < settings.Elite && i < len(parents); i++ {
+		children = append(children, Individual{Genome: parents[i].Genome})
+	}
+
+	for len(children) < settings.Population {
+		mother, father := selectParent(), selectParent()
+
+		var child Genome
+		for feature := range child {
+			child[feature] = mother[feature]
+			if rnd.Intn(2) == 1 {
+				child[feature] = father[feature]
+			}
+
+			if rnd.Float64() < settings.MutationRate {
+				child[feature] += rnd.NormFloat64() * settings.MutationScale
+			}
+		}
+
+		children = append(children, Individual{Genome: child})
+	}
+
+	population.Generation++
+	population.Evaluated = false
+	population.Individuals = children
+}
+
+// SaveFile writes the population to a checkpoint
+func (population *Population) SaveFile(fileName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return writeJSON(fileName, populationFile{
+		Format:      populationFormat,
+		Version:     populationVersion,
+		Features:    FeatureNames[:],
+		Seed:        population.Seed,
+		Generation:  population.Generation,
+		Evaluated:   population.Evaluated,
+		Individuals: population.Individuals,
+	})
+}
+
+// LoadPopulation reads a checkpoint written by SaveFile
+func LoadPopulation(fileName string) (population *Population, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var file populationFile
+	if err := readJSON(fileName, &file, ErrInvalidCheckpoint); err != nil {
+		return nil, err
+	}
+
+	if file.Format != populationFormat || file.Version != populationVersion ||
+		fmt.Sprint(file.Features) != fmt.Sprint(FeatureNames[:]) || len(file.Individuals) < 2 {
+		return nil, fmt.Errorf("%w: %s version %d, features %v, %d genomes", ErrInvalidCheckpoint,
+			file.Format, file.Version, file.Features, len(file.Individuals))
+	}
+
+	return &Population{Seed: file.Seed, Generation: file.Generation, Evaluated: file.Evaluated,
+		Individuals: file.Individuals}, nil
+}
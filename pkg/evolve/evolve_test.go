@@ -0,0 +1,112 @@
+package evolve
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// smallSettings evolve a few genomes on small boards
+func smallSettings() Settings {
+	settings := DefaultSettings()
+	settings.Population, settings.Generations, settings.Games = 6, 3, 2
+	settings.BoardSize, settings.MaxRounds, settings.Workers = 6, 150, 2
+
+	return settings
+}
+
+func TestEvolve(t *testing.T) {
+	settings := smallSettings()
+
+	population := NewPopulation(settings)
+	generations := 0
+	require.NoError(t, Evolve(population, settings, func(population *Population) error {
+		require.Equal(t, generations, population.Generation)
+		require.True(t, population.Evaluated)
+		require.Len(t, population.Individuals, settings.Population)
+
+		for i := 1; i < len(population.Individuals); i++ {
+			require.GreaterOrEqual(t, population.Individuals[i-1].Fitness, population.Individuals[i].Fitness)
+		}
+
+		generations++
+
+		return nil
+	}))
+	require.Equal(t, settings.Generations, generations)
+	require.Equal(t, settings.Generations-1, population.Best().Generation)
+	require.Greater(t, population.Best().Fitness, 0.0)
+
+	// The evolution is repeatable, whatever the number of workers
+	settings.Workers = 1
+	again := NewPopulation(settings)
+	require.NoError(t, Evolve(again, settings, nil))
+	require.Equal(t, population, again)
+
+	// A population resumed from its checkpoint ends as the uninterrupted one
+	checkpoint := filepath.Join(t.TempDir(), "population.json")
+	resumed := NewPopulation(settings)
+	settings.Generations = 2
+	require.NoError(t, Evolve(resumed, settings, func(population *Population) error {
+		return population.SaveFile(checkpoint)
+	}))
+
+	resumed, err := LoadPopulation(checkpoint)
+	require.NoError(t, err)
+	settings.Generations = 3
+	require.NoError(t, Evolve(resumed, settings, nil))
+	require.Equal(t, population, resumed)
+
+	// The checkpoint is replaced whole, nothing is left beside it
+	entries, err := os.ReadDir(filepath.Dir(checkpoint))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// The population resumed with another seed wouldn't play the same games
+	resumed, err = LoadPopulation(checkpoint)
+	require.NoError(t, err)
+	settings.Seed++
+	require.ErrorIs(t, Evolve(resumed, settings, nil), ErrInvalidSettings)
+}
+
+func TestEvolveErrors(t *testing.T) {
+	for _, change := range []func(settings *Settings){
+		func(settings *Settings) { settings.Population = 1 },
+		func(settings *Settings) { settings.Generations = 0 },
+		func(settings *Settings) { settings.Games = 0 },
+		func(settings *Settings) { settings.BoardSize = 2 },
+		func(settings *Settings) { settings.Elite = settings.Population },
+		func(settings *Settings) { settings.TournamentSize = 0 },
+		func(settings *Settings) { settings.MutationRate = 2 },
+	} {
+		settings := smallSettings()
+		change(&settings)
+		require.ErrorIs(t, Evolve(NewPopulation(smallSettings()), settings, nil), ErrInvalidSettings)
+	}
+
+	// The errors of progress stop the evolution
+	require.ErrorIs(t, Evolve(NewPopulation(smallSettings()), smallSettings(), func(*Population) error {
+		return os.ErrPermission
+	}), os.ErrPermission)
+}
+
+func TestLoadPopulation(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "population.json")
+
+	_, err := LoadPopulation(fileName)
+	require.Error(t, err)
+
+	for _, content := range []string{
+		"not json",
+		`{"format":"gosnake-genome","version":2,"features":["candy","area","tail","walls"],"individuals":[{},{}]}`,
+		`{"format":"gosnake-population","version":1,"features":["candy","area","tail","walls"],"individuals":[{},{}]}`,
+		`{"format":"gosnake-population","version":2,"features":["candy","area"],"individuals":[{},{}]}`,
+		`{"format":"gosnake-population","version":2,"features":["candy","area","tail","walls"],"individuals":[{}]}`,
+	} {
+		require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+		_, err := LoadPopulation(fileName)
+		require.ErrorIs(t, err, ErrInvalidCheckpoint, content)
+	}
+}
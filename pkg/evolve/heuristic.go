@@ -0,0 +1,252 @@
+package evolve
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/grid"
+	"gosnake/pkg/strategy"
+	"os"
+	"path/filepath"
+)
+
+// Features of a move weighted by the genomes, each one is between 0 and 1
+const (
+	FeatureCandy = iota // Closeness to the candy, through the sides of the board
+	FeatureArea         // Share of the free cells the head can still reach
+	FeatureTail         // 1 when the tail can be reached, the snake can follow it to get out
+	FeatureWalls        // Share of the neighbours of the head which are the snake, the board has no other wall
+	FeatureCount
+)
+
+// FeatureNames name the weights in the profiles
+var FeatureNames = [FeatureCount]string{"candy", "area", "tail", "walls"}
+
+// profileFormat and profileVersion head the files of the profiles
+const (
+	profileFormat  = "gosnake-genome"
+	profileVersion = 1
+)
+
+// ErrInvalidProfile is returned when a file doesn't hold a profile of these features
+var ErrInvalidProfile = errors.New("invalid bot profile")
+
+// Genome holds the weight of each feature
+type Genome [FeatureCount]float64
+
+// Profile is a genome exported for the autopilot
+type Profile struct {
+	Genome     Genome
+	Fitness    float64
+	Generation int
+}
+
+// profileFile is the content of the files, the weights are named
+type profileFile struct {
+	Format     string             `json:"format"`
+	Version    int                `json:"version"`
+	Weights    map[string]float64 `json:"weights"`
+	Fitness    float64            `json:"fitness"`
+	Generation int                `json:"generation"`
+}
+
+// SaveFile writes the profile to a file as JSON
+func (profile Profile) SaveFile(fileName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	file := profileFile{
+		Format:     profileFormat,
+		Version:    profileVersion,
+		Weights:    make(map[string]float64, FeatureCount),
+		Fitness:    profile.Fitness,
+		Generation: profile.Generation,
+	}
+
+	for feature, name := range FeatureNames {
+		file.Weights[name] = profile.Genome[feature]
+	}
+
+	return writeJSON(fileName, file)
+}
+
+// LoadProfile reads a profile written by SaveFile, each feature must have its weight
+func LoadProfile(fileName string) (profile Profile, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var file profileFile
+	if err := readJSON(fileName, &file, ErrInvalidProfile); err != nil {
+		return profile, err
+	}
+
+	if file.Format != profileFormat || file.Version != profileVersion || len(file.Weights) != FeatureCount {
+		return profile, fmt.Errorf("%w: %s version %d, %d weights", ErrInvalidProfile,
+			file.Format, file.Version, len(file.Weights))
+	}
+
+	for feature, name := range FeatureNames {
+		weight, ok := file.Weights[name]
+		if !ok {
+			return profile, fmt.Errorf("%w: no weight for %s", ErrInvalidProfile, name)
+		}
+
+		profile.Genome[feature] = weight
+	}
+
+	profile.Fitness, profile.Generation = file.Fitness, file.Generation
+
+	return profile, nil
+}
+
+// writeJSON replaces a file with content, it is written beside it first so an interruption leaves the former one whole
+func writeJSON(fileName string, content interface{}) error {
+	file, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // Nothing is left once the file is renamed
+
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := json.NewEncoder(file).Encode(content); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), fileName)
+}
+
+// readJSON decodes a file, invalid wraps the errors of content
+func readJSON(fileName string, content interface{}, invalid error) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(content); err != nil {
+		return fmt.Errorf("%w: %v", invalid, err)
+	}
+
+	return nil
+}
+
+// Features returns the features of a move of the snake in direction
+func Features(state strategy.State, direction common.Direction) (features [FeatureCount]float64) {
+	var (
+		head = state.Next(state.Head(), direction)
+		eats = state.CandyAlive && head == state.Candy
+		tail = state.Body[0]
+	)
+
+	if state.CandyAlive {
+		features[FeatureCandy] = 1 - float64(state.Distance(head, state.Candy))/
+			float64(state.Size.Width/2+state.Size.Height/2)
+	}
+
+	// The tail leaves its cell unless the snake grows, the cell of the new tail is then free next round
+	blocked := func(position common.Position) bool {
+		return state.Cells[position.X][position.Y] == gameboard.SnakePart && (eats || position != tail)
+	}
+
+	newTail := head
+	switch {
+	case eats:
+		newTail = tail
+	case len(state.Body) > 1:
+		newTail = state.Body[1]
+	}
+
+	free := state.Size.Width*state.Size.Height - len(state.Body)
+	if !eats {
+		free++
+	}
+
+	distances := grid.DistanceMap(state.Grid(), head,
+		func(position common.Position) bool { return !blocked(position) })
+
+	reached := 0
+	for x := range distances {
+		for _, distance := range distances[x] {
+			if distance != grid.Unreachable {
+				reached++
+			}
+		}
+	}
+
+	features[FeatureArea] = float64(reached) / float64(free)
+
+	if newTail == head {
+		features[FeatureTail] = 1
+	}
+
+	for _, aDirection := range strategy.Directions {
+		if around := state.Next(newTail, aDirection); distances[around.X][around.Y] != grid.Unreachable {
+			features[FeatureTail] = 1
+		}
+
+		if blocked(state.Next(head, aDirection)) {
+			features[FeatureWalls] += 1.0 / float64(len(strategy.Directions))
+		}
+	}
+
+	return features
+}
+
+// Score returns the value of a move for genome, the weighted sum of its features
+func (genome Genome) Score(state strategy.State, direction common.Direction) (score float64) {
+	for feature, value := range Features(state, direction) {
+		score += genome[feature] * value
+	}
+
+	return score
+}
+
+// bot plays the safe move of greatest score for its genome
+type bot struct {
+	genome Genome
+}
+
+// NewBot returns a strategy playing genome
+func NewBot(genome Genome) strategy.Strategyer {
+	return &bot{genome: genome}
+}
+
+func (aBot *bot) Name() string {
+	return "heuristic"
+}
+
+// NextMove plays the safe move of greatest score, the current direction wins the ties
+func (aBot *bot) NextMove(state strategy.State) common.Direction {
+	if len(state.Body) == 0 {
+		return state.Direction
+	}
+
+	best, bestScore, found := state.Direction, 0.0, false
+
+	for _, direction := range append([]common.Direction{state.Direction}, strategy.Directions...) {
+		if !state.Safe(direction) {
+			continue
+		}
+
+		score := aBot.genome.Score(state, direction)
+		if !found || score > bestScore {
+			best, bestScore, found = direction, score, true
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,131 @@
+package evolve
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/strategy/strategytest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// boardSize is the size of the boards of the tests
+var boardSize = common.Size{Width: 10, Height: 10}
+
+// pocketState has the free cell (4,4) walled in by the snake, on the left of the head
+func pocketState() strategy.State {
+	return strategytest.New(boardSize, []common.Position{{X: 5, Y: 3}, {X: 4, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 4},
+		{X: 3, Y: 5}, {X: 4, Y: 5}, {X: 5, Y: 5}, {X: 6, Y: 5}, {X: 6, Y: 4}, {X: 5, Y: 4}}, strategy.Left,
+		&common.Position{X: 5, Y: 8})
+}
+
+func TestFeatures(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     strategy.State
+		direction common.Direction
+		want      [FeatureCount]float64
+	}{
+		{
+			name:      "TestOpenBoard",
+			state:     strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Down, &common.Position{X: 5, Y: 8}),
+			direction: strategy.Down,
+			want:      [FeatureCount]float64{0.8, 1, 1, 0},
+		},
+		{
+			name:      "TestCandyThroughTheSide",
+			state:     strategytest.New(boardSize, []common.Position{{X: 1, Y: 5}}, strategy.Left, &common.Position{X: 9, Y: 5}),
+			direction: strategy.Left,
+			want:      [FeatureCount]float64{0.9, 1, 1, 0},
+		},
+		{
+			name:      "TestPocket", // The snake can only follow its tail out of the pocket
+			state:     pocketState(),
+			direction: strategy.Left,
+			want:      [FeatureCount]float64{0.5, 1.0 / 91, 1, 1},
+		},
+		{
+			name:      "TestOutOfThePocket", // The tail leaves its cell, the head takes it
+			state:     pocketState(),
+			direction: strategy.Up,
+			want:      [FeatureCount]float64{0.5, 90.0 / 91, 1, 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDeltaSlice(t, tt.want[:], func() []float64 {
+				features := Features(tt.state, tt.direction)
+				return features[:]
+			}(), 1e-9)
+		})
+	}
+}
+
+func TestBot_NextMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		genome Genome
+		state  strategy.State
+		want   common.Direction
+	}{
+		{
+			name:   "TestArea",
+			genome: Genome{0, 1, 0, 0},
+			state:  pocketState(),
+			want:   strategy.Up,
+		},
+		{
+			name:   "TestWalls",
+			genome: Genome{0, 0, 0, 1},
+			state:  pocketState(),
+			want:   strategy.Left,
+		},
+		{
+			name:   "TestTieKeepsTheDirection",
+			genome: Genome{},
+			state:  strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Down, &common.Position{X: 1, Y: 1}),
+			want:   strategy.Down,
+		},
+		{
+			name:   "TestCandy",
+			genome: Genome{1, 0, 0, 0},
+			state:  strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Down, &common.Position{X: 8, Y: 5}),
+			want:   strategy.Right,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aBot := NewBot(tt.genome)
+			require.Equal(t, "heuristic", aBot.Name())
+			require.Equal(t, tt.want, aBot.NextMove(tt.state))
+		})
+	}
+}
+
+func TestProfile_SaveLoad(t *testing.T) {
+	profile := Profile{Genome: Genome{0.5, 1.5, -0.25, -1}, Fitness: 12.5, Generation: 3}
+
+	fileName := filepath.Join(t.TempDir(), "bot.json")
+	require.NoError(t, profile.SaveFile(fileName))
+
+	loaded, err := LoadProfile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, profile, loaded)
+
+	_, err = LoadProfile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+
+	for _, content := range []string{
+		"not json",
+		`{"format":"gosnake-qtable","version":1,"weights":{"candy":1,"area":1,"tail":1,"walls":1}}`,
+		`{"format":"gosnake-genome","version":2,"weights":{"candy":1,"area":1,"tail":1,"walls":1}}`,
+		`{"format":"gosnake-genome","version":1,"weights":{"candy":1,"area":1,"tail":1}}`,
+		`{"format":"gosnake-genome","version":1,"weights":{"candy":1,"area":1,"tail":1,"speed":1}}`,
+	} {
+		require.NoError(t, os.WriteFile(fileName, []byte(content), 0o600))
+		_, err := LoadProfile(fileName)
+		require.ErrorIs(t, err, ErrInvalidProfile, content)
+	}
+}
@@ -1,12 +1,15 @@
 package gameboard
 
 import (
-	"crypto/rand"
+	cryptorand "crypto/rand"
+	"encoding/binary"
 	"errors"
 	"gosnake/pkg/candy"
 	"gosnake/pkg/common"
+	"gosnake/pkg/grid"
 	"gosnake/pkg/snake"
-	"math/big"
+	"math/rand"
+	"time"
 )
 
 // Objects' body representation
@@ -22,6 +25,7 @@ var (
 	ErrInvalidCandyReference = errors.New("the candy object is nil")
 	ErrInvalidSize           = errors.New("invalid board size")
 	ErrInvalidPosition       = errors.New("invalid position")
+	ErrBoardFull             = errors.New("the board is full")
 )
 
 // GameBoarder is the interface defining gameBoard exported methods
@@ -41,6 +45,21 @@ type GameBoarder interface {
 	RemoveCandy()
 	CreateCandy() (sprite common.Sprite, err error)
 	RandomFreePosition() (position common.Position, err error)
+	Checkpoint() (checkpoint common.BoardCheckpoint, err error)
+	Restore(checkpoint common.BoardCheckpoint) (err error)
+	Sprites() []common.Sprite
+	SetSeed(seed int64)
+	Snapshot() common.BoardSnapshot
+	Grid() grid.Grider
+	FloodFill(start common.Position) (region []common.Position)
+	Reachable(from, to common.Position) bool
+	TrapRegion() (region []common.Position, trapped bool, err error)
+}
+
+// Cloner is a GameBoarder which can be copied, cf gamestate.Cloner
+type Cloner interface {
+	GameBoarder
+	Clone(seed int64) GameBoarder
 }
 
 // gameBoard defines the properties of a game board
@@ -49,6 +68,8 @@ type gameBoard struct {
 	board       [][]rune
 	movingSnake snake.Snaker
 	candy       candy.Candyer
+	seed        int64
+	rnd         *rand.Rand // Places the candies, the same seed gives the same positions
 }
 
 // New returns an instance of gameBoard
@@ -56,9 +77,26 @@ func New() GameBoarder {
 	var aGameBoard gameBoard
 	aGameBoard.movingSnake = snake.New()
 	aGameBoard.candy = candy.New()
+	aGameBoard.SetSeed(newSeed())
 	return &aGameBoard
 }
 
+// SetSeed restarts the random positions from seed, a game can be played again
+func (aGameBoard *gameBoard) SetSeed(seed int64) {
+	aGameBoard.seed = seed
+	aGameBoard.rnd = rand.New(rand.NewSource(seed))
+}
+
+// newSeed draws the seed of a new board
+func newSeed() int64 {
+	var data [8]byte
+	if _, err := cryptorand.Read(data[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+
+	return int64(binary.LittleEndian.Uint64(data[:]))
+}
+
 func (aGameBoard *gameBoard) InitGameBoard(size common.Size) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
@@ -182,16 +220,21 @@ func (aGameBoard *gameBoard) RandomFreePosition() (position common.Position, err
 	if aGameBoard.size.Width <= 0 || aGameBoard.size.Height <= 0 {
 		return position, ErrInvalidSize
 	}
+	// The snake fills the board, there is no position left
+	if !aGameBoard.hasFreeSpace() {
+		return position, ErrBoardFull
+	}
+
 	// Randomly defines the candy position
 	maxW := aGameBoard.size.Width
 	maxH := aGameBoard.size.Height
 
-	rndX, err := random(maxW)
+	rndX, err := aGameBoard.random(maxW)
 	if err != nil {
 		return position, err
 	}
 
-	rndY, err := random(maxH)
+	rndY, err := aGameBoard.random(maxH)
 	if err != nil {
 		return position, err
 	}
@@ -206,12 +249,12 @@ func (aGameBoard *gameBoard) RandomFreePosition() (position common.Position, err
 	}
 
 	for val != FreeSpace {
-		rndX, err = random(maxW)
+		rndX, err = aGameBoard.random(maxW)
 		if err != nil {
 			break
 		}
 
-		rndY, err = random(maxH)
+		rndY, err = aGameBoard.random(maxH)
 		if err != nil {
 			break
 		}
@@ -231,13 +274,153 @@ func (aGameBoard *gameBoard) RandomFreePosition() (position common.Position, err
 	}, err
 }
 
-func random(max int) (rnd int, err error) {
-	aBig, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
-	if err == nil {
-		rnd = int(aBig.Int64())
+func (aGameBoard *gameBoard) hasFreeSpace() bool {
+	for i := range aGameBoard.board {
+		for j := range aGameBoard.board[i] {
+			if aGameBoard.board[i][j] == FreeSpace {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// random returns a number in [0, max) drawn from the seed of the board
+func (aGameBoard *gameBoard) random(max int) (rnd int, err error) {
+	if max <= 0 {
+		return rnd, ErrInvalidSize
+	}
+
+	if aGameBoard.rnd == nil {
+		aGameBoard.SetSeed(newSeed())
+	}
+
+	return aGameBoard.rnd.Intn(max), nil
+}
+
+// Checkpoint copies the state of the board so it can be restored later
+func (aGameBoard *gameBoard) Checkpoint() (checkpoint common.BoardCheckpoint, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameBoard.movingSnake == nil {
+		return checkpoint, ErrInvalidSnakeReference
+	}
+
+	if aGameBoard.candy == nil {
+		return checkpoint, ErrInvalidCandyReference
+	}
+
+	checkpoint.Size = aGameBoard.size
+	checkpoint.Board = make([][]rune, len(aGameBoard.board))
+
+	for i := range aGameBoard.board {
+		checkpoint.Board[i] = append([]rune(nil), aGameBoard.board[i]...)
 	}
 
-	return rnd, err
+	checkpoint.SnakeBody = aGameBoard.movingSnake.Body()
+	checkpoint.SnakeDirection = aGameBoard.movingSnake.Direction()
+	checkpoint.CandyPosition = aGameBoard.candy.Position()
+	checkpoint.CandyAlive = aGameBoard.candy.Alive()
+
+	return checkpoint, nil
+}
+
+// Restore recreates the board and its objects from a checkpoint
+func (aGameBoard *gameBoard) Restore(checkpoint common.BoardCheckpoint) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aGameBoard.createBoard(checkpoint.Size); err != nil {
+		return err
+	}
+
+	if len(checkpoint.Board) != checkpoint.Size.Width {
+		return ErrInvalidSize
+	}
+
+	for i := range checkpoint.Board {
+		if len(checkpoint.Board[i]) != checkpoint.Size.Height {
+			return ErrInvalidSize
+		}
+
+		copy(aGameBoard.board[i], checkpoint.Board[i])
+	}
+
+	aGameBoard.movingSnake = snake.New()
+	aGameBoard.movingSnake.SetDirection(checkpoint.SnakeDirection)
+
+	for _, position := range checkpoint.SnakeBody {
+		if err := aGameBoard.movingSnake.GrowTo(position); err != nil {
+			return err // Shouldn't happen
+		}
+	}
+
+	aGameBoard.candy = candy.New()
+	aGameBoard.candy.Init(checkpoint.CandyPosition)
+
+	if !checkpoint.CandyAlive {
+		aGameBoard.candy.Remove()
+	}
+
+	return nil
+}
+
+// Sprites returns a sprite for each occupied cell, allowing the whole board to be redrawn
+func (aGameBoard *gameBoard) Sprites() (listSprite []common.Sprite) {
+	for x := range aGameBoard.board {
+		for y := range aGameBoard.board[x] {
+			if aGameBoard.board[x][y] == FreeSpace {
+				continue
+			}
+
+			listSprite = append(listSprite, common.Sprite{
+				Value: aGameBoard.board[x][y],
+				Position: common.Position{
+					X: x,
+					Y: y,
+				},
+			})
+		}
+	}
+
+	return listSprite
+}
+
+// Snapshot copies the cells of the board, the score and the round are left to the game state
+func (aGameBoard *gameBoard) Snapshot() (snapshot common.BoardSnapshot) {
+	snapshot.Size = aGameBoard.size
+	snapshot.Seed = aGameBoard.seed
+	snapshot.Cells = make([][]rune, len(aGameBoard.board))
+
+	for i := range aGameBoard.board {
+		snapshot.Cells[i] = append([]rune(nil), aGameBoard.board[i]...)
+	}
+
+	return snapshot
+}
+
+// Clone returns a copy of the board and its objects which plays on its own
+// The candies of the copy are drawn from seed, the copies can't change the board nor its random positions
+func (aGameBoard *gameBoard) Clone(seed int64) GameBoarder {
+	aClone := &gameBoard{size: aGameBoard.size, board: make([][]rune, len(aGameBoard.board))}
+	aClone.SetSeed(seed)
+
+	// The columns share an allocation, the boards are copied once per rollout
+	cells := make([]rune, aGameBoard.size.Width*aGameBoard.size.Height)
+	for i := range aGameBoard.board {
+		aClone.board[i] = cells[i*aGameBoard.size.Height : (i+1)*aGameBoard.size.Height : (i+1)*aGameBoard.size.Height]
+		copy(aClone.board[i], aGameBoard.board[i])
+	}
+
+	if aGameBoard.movingSnake != nil {
+		aClone.movingSnake = aGameBoard.movingSnake.Clone()
+	}
+
+	if aGameBoard.candy != nil {
+		aClone.candy = aGameBoard.candy.Clone()
+	}
+
+	return aClone
 }
 
 func (aGameBoard *gameBoard) SetSnakeDirection(direction common.Direction) {
@@ -393,28 +576,13 @@ func (aGameBoard *gameBoard) checkPosition(position common.Position) bool {
 
 func (aGameBoard *gameBoard) translatePosition(requestedPosition common.Position) (translatedPostion common.Position, err error) {
 	// The position is kept inside the board
-	// If it gets out one side, it enters the other side
+	// If it gets out one side, the grid makes it enter the other side
 
 	if aGameBoard.size.Width <= 0 || aGameBoard.size.Height <= 0 {
 		return translatedPostion, ErrInvalidSize
 	}
 
-	translatedPosition := requestedPosition
-	maxW := aGameBoard.size.Width - 1
-	maxH := aGameBoard.size.Height - 1
-
-	if translatedPosition.X < 0 {
-		translatedPosition.X = maxW
-	}
-	if translatedPosition.X > maxW {
-		translatedPosition.X = 0
-	}
-	if translatedPosition.Y < 0 {
-		translatedPosition.Y = maxH
-	}
-	if translatedPosition.Y > maxH {
-		translatedPosition.Y = 0
-	}
+	translatedPostion, _ = grid.Step(aGameBoard.Grid(), requestedPosition, common.Direction{})
 
-	return translatedPosition, nil
+	return translatedPostion, nil
 }
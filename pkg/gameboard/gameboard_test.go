@@ -672,6 +672,14 @@ func TestGameBoard_RandomFreePosition(t *testing.T) {
 			wantPosition: testdata.Position1_1,
 			wantErr:      false,
 		},
+		{
+			name: "TestBoard3_3_Full",
+			fields: fields{
+				size:  testdata.Size3_3,
+				board: testdata.Duplicate(testdata.Board3_3),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1228,7 +1236,7 @@ func Test_random(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRnd, err := random(tt.args.max)
+			gotRnd, err := (&gameBoard{}).random(tt.args.max)
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
 			require.Equal(t, tt.wantRnd, gotRnd)
@@ -1347,3 +1355,105 @@ func Test_gameBoard_getOldValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGameBoard_Checkpoint(t *testing.T) {
+	tests := []struct {
+		name        string
+		candyAlive  bool
+		size        common.Size
+		wantErrType error
+	}{
+		{
+			name:       "TestCandyAlive",
+			candyAlive: true,
+			size:       common.Size{Width: 6, Height: 4},
+		},
+		{
+			name: "TestCandyEaten",
+			size: common.Size{Width: 6, Height: 4},
+		},
+		{
+			name:        "TestInvalidSize",
+			size:        common.Size{Width: 4, Height: 4},
+			wantErrType: ErrInvalidSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard := New()
+			require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 6, Height: 4}))
+			_, err := aGameBoard.CreateSnake(testdata.Position1_2, testdata.Direction1_0)
+			require.NoError(t, err)
+			_, err = aGameBoard.CreateCandy()
+			require.NoError(t, err)
+			if !tt.candyAlive {
+				aGameBoard.RemoveCandy()
+			}
+
+			checkpoint, err := aGameBoard.Checkpoint()
+			require.NoError(t, err)
+			checkpoint.Size = tt.size
+
+			restored := New()
+			err = restored.Restore(checkpoint)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, aGameBoard.Sprites(), restored.Sprites())
+			require.Equal(t, tt.candyAlive, restored.CandyAlive())
+
+			// Both boards play the same move
+			_, wantSprites, err := aGameBoard.MoveSnake()
+			require.NoError(t, err)
+			_, gotSprites, err := restored.MoveSnake()
+			require.NoError(t, err)
+			require.Equal(t, wantSprites, gotSprites)
+		})
+	}
+}
+
+func TestGameBoard_CheckpointNoObjects(t *testing.T) {
+	aGameBoard := &gameBoard{}
+	_, err := aGameBoard.Checkpoint()
+	require.ErrorIs(t, err, ErrInvalidSnakeReference)
+	require.Empty(t, aGameBoard.Sprites())
+}
+
+func TestGameBoard_Clone(t *testing.T) {
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 6, Height: 4}))
+	_, err := aGameBoard.CreateSnake(testdata.Position1_2, testdata.Direction1_0)
+	require.NoError(t, err)
+	_, err = aGameBoard.CreateCandy()
+	require.NoError(t, err)
+
+	want, err := aGameBoard.Checkpoint()
+	require.NoError(t, err)
+
+	aClone := aGameBoard.(Cloner).Clone(7)
+	got, err := aClone.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// The clone plays on its own
+	aClone.SetSnakeDirection(testdata.DirectionMinus1_0)
+	_, _, err = aClone.MoveSnake()
+	require.NoError(t, err)
+	aClone.RemoveCandy()
+
+	after, err := aGameBoard.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, want, after)
+
+	// The clones of the same seed place the same candies
+	first, second := aGameBoard.(Cloner).Clone(7), aGameBoard.(Cloner).Clone(7)
+	for i := 0; i < 5; i++ {
+		firstPosition, err := first.RandomFreePosition()
+		require.NoError(t, err)
+		secondPosition, err := second.RandomFreePosition()
+		require.NoError(t, err)
+		require.Equal(t, firstPosition, secondPosition)
+	}
+}
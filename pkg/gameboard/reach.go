@@ -0,0 +1,86 @@
+package gameboard
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/grid"
+)
+
+// boardGrid is the read-only view of the cells of a board, it follows the board when it is resized or restored
+type boardGrid struct {
+	aGameBoard *gameBoard
+}
+
+// Grid returns a view of the cells of the board for the algorithms of the grid package
+func (aGameBoard *gameBoard) Grid() grid.Grider {
+	return boardGrid{aGameBoard: aGameBoard}
+}
+
+func (view boardGrid) Size() common.Size {
+	return view.aGameBoard.size
+}
+
+// Cell returns the value of a cell, 0 out of the board
+func (view boardGrid) Cell(position common.Position) rune {
+	if !view.aGameBoard.checkPosition(position) {
+		return 0
+	}
+
+	return view.aGameBoard.board[position.X][position.Y]
+}
+
+// Boundary is the wrap-around of the board, translatePosition steps through it
+func (view boardGrid) Boundary() grid.Boundary {
+	return grid.BoundaryWrap
+}
+
+// passable tells if the snake can move through a cell: a free cell, the candy or its tail which moves away
+func (aGameBoard *gameBoard) passable() grid.Passable {
+	tail, tailErr := common.Position{}, ErrInvalidSnakeReference
+	if aGameBoard.movingSnake != nil {
+		tail, tailErr = aGameBoard.movingSnake.Tail()
+	}
+
+	return func(position common.Position) bool {
+		return aGameBoard.board[position.X][position.Y] != SnakePart || (tailErr == nil && position == tail)
+	}
+}
+
+// FloodFill returns the cells the snake can reach from start, start first then by distance
+// The snake moves through the free cells, the candy and its tail which moves away, the sides of the board wrap
+func (aGameBoard *gameBoard) FloodFill(start common.Position) (region []common.Position) {
+	return grid.FloodFill(aGameBoard.Grid(), start, aGameBoard.passable())
+}
+
+// Reachable tells if the snake can go from from to to, cf FloodFill
+func (aGameBoard *gameBoard) Reachable(from, to common.Position) bool {
+	_, ok := grid.BFS(aGameBoard.Grid(), from, to, aGameBoard.passable())
+
+	return ok
+}
+
+// TrapRegion returns the cells the head of the snake can reach, the head excluded
+// The snake is trapped when they are fewer than its length, it can't get out before running into itself
+func (aGameBoard *gameBoard) TrapRegion() (region []common.Position, trapped bool, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameBoard.movingSnake == nil {
+		return nil, false, ErrInvalidSnakeReference
+	}
+
+	head, err := aGameBoard.movingSnake.Position()
+	if err != nil {
+		return nil, false, err
+	}
+
+	size, err := aGameBoard.movingSnake.Size()
+	if err != nil {
+		return nil, false, err
+	}
+
+	region = aGameBoard.FloodFill(head)
+	if len(region) > 0 {
+		region = region[1:]
+	}
+
+	return region, len(region) < size, nil
+}
@@ -0,0 +1,145 @@
+package gameboard
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/grid"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSnakeBoard returns a board of size holding the snake of body, from the tail to the head
+func newSnakeBoard(t *testing.T, size common.Size, body []common.Position) GameBoarder {
+	checkpoint := common.BoardCheckpoint{Size: size, Board: make([][]rune, size.Width), SnakeBody: body}
+	for x := range checkpoint.Board {
+		checkpoint.Board[x] = make([]rune, size.Height)
+		for y := range checkpoint.Board[x] {
+			checkpoint.Board[x][y] = FreeSpace
+		}
+	}
+
+	for _, position := range body {
+		checkpoint.Board[position.X][position.Y] = SnakePart
+	}
+
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.Restore(checkpoint))
+
+	return aGameBoard
+}
+
+// pocketBody walls in the head and the cells (1,1) and (2,1) of a board of 4x4, the tail is away from them
+var pocketBody = []common.Position{{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 2},
+	{X: 3, Y: 1}, {X: 3, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2},
+	{X: 1, Y: 2}, {X: 2, Y: 2}}
+
+func TestGameBoard_Grid(t *testing.T) {
+	aGameBoard := newSnakeBoard(t, common.Size{Width: 4, Height: 4}, pocketBody)
+	view := aGameBoard.Grid()
+
+	require.Equal(t, common.Size{Width: 4, Height: 4}, view.Size())
+	require.Equal(t, SnakePart, view.Cell(common.Position{X: 2, Y: 2}))
+	require.Equal(t, FreeSpace, view.Cell(common.Position{X: 1, Y: 1}))
+	require.Equal(t, rune(0), view.Cell(common.Position{X: 4, Y: 0}))
+
+	// The moves out of a side enter the other side, as translatePosition does
+	require.Equal(t, grid.BoundaryWrap, view.Boundary())
+	for _, move := range []struct {
+		from      common.Position
+		direction common.Direction
+	}{
+		{from: common.Position{X: 0, Y: 2}, direction: common.Direction{DX: -1}},
+		{from: common.Position{X: 3, Y: 2}, direction: common.Direction{DX: 1}},
+		{from: common.Position{X: 1, Y: 0}, direction: common.Direction{DY: -1}},
+		{from: common.Position{X: 1, Y: 3}, direction: common.Direction{DY: 1}},
+	} {
+		want, err := aGameBoard.(*gameBoard).translatePosition(common.Position{
+			X: move.from.X + move.direction.DX,
+			Y: move.from.Y + move.direction.DY,
+		})
+		require.NoError(t, err)
+
+		got, ok := grid.Step(view, move.from, move.direction)
+		require.True(t, ok)
+		require.Equal(t, want, got)
+	}
+
+	// The view follows the board
+	require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 6, Height: 5}))
+	require.Equal(t, common.Size{Width: 6, Height: 5}, view.Size())
+	require.Equal(t, FreeSpace, view.Cell(common.Position{X: 2, Y: 2}))
+}
+
+func TestGameBoard_FloodFill(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       []common.Position
+		start      common.Position
+		wantRegion []common.Position
+		wantCount  int
+	}{
+		{
+			name:       "TestPocket",
+			body:       pocketBody,
+			start:      common.Position{X: 2, Y: 2},
+			wantRegion: []common.Position{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 1, Y: 1}},
+			wantCount:  3,
+		},
+		{
+			name:      "TestEmptyBoard",
+			body:      []common.Position{{X: 1, Y: 1}},
+			start:     common.Position{X: 1, Y: 1},
+			wantCount: 16,
+		},
+		{
+			name:      "TestThroughTheSides", // The column of the snake is crossed through the sides and the tail
+			body:      []common.Position{{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: 0}},
+			start:     common.Position{X: 0, Y: 0},
+			wantCount: 13,
+		},
+		{
+			name:      "TestOutOfTheBoard",
+			body:      []common.Position{{X: 1, Y: 1}},
+			start:     common.Position{X: 4, Y: 1},
+			wantCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region := newSnakeBoard(t, common.Size{Width: 4, Height: 4}, tt.body).FloodFill(tt.start)
+			require.Len(t, region, tt.wantCount)
+
+			if tt.wantRegion != nil {
+				require.Equal(t, tt.wantRegion, region)
+			}
+		})
+	}
+}
+
+func TestGameBoard_Reachable(t *testing.T) {
+	aGameBoard := newSnakeBoard(t, common.Size{Width: 4, Height: 4}, pocketBody)
+
+	require.True(t, aGameBoard.Reachable(common.Position{X: 2, Y: 2}, common.Position{X: 1, Y: 1}))
+	require.False(t, aGameBoard.Reachable(common.Position{X: 2, Y: 2}, common.Position{X: 0, Y: 3}))
+	require.True(t, aGameBoard.Reachable(common.Position{X: 1, Y: 1}, common.Position{X: 2, Y: 1}))
+}
+
+func TestGameBoard_TrapRegion(t *testing.T) {
+	region, trapped, err := newSnakeBoard(t, common.Size{Width: 4, Height: 4}, pocketBody).TrapRegion()
+	require.NoError(t, err)
+	require.True(t, trapped)
+	require.Equal(t, []common.Position{{X: 2, Y: 1}, {X: 1, Y: 1}}, region)
+
+	// The snake which can follow its tail isn't trapped
+	region, trapped, err = newSnakeBoard(t, common.Size{Width: 4, Height: 4},
+		[]common.Position{{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 0}, {X: 0, Y: 0}}).TrapRegion()
+	require.NoError(t, err)
+	require.False(t, trapped)
+	require.Len(t, region, 12)
+
+	// There is no snake yet
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 4, Height: 4}))
+	_, _, err = aGameBoard.TrapRegion()
+	require.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package gameboard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+)
+
+// Formats of the snapshots, named after the extensions of the files
+const (
+	FormatPNG  = "png"
+	FormatSVG  = "svg"
+	FormatText = "txt"
+)
+
+// SnapshotFormats lists the formats a snapshot can be written in
+var SnapshotFormats = []string{FormatPNG, FormatSVG, FormatText}
+
+// snapshotTitle is the first entry of the metadata of the snapshots
+const snapshotTitle = "GoSnake snapshot"
+
+// textFreeSpace is written for the free cells in the text snapshots, the rows keep their width
+const textFreeSpace = '.'
+
+// pngHeaderSize is the size of the signature and of the IHDR chunk, the text chunks are written after them
+const pngHeaderSize = 8 + 4 + 4 + 13 + 4
+
+// ErrUnknownFormat is returned when a snapshot is requested in a format which doesn't exist
+var ErrUnknownFormat = errors.New("unknown snapshot format")
+
+// Palette holds the colors of the cells in the images of the board
+type Palette struct {
+	Background color.Color
+	Snake      color.Color
+	Candy      color.Color
+}
+
+// Color returns the color of a cell, the values unknown are drawn as free space
+func (palette Palette) Color(value rune) color.Color {
+	switch value {
+	case SnakePart:
+		return palette.Snake
+	case CandyBody:
+		return palette.Candy
+	}
+
+	return palette.Background
+}
+
+// WriteSnapshot writes the snapshot in format, the images draw each cell with cellSize pixels
+// Each format starts with the score, the round, the board size and the seed
+func WriteSnapshot(w io.Writer, format string, snapshot common.BoardSnapshot, palette Palette,
+	cellSize int) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if cellSize <= 0 {
+		return fmt.Errorf("%w: cells of %d pixels", ErrInvalidSize, cellSize)
+	}
+
+	switch format {
+	case FormatPNG:
+		return writePNG(w, snapshot, palette, cellSize)
+	case FormatSVG:
+		return writeSVG(w, snapshot, palette, cellSize)
+	case FormatText:
+		return writeText(w, snapshot)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// snapshotMetadata returns the names and the values written before the cells
+func snapshotMetadata(snapshot common.BoardSnapshot) [][2]string {
+	return [][2]string{
+		{"Title", snapshotTitle},
+		{"Score", strconv.Itoa(snapshot.Score)},
+		{"Round", strconv.Itoa(snapshot.Round)},
+		{"Board size", fmt.Sprintf("%dx%d", snapshot.Size.Width, snapshot.Size.Height)},
+		{"Seed", strconv.FormatInt(snapshot.Seed, 10)},
+	}
+}
+
+// writeText writes the metadata as comments then a line per row of cells
+func writeText(w io.Writer, snapshot common.BoardSnapshot) error {
+	output := bufio.NewWriter(w)
+
+	for _, entry := range snapshotMetadata(snapshot) {
+		fmt.Fprintf(output, "# %s: %s\n", entry[0], entry[1])
+	}
+
+	for y := 0; y < snapshot.Size.Height; y++ {
+		for x := 0; x < snapshot.Size.Width; x++ {
+			value := snapshot.Cells[x][y]
+			if value == FreeSpace {
+				value = textFreeSpace
+			}
+
+			output.WriteRune(value)
+		}
+
+		output.WriteByte('\n')
+	}
+
+	return output.Flush()
+}
+
+// writeSVG draws the board and a square per occupied cell, the metadata is the description
+func writeSVG(w io.Writer, snapshot common.BoardSnapshot, palette Palette, cellSize int) error {
+	output := bufio.NewWriter(w)
+	width, height := snapshot.Size.Width*cellSize, snapshot.Size.Height*cellSize
+
+	fmt.Fprintf(output, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" "+
+		"viewBox=\"0 0 %d %d\" shape-rendering=\"crispEdges\">\n", width, height, width, height)
+
+	metadata := snapshotMetadata(snapshot)
+	fmt.Fprintf(output, "<title>%s</title>\n<desc>", snapshotTitle)
+
+	for i, entry := range metadata[1:] {
+		if i > 0 {
+			output.WriteString(", ")
+		}
+
+		fmt.Fprintf(output, "%s: %s", entry[0], entry[1])
+	}
+
+	output.WriteString("</desc>\n")
+	fmt.Fprintf(output, "<rect width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", width, height, hexColor(palette.Background))
+
+	for y := 0; y < snapshot.Size.Height; y++ {
+		for x := 0; x < snapshot.Size.Width; x++ {
+			if snapshot.Cells[x][y] == FreeSpace {
+				continue
+			}
+
+			fmt.Fprintf(output, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				x*cellSize, y*cellSize, cellSize, cellSize, hexColor(palette.Color(snapshot.Cells[x][y])))
+		}
+	}
+
+	output.WriteString("</svg>\n")
+
+	return output.Flush()
+}
+
+func hexColor(aColor color.Color) string {
+	rgba := color.RGBAModel.Convert(aColor).(color.RGBA)
+
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
+
+// writePNG draws the cells, the metadata is written in text chunks which image/png doesn't write
+func writePNG(w io.Writer, snapshot common.BoardSnapshot, palette Palette, cellSize int) error {
+	colors := color.Palette{palette.Background, palette.Snake, palette.Candy}
+	img := image.NewPaletted(image.Rect(0, 0, snapshot.Size.Width*cellSize, snapshot.Size.Height*cellSize), colors)
+
+	for x := 0; x < snapshot.Size.Width; x++ {
+		for y := 0; y < snapshot.Size.Height; y++ {
+			index := uint8(colors.Index(palette.Color(snapshot.Cells[x][y])))
+
+			for px := 0; px < cellSize; px++ {
+				for py := 0; py < cellSize; py++ {
+					img.SetColorIndex(x*cellSize+px, y*cellSize+py, index)
+				}
+			}
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return err
+	}
+
+	data := encoded.Bytes()
+	if _, err := w.Write(data[:pngHeaderSize]); err != nil {
+		return err
+	}
+
+	for _, entry := range snapshotMetadata(snapshot) {
+		if _, err := w.Write(pngTextChunk(entry[0], entry[1])); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(data[pngHeaderSize:])
+
+	return err
+}
+
+// pngTextChunk returns a tEXt chunk: length, type, keyword, a zero byte, text and the CRC of the type and the data
+func pngTextChunk(keyword, text string) []byte {
+	chunk := make([]byte, 4, 12+len(keyword)+1+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(keyword)+1+len(text)))
+
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, keyword...)
+	chunk = append(chunk, 0)
+	chunk = append(chunk, text...)
+
+	var crc [4]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.ChecksumIEEE(chunk[4:]))
+
+	return append(chunk, crc[:]...)
+}
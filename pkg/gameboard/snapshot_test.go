@@ -0,0 +1,156 @@
+package gameboard
+
+import (
+	"bytes"
+	"flag"
+	"gosnake/pkg/common"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the snapshots of the tests: go test ./pkg/gameboard -update
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// goldenDir holds the snapshots expected by the tests
+var goldenDir = filepath.Join("testdata", "snapshots")
+
+var testPalette = Palette{
+	Background: color.RGBA{0x00, 0x00, 0x00, 0xff},
+	Snake:      color.RGBA{0x00, 0xcd, 0x00, 0xff},
+	Candy:      color.RGBA{0xcd, 0x00, 0x00, 0xff},
+}
+
+// playedSnapshot plays a few rounds on a seeded board, the candies are always at the same places
+func playedSnapshot(t *testing.T) common.BoardSnapshot {
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 12, Height: 8}))
+	aGameBoard.SetSeed(42)
+
+	_, err := aGameBoard.CreateSnake(common.Position{X: 2, Y: 3}, common.Direction{DX: 1, DY: 0})
+	require.NoError(t, err)
+	_, err = aGameBoard.CreateCandy()
+	require.NoError(t, err)
+
+	// The snake eats the candy then turns
+	score := 0
+	for _, direction := range []common.Direction{{DX: 1}, {DX: 1}, {DX: 1}, {DY: 1}, {DY: 1}, {DX: -1}} {
+		aGameBoard.SetSnakeDirection(direction)
+		oldValue, _, err := aGameBoard.MoveSnake()
+		require.NoError(t, err)
+
+		if aGameBoard.IsCandy(oldValue) {
+			score++
+			_, err = aGameBoard.CreateCandy()
+			require.NoError(t, err)
+		}
+	}
+
+	snapshot := aGameBoard.Snapshot()
+	snapshot.Score, snapshot.Round = score, 6
+
+	return snapshot
+}
+
+func TestWriteSnapshot_golden(t *testing.T) {
+	snapshot := playedSnapshot(t)
+
+	for _, format := range []string{FormatText, FormatSVG} {
+		t.Run(format, func(t *testing.T) {
+			var output bytes.Buffer
+			require.NoError(t, WriteSnapshot(&output, format, snapshot, testPalette, 4))
+
+			fileName := filepath.Join(goldenDir, "board."+format)
+			if *update {
+				require.NoError(t, os.WriteFile(fileName, output.Bytes(), 0o644))
+			}
+
+			golden, err := os.ReadFile(fileName)
+			require.NoError(t, err)
+			require.Equal(t, string(golden), output.String())
+		})
+	}
+}
+
+func TestWriteSnapshot_png(t *testing.T) {
+	snapshot := playedSnapshot(t)
+
+	var output bytes.Buffer
+	require.NoError(t, WriteSnapshot(&output, FormatPNG, snapshot, testPalette, 3))
+
+	// The metadata is kept in text chunks
+	for _, text := range []string{"Score\x001", "Round\x006", "Board size\x0012x8", "Seed\x0042"} {
+		require.Contains(t, output.String(), "tEXt"+text)
+	}
+
+	img, err := png.Decode(&output)
+	require.NoError(t, err)
+	require.Equal(t, 36, img.Bounds().Dx())
+	require.Equal(t, 24, img.Bounds().Dy())
+
+	// Each pixel has the color of its cell
+	for x := 0; x < snapshot.Size.Width; x++ {
+		for y := 0; y < snapshot.Size.Height; y++ {
+			wantColor := color.RGBAModel.Convert(testPalette.Color(snapshot.Cells[x][y]))
+			require.Equal(t, wantColor, color.RGBAModel.Convert(img.At(x*3+2, y*3+1)))
+		}
+	}
+}
+
+func TestWriteSnapshot_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		cellSize    int
+		wantErrType error
+	}{
+		{
+			name:        "TestUnknownFormat",
+			format:      "jpg",
+			cellSize:    4,
+			wantErrType: ErrUnknownFormat,
+		},
+		{
+			name:        "TestInvalidCellSize",
+			format:      FormatPNG,
+			cellSize:    0,
+			wantErrType: ErrInvalidSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := WriteSnapshot(&output, tt.format, playedSnapshot(t), testPalette, tt.cellSize)
+			require.ErrorIs(t, err, tt.wantErrType)
+		})
+	}
+}
+
+func TestGameBoard_SetSeed(t *testing.T) {
+	// The same seed places the candies at the same positions
+	positions := func(seed int64) (list []common.Position) {
+		aGameBoard := New()
+		require.NoError(t, aGameBoard.InitGameBoard(common.Size{Width: 20, Height: 20}))
+		aGameBoard.SetSeed(seed)
+
+		for i := 0; i < 5; i++ {
+			position, err := aGameBoard.RandomFreePosition()
+			require.NoError(t, err)
+			list = append(list, position)
+		}
+
+		return list
+	}
+
+	require.Equal(t, positions(7), positions(7))
+	require.NotEqual(t, positions(7), positions(8))
+	require.Equal(t, int64(7), func() int64 {
+		aGameBoard := New()
+		aGameBoard.SetSeed(7)
+		return aGameBoard.Snapshot().Seed
+	}())
+}
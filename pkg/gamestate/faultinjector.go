@@ -0,0 +1,74 @@
+package gamestate
+
+import (
+	"errors"
+	"gosnake/pkg/common"
+	"sync/atomic"
+)
+
+// Faults which can be injected
+const (
+	noFault int32 = iota
+	faultError
+	faultPanic
+)
+
+// ErrInjectedFault is returned, or raised as a panic, by Play() when a fault was injected
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultInjector is a GameStater whose Play() fails on demand, it is used to test the recovery
+type FaultInjector interface {
+	GameStater
+	InjectError()
+	InjectPanic()
+}
+
+// faultInjector inherits the methods of the game state it decorates
+// The fault is injected by the key handler and consumed by the game engine, hence the atomic
+type faultInjector struct {
+	GameStater
+	fault int32
+}
+
+// NewFaultInjector returns an instance of faultInjector decorating gameState
+func NewFaultInjector(gameState GameStater) FaultInjector {
+	return &faultInjector{
+		GameStater: gameState,
+	}
+}
+
+// InjectError makes the next call to Play() return ErrInjectedFault
+func (injector *faultInjector) InjectError() {
+	atomic.StoreInt32(&injector.fault, faultError)
+}
+
+// InjectPanic makes the next call to Play() panic with ErrInjectedFault
+func (injector *faultInjector) InjectPanic() {
+	atomic.StoreInt32(&injector.fault, faultPanic)
+}
+
+// Play fails once if a fault was injected, otherwise it plays a round
+func (injector *faultInjector) Play() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	switch atomic.SwapInt32(&injector.fault, noFault) {
+	case faultError:
+		return nil, ErrInjectedFault
+	case faultPanic:
+		panic(ErrInjectedFault)
+	}
+
+	return injector.GameStater.Play()
+}
+
+// Clone copies the game it decorates, the faults are not copied
+func (injector *faultInjector) Clone(seed int64) (aClone GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	cloner, ok := injector.GameStater.(Cloner)
+	if !ok {
+		return nil, ErrNotClonable
+	}
+
+	return cloner.Clone(seed)
+}
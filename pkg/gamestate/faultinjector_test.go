@@ -0,0 +1,75 @@
+package gamestate
+
+import (
+	"gosnake/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjector_Play(t *testing.T) {
+	tests := []struct {
+		name     string
+		inject   func(injector FaultInjector)
+		wantErr  bool
+		wantStep bool // the round was played
+	}{
+		{
+			name:     "TestNoFault",
+			inject:   func(injector FaultInjector) {},
+			wantStep: true,
+		},
+		{
+			name:    "TestError",
+			inject:  FaultInjector.InjectError,
+			wantErr: true,
+		},
+		{
+			name:    "TestPanic",
+			inject:  FaultInjector.InjectPanic,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injector := NewFaultInjector(New())
+			require.NoError(t, injector.InitBoard(common.Size{Width: 10, Height: 10}))
+			_, err := injector.CreateObjects()
+			require.NoError(t, err)
+			injector.Start()
+
+			tt.inject(injector)
+			_, err = injector.Play()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInjectedFault)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantStep, injector.Round() == 1)
+
+			// The fault is injected once
+			_, err = injector.Play()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFaultInjector_Clone(t *testing.T) {
+	injector := NewFaultInjector(New())
+	require.NoError(t, injector.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := injector.CreateObjects()
+	require.NoError(t, err)
+	injector.Start()
+
+	// The fault stays with the game injected
+	injector.InjectError()
+	aClone, err := injector.(Cloner).Clone(1)
+	require.NoError(t, err)
+	_, err = aClone.Play()
+	require.NoError(t, err)
+	_, err = injector.Play()
+	require.ErrorIs(t, err, ErrInjectedFault)
+
+	_, err = NewFaultInjector(&gameState{}).(Cloner).Clone(1)
+	require.ErrorIs(t, err, ErrNotClonable)
+}
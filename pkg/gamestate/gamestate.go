@@ -4,6 +4,7 @@ import (
 	"errors"
 	"gosnake/pkg/common"
 	"gosnake/pkg/gameboard"
+	"sync"
 )
 
 // GameStater is the gameState interface
@@ -11,9 +12,12 @@ type GameStater interface {
 	InitBoard(size common.Size) (err error)
 	CreateObjects() (listSprite []common.Sprite, err error)
 	Start()
+	Resume()
 	Play() (listSprite []common.Sprite, err error)
 	GameInProgress() bool
 	SetGameInProgress(bool)
+	Paused() bool
+	SetPaused(bool)
 	Dirty() bool
 	HighScore() int
 	Score() int
@@ -25,20 +29,59 @@ type GameStater interface {
 	BoardSize() common.Size
 	SnakePosition() (position common.Position, err error)
 	SnakeSize() (size int, err error)
+	Sprites() []common.Sprite
+	Checkpoint() (checkpoint common.GameCheckpoint, err error)
+	Restore(checkpoint common.GameCheckpoint) (err error)
+	History() common.GameHistory
+	Snapshot() common.BoardSnapshot
+	SetSeed(seed int64)
+	TrapRegion() (region []common.Position, trapped bool, err error)
 }
 
+// Cloner is a GameStater which can be copied cheaply, e.g. to play the rounds ahead in simulations
+// Each copy draws its candies from its own seed, the copies can be played at once in several goroutines
+type Cloner interface {
+	GameStater
+	Clone(seed int64) (aClone GameStater, err error)
+}
+
+// The flags are set by the key handler while the game engine reads them, hence flagsMutex
+// Play holds roundMutex for a whole round, the snapshots and the scores read by the front-ends are taken between the rounds
 type gameState struct {
+	flagsMutex     sync.RWMutex
+	roundMutex     sync.Mutex
 	gameInProgress bool
+	paused         bool
 	round          int
 	score          int
 	highScore      int
 	dirty          bool
+	history        common.GameHistory // The current or the last game
 	gameboard.GameBoarder
 }
 
+// Limits of the games
+const (
+	MinBoardSize  = 4  // The smallest board a game is played on
+	RoundsPerCell = 20 // The rounds per cell of the board of a game without limit, a snake circling ends eventually
+)
+
+// Outcome is how a round played ends
+type Outcome int
+
+// Outcomes of the rounds
+const (
+	OutcomePlaying   Outcome = iota // The game goes on
+	OutcomeCollision                // The snake ran into its body, the game is lost
+	OutcomeBoardFull                // The snake filled the board, the game is won
+)
+
 // ErrInvalidBoardReference is a custom error thrown when the board object is nil
 var ErrInvalidBoardReference = errors.New("The board object is nil")
 
+// ErrNotClonable is returned when the board of a game can't be copied
+var ErrNotClonable = errors.New("the board can't be cloned")
+
 var (
 	goLeft common.Direction = common.Direction{
 		DX: -1,
@@ -65,6 +108,45 @@ func New() GameStater {
 	return &aGameState
 }
 
+// NewSeeded returns a started game on a board of size, the same seed places the candies at the same places
+func NewSeeded(size common.Size, seed int64) (aGameState GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aGameState = New()
+	if err := aGameState.InitBoard(size); err != nil {
+		return nil, err
+	}
+
+	aGameState.SetSeed(seed)
+
+	if _, err := aGameState.CreateObjects(); err != nil {
+		return nil, err
+	}
+
+	aGameState.Start()
+
+	return aGameState, nil
+}
+
+// PlayRound plays a round and tells how it ends, the snake filling the board wins the game and isn't an error
+func PlayRound(aGameState GameStater) (outcome Outcome, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if _, err := aGameState.Play(); err != nil {
+		if errors.Is(err, gameboard.ErrBoardFull) {
+			return OutcomeBoardFull, nil
+		}
+
+		return outcome, err
+	}
+
+	if !aGameState.GameInProgress() {
+		return OutcomeCollision, nil
+	}
+
+	return OutcomePlaying, nil
+}
+
 func (aGameState *gameState) InitBoard(size common.Size) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
@@ -96,10 +178,22 @@ func (aGameState *gameState) CreateObjects() (listSprite []common.Sprite, err er
 }
 
 func (aGameState *gameState) Start() {
-	aGameState.gameInProgress = true
+	aGameState.setFlags(true, false)
 	aGameState.score = 0
 	aGameState.round = 0
 	aGameState.dirty = true
+	aGameState.history = common.GameHistory{}
+
+	if aGameState.GameBoarder != nil {
+		aGameState.history.BoardSize = aGameState.BoardSize()
+		aGameState.history.Start = aGameState.Sprites()
+	}
+}
+
+// Resume continues a game without resetting the score and the rounds
+func (aGameState *gameState) Resume() {
+	aGameState.setFlags(true, false)
+	aGameState.dirty = true
 }
 
 func (aGameState *gameState) Play() (listSprite []common.Sprite, err error) {
@@ -109,18 +203,28 @@ func (aGameState *gameState) Play() (listSprite []common.Sprite, err error) {
 		return listSprite, ErrInvalidBoardReference
 	}
 
+	aGameState.roundMutex.Lock()
+	defer aGameState.roundMutex.Unlock()
+
+	// The rounds played without error are added to the history
+	defer func() {
+		if err == nil {
+			aGameState.history.Rounds = append(aGameState.history.Rounds, listSprite)
+		}
+	}()
+
 	//Plays a round
 	aGameState.round++
 
 	//Move the snake
 	oldValue, spriteList, err := aGameState.MoveSnake()
 	if err != nil {
-		aGameState.gameInProgress = false
+		aGameState.SetGameInProgress(false)
 		return spriteList, err
 	}
 	//Game over?
 	if aGameState.IsSnakePart(oldValue) {
-		aGameState.gameInProgress = false
+		aGameState.SetGameInProgress(false)
 		return spriteList, nil
 	}
 
@@ -149,26 +253,64 @@ func (aGameState *gameState) Play() (listSprite []common.Sprite, err error) {
 }
 
 func (aGameState *gameState) GameInProgress() bool {
+	aGameState.flagsMutex.RLock()
+	defer aGameState.flagsMutex.RUnlock()
+
 	return aGameState.gameInProgress
 }
 
 func (aGameState *gameState) SetGameInProgress(val bool) {
+	aGameState.flagsMutex.Lock()
+	defer aGameState.flagsMutex.Unlock()
+
 	aGameState.gameInProgress = val
 }
 
+// Paused tells if the rounds are suspended, the game is still in progress
+func (aGameState *gameState) Paused() bool {
+	aGameState.flagsMutex.RLock()
+	defer aGameState.flagsMutex.RUnlock()
+
+	return aGameState.paused
+}
+
+func (aGameState *gameState) SetPaused(val bool) {
+	aGameState.flagsMutex.Lock()
+	defer aGameState.flagsMutex.Unlock()
+
+	aGameState.paused = val
+}
+
+// setFlags starts or stops the game and its pause at once
+func (aGameState *gameState) setFlags(gameInProgress, paused bool) {
+	aGameState.flagsMutex.Lock()
+	defer aGameState.flagsMutex.Unlock()
+
+	aGameState.gameInProgress, aGameState.paused = gameInProgress, paused
+}
+
 func (aGameState *gameState) Dirty() bool {
 	return aGameState.dirty
 }
 
 func (aGameState *gameState) HighScore() int {
+	aGameState.roundMutex.Lock()
+	defer aGameState.roundMutex.Unlock()
+
 	return aGameState.highScore
 }
 
 func (aGameState *gameState) Score() int {
+	aGameState.roundMutex.Lock()
+	defer aGameState.roundMutex.Unlock()
+
 	return aGameState.score
 }
 
 func (aGameState *gameState) Round() int {
+	aGameState.roundMutex.Lock()
+	defer aGameState.roundMutex.Unlock()
+
 	return aGameState.round
 }
 
@@ -184,3 +326,92 @@ func (aGameState *gameState) MoveDown() {
 func (aGameState *gameState) MoveUp() {
 	aGameState.SetSnakeDirection(goUp)
 }
+
+// Checkpoint copies the game state and its board
+func (aGameState *gameState) Checkpoint() (checkpoint common.GameCheckpoint, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameState.GameBoarder == nil {
+		return checkpoint, ErrInvalidBoardReference
+	}
+
+	if checkpoint.Board, err = aGameState.GameBoarder.Checkpoint(); err != nil {
+		return checkpoint, err
+	}
+
+	checkpoint.Round = aGameState.round
+	checkpoint.Score = aGameState.score
+	checkpoint.HighScore = aGameState.highScore
+
+	return checkpoint, nil
+}
+
+// Restore sets the game state and its board back to a checkpoint, the game is not in progress
+func (aGameState *gameState) Restore(checkpoint common.GameCheckpoint) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	board := gameboard.New()
+	if err := board.Restore(checkpoint.Board); err != nil {
+		return err
+	}
+
+	aGameState.GameBoarder = board
+	aGameState.round = checkpoint.Round
+	aGameState.score = checkpoint.Score
+
+	// A higher score might have been reached since the checkpoint
+	if checkpoint.HighScore > aGameState.highScore {
+		aGameState.highScore = checkpoint.HighScore
+	}
+
+	// The rounds played after the checkpoint are forgotten
+	if checkpoint.Round < len(aGameState.history.Rounds) {
+		aGameState.history.Rounds = aGameState.history.Rounds[:checkpoint.Round]
+	}
+
+	aGameState.SetGameInProgress(false)
+
+	return nil
+}
+
+// History returns the sprites of the game started last, round after round
+func (aGameState *gameState) History() common.GameHistory {
+	return aGameState.history
+}
+
+// Snapshot copies the board with the score and the round, it waits for the round being played to end
+func (aGameState *gameState) Snapshot() (snapshot common.BoardSnapshot) {
+	if aGameState.GameBoarder == nil {
+		return snapshot
+	}
+
+	aGameState.roundMutex.Lock()
+	defer aGameState.roundMutex.Unlock()
+
+	snapshot = aGameState.GameBoarder.Snapshot()
+	snapshot.Score, snapshot.Round = aGameState.score, aGameState.round
+
+	return snapshot
+}
+
+// Clone returns a copy of the game which plays on its own, its candies are drawn from seed
+// The history isn't copied, the copy records its own rounds
+func (aGameState *gameState) Clone(seed int64) (aClone GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	board, ok := aGameState.GameBoarder.(gameboard.Cloner)
+	if !ok {
+		return nil, ErrNotClonable
+	}
+
+	return &gameState{
+		gameInProgress: aGameState.GameInProgress(),
+		paused:         aGameState.Paused(),
+		round:          aGameState.round,
+		score:          aGameState.score,
+		highScore:      aGameState.highScore,
+		dirty:          aGameState.dirty,
+		history:        common.GameHistory{BoardSize: aGameState.history.BoardSize},
+		GameBoarder:    board.Clone(seed),
+	}, nil
+}
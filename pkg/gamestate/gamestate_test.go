@@ -704,3 +704,262 @@ func TestNew(t *testing.T) {
 	var got = New()
 	require.IsType(t, wantType, got)
 }
+
+func TestNewSeeded(t *testing.T) {
+	aGameState, err := NewSeeded(common.Size{Width: 10, Height: 10}, 3)
+	require.NoError(t, err)
+	require.True(t, aGameState.GameInProgress())
+	require.Equal(t, common.Size{Width: 10, Height: 10}, aGameState.BoardSize())
+
+	// The same seed places the candy at the same place
+	again, err := NewSeeded(common.Size{Width: 10, Height: 10}, 3)
+	require.NoError(t, err)
+	require.Equal(t, aGameState.Sprites(), again.Sprites())
+
+	_, err = NewSeeded(common.Size{}, 3)
+	require.Error(t, err)
+}
+
+func TestPlayRound(t *testing.T) {
+	tests := []struct {
+		name        string
+		playErr     error
+		inProgress  bool
+		wantOutcome Outcome
+		wantErr     error
+	}{
+		{name: "TestPlaying", inProgress: true, wantOutcome: OutcomePlaying},
+		{name: "TestCollision", wantOutcome: OutcomeCollision},
+		{name: "TestBoardFull", playErr: gameboard.ErrBoardFull, wantOutcome: OutcomeBoardFull},
+		{name: "TestError", playErr: ErrInvalidBoardReference, wantErr: ErrInvalidBoardReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := &mocks.GameStater{}
+			aGameState.On("Play").Return(nil, tt.playErr)
+			aGameState.On("GameInProgress").Return(tt.inProgress)
+
+			got, err := PlayRound(aGameState)
+			require.Equal(t, tt.wantOutcome, got)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGameState_Checkpoint(t *testing.T) {
+	aGameState := New()
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+	for i := 0; i < 3; i++ {
+		_, err = aGameState.Play()
+		require.NoError(t, err)
+	}
+
+	checkpoint, err := aGameState.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, 3, checkpoint.Round)
+	sprites := aGameState.Sprites()
+
+	// The game goes on and crashes
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	aGameState.SetGameInProgress(false)
+
+	require.NoError(t, aGameState.Restore(checkpoint))
+	require.False(t, aGameState.GameInProgress())
+	require.Equal(t, 3, aGameState.Round())
+	require.ElementsMatch(t, sprites, aGameState.Sprites())
+
+	aGameState.Resume()
+	require.True(t, aGameState.GameInProgress())
+	require.True(t, aGameState.Dirty())
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	require.Equal(t, 4, aGameState.Round())
+
+	// An invalid checkpoint is rejected
+	checkpoint.Board.Size = common.Size{Width: -1, Height: -1}
+	require.ErrorIs(t, aGameState.Restore(checkpoint), gameboard.ErrInvalidSize)
+	require.Equal(t, 4, aGameState.Round())
+}
+
+func TestGameState_Paused(t *testing.T) {
+	aGameState := &gameState{}
+	aGameState.SetPaused(true)
+	require.True(t, aGameState.Paused())
+
+	// A new or resumed game is not paused
+	aGameState.Start()
+	require.False(t, aGameState.Paused())
+	aGameState.SetPaused(true)
+	aGameState.Resume()
+	require.False(t, aGameState.Paused())
+}
+
+func TestGameState_History(t *testing.T) {
+	aGameState := New()
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	start, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+
+	var rounds [][]common.Sprite
+	for i := 0; i < 3; i++ {
+		spriteList, err := aGameState.Play()
+		require.NoError(t, err)
+		rounds = append(rounds, spriteList)
+	}
+
+	// The history holds the players when the game started and the sprites of each round
+	history := aGameState.History()
+	require.Equal(t, common.Size{Width: 10, Height: 10}, history.BoardSize)
+	require.ElementsMatch(t, start, history.Start)
+	require.Equal(t, rounds, history.Rounds)
+
+	// The rounds after a checkpoint restored are forgotten
+	checkpoint, err := aGameState.Checkpoint()
+	require.NoError(t, err)
+	checkpoint.Round = 2
+	require.NoError(t, aGameState.Restore(checkpoint))
+	require.Equal(t, rounds[:2], aGameState.History().Rounds)
+
+	// A new game starts a new history
+	aGameState.Start()
+	require.Empty(t, aGameState.History().Rounds)
+}
+
+func TestGameState_Snapshot(t *testing.T) {
+	aGameState := New()
+	require.Empty(t, aGameState.Snapshot().Cells)
+
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+
+	for i := 0; i < 2; i++ {
+		_, err := aGameState.Play()
+		require.NoError(t, err)
+	}
+
+	// The board is copied with the score and the round
+	snapshot := aGameState.Snapshot()
+	require.Equal(t, 2, snapshot.Round)
+	require.Equal(t, aGameState.Score(), snapshot.Score)
+	require.Equal(t, common.Size{Width: 10, Height: 10}, snapshot.Size)
+	position, err := aGameState.SnakePosition()
+	require.NoError(t, err)
+	require.Equal(t, gameboard.SnakePart, snapshot.Cells[position.X][position.Y])
+}
+
+func TestGameState_SnapshotWhilePlaying(t *testing.T) {
+	// The snake goes straight along a row longer than it can grow, it never collides
+	aGameState := New()
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 40, Height: 3}))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+
+	countSnake := func(snapshot common.BoardSnapshot) (count int) {
+		for x := range snapshot.Cells {
+			for y := range snapshot.Cells[x] {
+				if snapshot.Cells[x][y] == gameboard.SnakePart {
+					count++
+				}
+			}
+		}
+
+		return count
+	}
+	length := countSnake(aGameState.Snapshot())
+
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 20; i++ {
+			if _, err := aGameState.Play(); err != nil {
+				done <- err
+				return
+			}
+		}
+		close(done)
+	}()
+
+	// The snapshots are taken between the rounds, the tail is never left behind the head
+	for playing := true; playing; {
+		select {
+		case err, ok := <-done:
+			require.NoError(t, err)
+			playing = ok
+		default:
+		}
+
+		snapshot := aGameState.Snapshot()
+		require.Equal(t, length+snapshot.Score, countSnake(snapshot))
+	}
+}
+
+func TestGameState_Clone(t *testing.T) {
+	aGameState := New()
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	aGameState.SetSeed(3)
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+
+	want, err := aGameState.Checkpoint()
+	require.NoError(t, err)
+
+	aClone, err := aGameState.(Cloner).Clone(1)
+	require.NoError(t, err)
+	got, err := aClone.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.True(t, aClone.GameInProgress())
+	require.Empty(t, aClone.History().Rounds)
+
+	// The clones of the same seed play the same game at once, the game cloned doesn't change
+	play := func(seed int64, checkpoints chan common.GameCheckpoint) {
+		aClone, err := aGameState.(Cloner).Clone(seed)
+		if err != nil {
+			close(checkpoints)
+			return
+		}
+
+		// The snake sweeps the board row after row
+		for round := 0; round < 100 && aClone.GameInProgress(); round++ {
+			if round%10 == 9 {
+				aClone.MoveDown()
+			} else {
+				aClone.MoveRight()
+			}
+
+			if _, err := aClone.Play(); err != nil {
+				break
+			}
+		}
+
+		checkpoint, _ := aClone.Checkpoint()
+		checkpoints <- checkpoint
+	}
+
+	first, second := make(chan common.GameCheckpoint, 1), make(chan common.GameCheckpoint, 1)
+	go play(5, first)
+	go play(5, second)
+	firstCheckpoint, secondCheckpoint := <-first, <-second
+	require.Equal(t, firstCheckpoint, secondCheckpoint)
+	require.Greater(t, firstCheckpoint.Score, 0)
+
+	after, err := aGameState.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, want, after)
+	require.Len(t, aGameState.History().Rounds, 1)
+
+	// The boards which can't be copied
+	aMock := &gameState{GameBoarder: &mocks.GameBoarder{}}
+	_, err = aMock.Clone(1)
+	require.ErrorIs(t, err, ErrNotClonable)
+}
@@ -0,0 +1,17 @@
+package gamestatetest
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// New returns a started game on a board of size, its candies drawn with seed
+func New(t *testing.T, size common.Size, seed int64) gamestate.GameStater {
+	gameState, err := gamestate.NewSeeded(size, seed)
+	require.NoError(t, err)
+
+	return gameState
+}
@@ -0,0 +1,153 @@
+package gifexport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Limits of the size of the cells, in pixels
+const (
+	MinCellSize = 1
+	MaxCellSize = 32
+)
+
+// gameOverHold is how long the last frame is displayed before the animation loops
+const gameOverHold = 2 * time.Second
+
+// Indexes of the colors in the palette of the frames
+const (
+	backgroundIndex uint8 = iota
+	snakeIndex
+	candyIndex
+)
+
+// Defines custom errors
+var (
+	ErrEmptyHistory    = errors.New("the game has no round to export")
+	ErrInvalidCellSize = errors.New("invalid cell size")
+)
+
+// Encode writes the game as an animated GIF, a frame per round lasting delay
+// The first frame is the whole board, the next ones only hold the cells changed by the round
+func Encode(w io.Writer, history common.GameHistory, palette gameboard.Palette, cellSize int, delay time.Duration) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if len(history.Rounds) == 0 {
+		return ErrEmptyHistory
+	}
+
+	if cellSize < MinCellSize || cellSize > MaxCellSize {
+		return fmt.Errorf("%w: %d pixels, expected %d to %d", ErrInvalidCellSize, cellSize, MinCellSize, MaxCellSize)
+	}
+
+	var (
+		colors = color.Palette{palette.Background, palette.Snake, palette.Candy}
+		size   = history.BoardSize
+		board  = make([][]uint8, size.Width)
+		anim   = &gif.GIF{
+			Config: image.Config{ColorModel: colors, Width: size.Width * cellSize, Height: size.Height * cellSize},
+		}
+	)
+
+	for x := range board {
+		board[x] = make([]uint8, size.Height)
+	}
+
+	addFrame := func(area image.Rectangle, hold time.Duration) {
+		frame := image.NewPaletted(image.Rect(area.Min.X*cellSize, area.Min.Y*cellSize,
+			area.Max.X*cellSize, area.Max.Y*cellSize), colors)
+
+		for x := area.Min.X; x < area.Max.X; x++ {
+			for y := area.Min.Y; y < area.Max.Y; y++ {
+				for px := 0; px < cellSize; px++ {
+					for py := 0; py < cellSize; py++ {
+						frame.SetColorIndex(x*cellSize+px, y*cellSize+py, board[x][y])
+					}
+				}
+			}
+		}
+
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, centiseconds(hold))
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+
+	applySprites(board, history.Start)
+	addFrame(image.Rect(0, 0, size.Width, size.Height), delay)
+
+	for i, spriteList := range history.Rounds {
+		area := applySprites(board, spriteList)
+		if area.Empty() {
+			// Nothing changed, the previous frame lasts longer
+			anim.Delay[len(anim.Delay)-1] += centiseconds(delay)
+			continue
+		}
+
+		hold := delay
+		if i == len(history.Rounds)-1 {
+			hold = gameOverHold
+		}
+
+		addFrame(area, hold)
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+// Save writes the GIF to a file of directory named after the current time
+func Save(directory string, history common.GameHistory, palette gameboard.Palette, cellSize int,
+	delay time.Duration) (fileName string, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// The GIF is encoded before the file is created, a failed export leaves no file behind
+	var output bytes.Buffer
+	if err := Encode(&output, history, palette, cellSize, delay); err != nil {
+		return "", err
+	}
+
+	fileName = filepath.Join(directory, "gosnake-"+time.Now().Format("20060102-150405")+".gif")
+
+	return fileName, os.WriteFile(fileName, output.Bytes(), 0o644)
+}
+
+// applySprites writes the sprites to the board and returns the area of the cells changed
+func applySprites(board [][]uint8, spriteList []common.Sprite) (area image.Rectangle) {
+	for _, sprite := range spriteList {
+		x, y := sprite.Position.X, sprite.Position.Y
+		if x < 0 || x >= len(board) || y < 0 || y >= len(board[x]) {
+			continue
+		}
+
+		board[x][y] = colorIndex(sprite.Value)
+		area = area.Union(image.Rect(x, y, x+1, y+1))
+	}
+
+	return area
+}
+
+func colorIndex(value rune) uint8 {
+	switch value {
+	case gameboard.SnakePart:
+		return snakeIndex
+	case gameboard.CandyBody:
+		return candyIndex
+	}
+
+	return backgroundIndex
+}
+
+// centiseconds converts a delay to the unit of the GIF format, a frame lasts at least 1/100s
+func centiseconds(delay time.Duration) int {
+	return int(math.Max(1, math.Round(delay.Seconds()*100)))
+}
@@ -0,0 +1,118 @@
+package gifexport
+
+import (
+	"bytes"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sprite(value rune, x, y int) common.Sprite {
+	return common.Sprite{Value: value, Position: common.Position{X: x, Y: y}}
+}
+
+// testPalette holds the colors of the classic theme
+var testPalette = gameboard.Palette{
+	Background: color.RGBA{0x00, 0x00, 0x00, 0xff},
+	Snake:      color.RGBA{0x00, 0xcd, 0x00, 0xff},
+	Candy:      color.RGBA{0xcd, 0x00, 0x00, 0xff},
+}
+
+// testHistory is a snake moving right twice on a 4x3 board
+var testHistory = common.GameHistory{
+	BoardSize: common.Size{Width: 4, Height: 3},
+	Start:     []common.Sprite{sprite(gameboard.SnakePart, 1, 1), sprite(gameboard.CandyBody, 3, 0)},
+	Rounds: [][]common.Sprite{
+		{sprite(gameboard.SnakePart, 2, 1), sprite(gameboard.FreeSpace, 1, 1)},
+		{sprite(gameboard.SnakePart, 3, 1), sprite(gameboard.FreeSpace, 2, 1)},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	var output bytes.Buffer
+
+	require.NoError(t, Encode(&output, testHistory, testPalette, 5, 100*time.Millisecond))
+
+	anim, err := gif.DecodeAll(&output)
+	require.NoError(t, err)
+	require.Equal(t, 20, anim.Config.Width)
+	require.Equal(t, 15, anim.Config.Height)
+
+	// The whole board then the cells changed by each round, the last frame is held
+	require.Len(t, anim.Image, 3)
+	require.Equal(t, image.Rect(0, 0, 20, 15), anim.Image[0].Bounds())
+	require.Equal(t, image.Rect(5, 5, 15, 10), anim.Image[1].Bounds())
+	require.Equal(t, []int{10, 10, 200}, anim.Delay)
+
+	// Each element has its color
+	require.Equal(t, testPalette.Snake, anim.Image[0].At(7, 7))
+	require.Equal(t, testPalette.Candy, anim.Image[0].At(17, 2))
+	require.Equal(t, testPalette.Background, anim.Image[0].At(2, 2))
+	require.Equal(t, testPalette.Background, anim.Image[1].At(7, 7))
+	require.Equal(t, testPalette.Snake, anim.Image[2].At(17, 7))
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		history     common.GameHistory
+		cellSize    int
+		wantErrType error
+	}{
+		{
+			name:        "TestEmptyHistory",
+			history:     common.GameHistory{BoardSize: testHistory.BoardSize, Start: testHistory.Start},
+			cellSize:    8,
+			wantErrType: ErrEmptyHistory,
+		},
+		{
+			name:        "TestCellTooSmall",
+			history:     testHistory,
+			cellSize:    0,
+			wantErrType: ErrInvalidCellSize,
+		},
+		{
+			name:        "TestCellTooLarge",
+			history:     testHistory,
+			cellSize:    MaxCellSize + 1,
+			wantErrType: ErrInvalidCellSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			require.ErrorIs(t, Encode(&output, tt.history, testPalette, tt.cellSize, time.Second), tt.wantErrType)
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	directory := t.TempDir()
+
+	fileName, err := Save(directory, testHistory, testPalette, 2, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, directory, filepath.Dir(fileName))
+
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+	defer file.Close()
+
+	anim, err := gif.DecodeAll(file)
+	require.NoError(t, err)
+	require.Len(t, anim.Image, 3)
+
+	// Nothing is left behind when the export fails
+	_, err = Save(directory, common.GameHistory{}, testPalette, 2, time.Second)
+	require.ErrorIs(t, err, ErrEmptyHistory)
+	files, err := os.ReadDir(directory)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -0,0 +1,355 @@
+package grid
+
+import (
+	"container/heap"
+	"gosnake/pkg/common"
+)
+
+// Boundary tells where a move out of a side of the grid goes
+type Boundary int
+
+// Boundaries of the grids
+const (
+	BoundaryWrap Boundary = iota // The move out of a side enters the other side, as on the game board
+	BoundaryWall                 // The moves out of the grid are blocked
+)
+
+// Unreachable is the distance of the cells which can't be reached
+const Unreachable = -1
+
+// Moves from a cell to its neighbours, Directions in the order they are tried
+var (
+	Up    = common.Direction{DX: 0, DY: -1}
+	Down  = common.Direction{DX: 0, DY: 1}
+	Left  = common.Direction{DX: -1, DY: 0}
+	Right = common.Direction{DX: 1, DY: 0}
+
+	Directions = []common.Direction{Up, Down, Left, Right}
+)
+
+// Grider is the interface for the read-only views of a grid of cells, Cells[x][y] as the board
+type Grider interface {
+	Size() common.Size
+	Cell(position common.Position) rune
+	Boundary() Boundary
+}
+
+// Passable tells if a path can go through a cell
+type Passable func(position common.Position) bool
+
+// grid is a view of cells, they are read as they are and never changed
+type grid struct {
+	cells    [][]rune
+	size     common.Size
+	boundary Boundary
+}
+
+// New returns a grid reading cells, cells[x][y], the cells aren't copied
+func New(cells [][]rune, boundary Boundary) Grider {
+	size := common.Size{Width: len(cells)}
+	if len(cells) > 0 {
+		size.Height = len(cells[0])
+	}
+
+	return &grid{cells: cells, size: size, boundary: boundary}
+}
+
+func (aGrid *grid) Size() common.Size {
+	return aGrid.size
+}
+
+// Cell returns the value of a cell, 0 out of the grid
+func (aGrid *grid) Cell(position common.Position) rune {
+	if !Inside(aGrid, position) {
+		return 0
+	}
+
+	return aGrid.cells[position.X][position.Y]
+}
+
+func (aGrid *grid) Boundary() Boundary {
+	return aGrid.boundary
+}
+
+// Inside tells if position is a cell of the grid
+func Inside(aGrid Grider, position common.Position) bool {
+	size := aGrid.Size()
+
+	return position.X >= 0 && position.X < size.Width && position.Y >= 0 && position.Y < size.Height
+}
+
+// Step returns the cell reached from position in direction, ok is false when a wall blocks the move
+func Step(aGrid Grider, position common.Position, direction common.Direction) (next common.Position, ok bool) {
+	next = common.Position{X: position.X + direction.DX, Y: position.Y + direction.DY}
+	if Inside(aGrid, next) {
+		return next, true
+	}
+
+	if aGrid.Boundary() != BoundaryWrap {
+		return position, false
+	}
+
+	size := aGrid.Size()
+
+	return common.Position{
+		X: (next.X%size.Width + size.Width) % size.Width,
+		Y: (next.Y%size.Height + size.Height) % size.Height,
+	}, true
+}
+
+// Neighbours returns the cells reached in one move from position
+func Neighbours(aGrid Grider, position common.Position) (neighbours []common.Position) {
+	for _, direction := range Directions {
+		if next, ok := Step(aGrid, position, direction); ok && next != position {
+			neighbours = append(neighbours, next)
+		}
+	}
+
+	return neighbours
+}
+
+// Delta returns the shortest move from one cell to another of an empty grid, through the sides when they wrap
+// Halfway round a side that wraps, the move goes forward
+func Delta(aGrid Grider, from, to common.Position) common.Direction {
+	delta := common.Direction{DX: to.X - from.X, DY: to.Y - from.Y}
+
+	if aGrid.Boundary() == BoundaryWrap {
+		size := aGrid.Size()
+		delta.DX, delta.DY = wrapDelta(delta.DX, size.Width), wrapDelta(delta.DY, size.Height)
+	}
+
+	return delta
+}
+
+// wrapDelta returns the shortest of the moves forward and backward along a side of size cells
+func wrapDelta(delta, size int) int {
+	if size <= 0 {
+		return delta
+	}
+
+	delta = (delta%size + size) % size
+	if delta > size/2 {
+		delta -= size
+	}
+
+	return delta
+}
+
+// Distance returns the number of moves between two cells of an empty grid, through the sides when they wrap
+func Distance(aGrid Grider, from, to common.Position) int {
+	delta := Delta(aGrid, from, to)
+
+	return abs(delta.DX) + abs(delta.DY)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
+
+// index returns the place of a cell in the slices of the algorithms
+func index(aGrid Grider, position common.Position) int {
+	return position.X*aGrid.Size().Height + position.Y
+}
+
+// search walks the passable cells from start by distance, visit is called for each cell reached, start first
+// The walk stops when visit returns false, parents holds the cell each one was reached from
+func search(aGrid Grider, start common.Position, passable Passable,
+	visit func(position common.Position, distance int) bool) (parents []int) {
+	size := aGrid.Size()
+	if !Inside(aGrid, start) {
+		return nil
+	}
+
+	parents = make([]int, size.Width*size.Height)
+	for i := range parents {
+		parents[i] = Unreachable
+	}
+
+	parents[index(aGrid, start)] = index(aGrid, start)
+	queue, distances := []common.Position{start}, []int{0}
+
+	for i := 0; i < len(queue); i++ {
+		if !visit(queue[i], distances[i]) {
+			break
+		}
+
+		for _, next := range Neighbours(aGrid, queue[i]) {
+			if parents[index(aGrid, next)] != Unreachable || !passable(next) {
+				continue
+			}
+
+			parents[index(aGrid, next)] = index(aGrid, queue[i])
+			queue, distances = append(queue, next), append(distances, distances[i]+1)
+		}
+	}
+
+	return parents
+}
+
+// FloodFill returns the cells reached from start through the passable cells, start first then by distance
+// start is the origin of the fill whether it is passable or not
+func FloodFill(aGrid Grider, start common.Position, passable Passable) (region []common.Position) {
+	search(aGrid, start, passable, func(position common.Position, _ int) bool {
+		region = append(region, position)
+		return true
+	})
+
+	return region
+}
+
+// DistanceMap returns the number of moves from start to each cell, distances[x][y], Unreachable where it can't go
+func DistanceMap(aGrid Grider, start common.Position, passable Passable) (distances [][]int) {
+	size := aGrid.Size()
+
+	distances = make([][]int, size.Width)
+	for x := range distances {
+		distances[x] = make([]int, size.Height)
+		for y := range distances[x] {
+			distances[x][y] = Unreachable
+		}
+	}
+
+	search(aGrid, start, passable, func(position common.Position, distance int) bool {
+		distances[position.X][position.Y] = distance
+		return true
+	})
+
+	return distances
+}
+
+// BFS returns a shortest path from from to to, both included, ok is false when to can't be reached
+func BFS(aGrid Grider, from, to common.Position, passable Passable) (path []common.Position, ok bool) {
+	parents := search(aGrid, from, passable, func(position common.Position, _ int) bool {
+		ok = position == to
+		return !ok
+	})
+
+	if !ok {
+		return nil, false
+	}
+
+	return walkBack(aGrid, parents, to), true
+}
+
+// walkBack returns the path ending at to, from the start of the search which gave parents
+func walkBack(aGrid Grider, parents []int, to common.Position) (path []common.Position) {
+	height := aGrid.Size().Height
+
+	for current := index(aGrid, to); ; current = parents[current] {
+		path = append(path, common.Position{X: current / height, Y: current % height})
+		if parents[current] == current {
+			break
+		}
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// AStar returns a shortest path from from to to, both included, guided by the distance on an empty grid
+// ok is false when to can't be reached, the paths found are as short as the ones of BFS
+func AStar(aGrid Grider, from, to common.Position, passable Passable) (path []common.Position, ok bool) {
+	if !Inside(aGrid, from) || !Inside(aGrid, to) {
+		return nil, false
+	}
+
+	size := aGrid.Size()
+	parents, costs := make([]int, size.Width*size.Height), make([]int, size.Width*size.Height)
+
+	for i := range parents {
+		parents[i], costs[i] = Unreachable, Unreachable
+	}
+
+	parents[index(aGrid, from)], costs[index(aGrid, from)] = index(aGrid, from), 0
+	open, pushed := &openSet{{position: from, estimate: Distance(aGrid, from, to)}}, 1
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(openCell)
+		if current.position == to {
+			return walkBack(aGrid, parents, to), true
+		}
+
+		// A cell can be pushed again with a lower cost, the old entry is skipped
+		if current.cost > costs[index(aGrid, current.position)] {
+			continue
+		}
+
+		for _, next := range Neighbours(aGrid, current.position) {
+			cost := current.cost + 1
+			if !passable(next) || (costs[index(aGrid, next)] != Unreachable && costs[index(aGrid, next)] <= cost) {
+				continue
+			}
+
+			parents[index(aGrid, next)], costs[index(aGrid, next)] = index(aGrid, current.position), cost
+			heap.Push(open, openCell{position: next, cost: cost, estimate: cost + Distance(aGrid, next, to),
+				order: pushed})
+			pushed++
+		}
+	}
+
+	return nil, false
+}
+
+// openCell is a cell to explore, the cells of lowest estimate first, then the ones pushed first
+type openCell struct {
+	position common.Position
+	cost     int // Moves from the start
+	estimate int // cost plus the distance left on an empty grid
+	order    int // The ties are explored in the order the cells were pushed
+}
+
+// openSet is the heap of the cells to explore
+type openSet []openCell
+
+func (open openSet) Len() int { return len(open) }
+
+func (open openSet) Less(i, j int) bool {
+	if open[i].estimate != open[j].estimate {
+		return open[i].estimate < open[j].estimate
+	}
+
+	return open[i].order < open[j].order
+}
+
+func (open openSet) Swap(i, j int) { open[i], open[j] = open[j], open[i] }
+
+func (open *openSet) Push(cell interface{}) { *open = append(*open, cell.(openCell)) }
+
+func (open *openSet) Pop() interface{} {
+	old := *open
+	cell := old[len(old)-1]
+	*open = old[:len(old)-1]
+
+	return cell
+}
+
+// Components returns the regions of passable cells connected to each other, in the order of their first cell, x then y
+func Components(aGrid Grider, passable Passable) (components [][]common.Position) {
+	size := aGrid.Size()
+	labelled := make([]bool, size.Width*size.Height)
+
+	for x := 0; x < size.Width; x++ {
+		for y := 0; y < size.Height; y++ {
+			position := common.Position{X: x, Y: y}
+			if labelled[index(aGrid, position)] || !passable(position) {
+				continue
+			}
+
+			component := FloodFill(aGrid, position, passable)
+			for _, cell := range component {
+				labelled[index(aGrid, cell)] = true
+			}
+
+			components = append(components, component)
+		}
+	}
+
+	return components
+}
@@ -0,0 +1,217 @@
+package grid
+
+import (
+	"gosnake/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// parse returns the cells of rows, a string per row, cells[x][y] as the board
+func parse(rows ...string) [][]rune {
+	cells := make([][]rune, len(rows[0]))
+	for x := range cells {
+		cells[x] = make([]rune, len(rows))
+		for y := range rows {
+			cells[x][y] = rune(rows[y][x])
+		}
+	}
+
+	return cells
+}
+
+// open lets the paths through the cells which aren't walls
+func open(aGrid Grider) Passable {
+	return func(position common.Position) bool {
+		return aGrid.Cell(position) != '#'
+	}
+}
+
+// rows of a grid whose right part is only reached through the sides when they wrap
+var rows = []string{
+	".#...",
+	".#.#.",
+	".#.#.",
+}
+
+func TestNew(t *testing.T) {
+	aGrid := New(parse(rows...), BoundaryWall)
+	require.Equal(t, common.Size{Width: 5, Height: 3}, aGrid.Size())
+	require.Equal(t, '#', aGrid.Cell(common.Position{X: 1, Y: 2}))
+	require.Equal(t, rune(0), aGrid.Cell(common.Position{X: 5, Y: 0}))
+	require.Equal(t, BoundaryWall, aGrid.Boundary())
+	require.Equal(t, common.Size{}, New(nil, BoundaryWrap).Size())
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		boundary  Boundary
+		position  common.Position
+		direction common.Direction
+		want      common.Position
+		wantOK    bool
+	}{
+		{
+			name:      "TestInside",
+			boundary:  BoundaryWall,
+			position:  common.Position{X: 2, Y: 1},
+			direction: Directions[0],
+			want:      common.Position{X: 2, Y: 0},
+			wantOK:    true,
+		},
+		{
+			name:      "TestWall",
+			boundary:  BoundaryWall,
+			position:  common.Position{X: 0, Y: 1},
+			direction: Directions[2],
+			want:      common.Position{X: 0, Y: 1},
+		},
+		{
+			name:      "TestWrapLeft",
+			boundary:  BoundaryWrap,
+			position:  common.Position{X: 0, Y: 1},
+			direction: Directions[2],
+			want:      common.Position{X: 4, Y: 1},
+			wantOK:    true,
+		},
+		{
+			name:      "TestWrapDown",
+			boundary:  BoundaryWrap,
+			position:  common.Position{X: 3, Y: 2},
+			direction: Directions[1],
+			want:      common.Position{X: 3, Y: 0},
+			wantOK:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Step(New(parse(rows...), tt.boundary), tt.position, tt.direction)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	from, to := common.Position{X: 0, Y: 0}, common.Position{X: 4, Y: 2}
+	require.Equal(t, 6, Distance(New(parse(rows...), BoundaryWall), from, to))
+	require.Equal(t, 2, Distance(New(parse(rows...), BoundaryWrap), from, to))
+}
+
+func TestDelta(t *testing.T) {
+	from, to := common.Position{X: 0, Y: 0}, common.Position{X: 4, Y: 2}
+	require.Equal(t, common.Direction{DX: 4, DY: 2}, Delta(New(parse(rows...), BoundaryWall), from, to))
+	require.Equal(t, common.Direction{DX: -1, DY: -1}, Delta(New(parse(rows...), BoundaryWrap), from, to))
+	require.Equal(t, common.Direction{DX: 1, DY: 1}, Delta(New(parse(rows...), BoundaryWrap), to, from))
+}
+
+func TestFloodFill(t *testing.T) {
+	walled := New(parse(rows...), BoundaryWall)
+	require.Equal(t, []common.Position{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+		FloodFill(walled, common.Position{X: 0, Y: 0}, open(walled)))
+
+	wrapped := New(parse(rows...), BoundaryWrap)
+	require.Len(t, FloodFill(wrapped, common.Position{X: 0, Y: 0}, open(wrapped)), 10)
+
+	// The origin is filled from even when it isn't passable
+	require.Len(t, FloodFill(walled, common.Position{X: 1, Y: 0}, open(walled)), 11)
+	require.Empty(t, FloodFill(walled, common.Position{X: 9, Y: 0}, open(walled)))
+}
+
+func TestDistanceMap(t *testing.T) {
+	wrapped := New(parse(rows...), BoundaryWrap)
+	distances := DistanceMap(wrapped, common.Position{X: 0, Y: 0}, open(wrapped))
+
+	require.Equal(t, 0, distances[0][0])
+	require.Equal(t, 1, distances[4][0])
+	require.Equal(t, 2, distances[3][0])
+	require.Equal(t, 3, distances[2][0])
+	require.Equal(t, Unreachable, distances[1][0])
+}
+
+func TestBFSAndAStar(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary Boundary
+		rows     []string
+		from, to common.Position
+		wantLen  int // Cells of the path, 0 when there is none
+	}{
+		{
+			name:     "TestAroundTheWall",
+			boundary: BoundaryWall,
+			rows:     []string{"...", ".#.", ".#.", "..."},
+			from:     common.Position{X: 0, Y: 1},
+			to:       common.Position{X: 2, Y: 1},
+			wantLen:  5,
+		},
+		{
+			name:     "TestThroughTheSides",
+			boundary: BoundaryWrap,
+			rows:     rows,
+			from:     common.Position{X: 0, Y: 2},
+			to:       common.Position{X: 2, Y: 2},
+			wantLen:  6,
+		},
+		{
+			name:     "TestWalledIn",
+			boundary: BoundaryWall,
+			rows:     rows,
+			from:     common.Position{X: 0, Y: 2},
+			to:       common.Position{X: 2, Y: 2},
+		},
+		{
+			name:     "TestSameCell",
+			boundary: BoundaryWall,
+			rows:     rows,
+			from:     common.Position{X: 4, Y: 1},
+			to:       common.Position{X: 4, Y: 1},
+			wantLen:  1,
+		},
+		{
+			name:     "TestOutOfTheGrid",
+			boundary: BoundaryWrap,
+			rows:     rows,
+			from:     common.Position{X: 0, Y: 0},
+			to:       common.Position{X: 0, Y: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGrid := New(parse(tt.rows...), tt.boundary)
+
+			for name, find := range map[string]func(Grider, common.Position, common.Position,
+				Passable) ([]common.Position, bool){"BFS": BFS, "AStar": AStar} {
+				path, ok := find(aGrid, tt.from, tt.to, open(aGrid))
+				require.Equal(t, tt.wantLen > 0, ok, name)
+				require.Len(t, path, tt.wantLen, name)
+
+				if !ok {
+					continue
+				}
+
+				// The path goes from a cell to a neighbour, through open cells
+				require.Equal(t, tt.from, path[0], name)
+				require.Equal(t, tt.to, path[len(path)-1], name)
+
+				for i := 1; i < len(path); i++ {
+					require.Contains(t, Neighbours(aGrid, path[i-1]), path[i], name)
+					require.NotEqual(t, '#', aGrid.Cell(path[i]), name)
+				}
+			}
+		})
+	}
+}
+
+func TestComponents(t *testing.T) {
+	walled := New(parse(rows...), BoundaryWall)
+	components := Components(walled, open(walled))
+	require.Len(t, components, 2)
+	require.Equal(t, common.Position{X: 0, Y: 0}, components[0][0])
+	require.Len(t, components[0], 3)
+	require.Len(t, components[1], 7)
+
+	wrapped := New(parse(rows...), BoundaryWrap)
+	require.Len(t, Components(wrapped, open(wrapped)), 1)
+}
@@ -0,0 +1,250 @@
+package keybindings
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/uimanager"
+	"sort"
+	"strings"
+)
+
+// Action is what a key does in the game
+type Action int
+
+// Actions which can be bound
+const (
+	ActionNone Action = iota
+	ActionUp
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionStart
+	ActionResize // Also resumes a crashed game
+	ActionPause
+	ActionTheme
+	ActionExportGIF
+	ActionSnapshot
+	ActionAutopilot
+	ActionQuit
+	ActionScrollUp
+	ActionScrollDown
+	ActionErrorDetails
+	ActionCloseDetails
+	ActionInjectError
+	ActionInjectPanic
+)
+
+// actionNames are the names of the actions, as written in the configuration
+var actionNames = []string{
+	ActionNone:         "none",
+	ActionUp:           "up",
+	ActionDown:         "down",
+	ActionLeft:         "left",
+	ActionRight:        "right",
+	ActionStart:        "start",
+	ActionResize:       "resize",
+	ActionPause:        "pause",
+	ActionTheme:        "theme",
+	ActionExportGIF:    "export_gif",
+	ActionSnapshot:     "snapshot",
+	ActionAutopilot:    "autopilot",
+	ActionQuit:         "quit",
+	ActionScrollUp:     "scroll_up",
+	ActionScrollDown:   "scroll_down",
+	ActionErrorDetails: "error_details",
+	ActionCloseDetails: "close_details",
+	ActionInjectError:  "inject_error",
+	ActionInjectPanic:  "inject_panic",
+}
+
+// DefaultPreset is the name of the preset used when none is configured
+const DefaultPreset = "arrows"
+
+// Defines custom errors
+var (
+	ErrUnknownAction = errors.New("unknown action")
+	ErrUnknownPreset = errors.New("unknown preset")
+	ErrUnboundAction = errors.New("the action has no key")
+	ErrDuplicateKey  = errors.New("the key is bound to several actions")
+)
+
+// Bindings is the table of the keys bound to each action
+type Bindings map[Action][]uimanager.Key
+
+// presets only differ by their moves, the other actions are added by Preset
+var presets = map[string]Bindings{
+	"arrows": {
+		ActionUp:    {uimanager.KeyArrowUp},
+		ActionDown:  {uimanager.KeyArrowDown},
+		ActionLeft:  {uimanager.KeyArrowLeft},
+		ActionRight: {uimanager.KeyArrowRight},
+	},
+	"wasd": {
+		ActionUp:    {uimanager.KeyRune('w'), uimanager.KeyRune('W')},
+		ActionDown:  {uimanager.KeyRune('s'), uimanager.KeyRune('S')},
+		ActionLeft:  {uimanager.KeyRune('a'), uimanager.KeyRune('A')},
+		ActionRight: {uimanager.KeyRune('d'), uimanager.KeyRune('D')},
+	},
+	"hjkl": {
+		ActionUp:    {uimanager.KeyRune('k'), uimanager.KeyRune('K')},
+		ActionDown:  {uimanager.KeyRune('j'), uimanager.KeyRune('J')},
+		ActionLeft:  {uimanager.KeyRune('h'), uimanager.KeyRune('H')},
+		ActionRight: {uimanager.KeyRune('l'), uimanager.KeyRune('L')},
+	},
+	"zqsd": { // AZERTY keyboards
+		ActionUp:    {uimanager.KeyRune('z'), uimanager.KeyRune('Z')},
+		ActionDown:  {uimanager.KeyRune('s'), uimanager.KeyRune('S')},
+		ActionLeft:  {uimanager.KeyRune('q'), uimanager.KeyRune('Q')},
+		ActionRight: {uimanager.KeyRune('d'), uimanager.KeyRune('D')},
+	},
+}
+
+// commonBindings are the same in every preset
+var commonBindings = Bindings{
+	ActionStart:        {uimanager.KeySpace},
+	ActionResize:       {uimanager.KeyEnter},
+	ActionPause:        {uimanager.KeyRune('p'), uimanager.KeyRune('P')},
+	ActionTheme:        {uimanager.KeyRune('t'), uimanager.KeyRune('T')},
+	ActionExportGIF:    {uimanager.KeyRune('g'), uimanager.KeyRune('G')},
+	ActionSnapshot:     {uimanager.KeyRune('c'), uimanager.KeyRune('C')},
+	ActionAutopilot:    {uimanager.KeyRune('o'), uimanager.KeyRune('O')},
+	ActionQuit:         {uimanager.KeyCtrlC},
+	ActionScrollUp:     {uimanager.KeyPgup},
+	ActionScrollDown:   {uimanager.KeyPgdn},
+	ActionErrorDetails: {uimanager.KeyTab},
+	ActionCloseDetails: {uimanager.KeyEsc},
+	ActionInjectError:  {uimanager.KeyF9},
+	ActionInjectPanic:  {uimanager.KeyF10},
+}
+
+// Actions returns the actions which can be bound, in display order
+func Actions() (actions []Action) {
+	for action := ActionUp; int(action) < len(actionNames); action++ {
+		actions = append(actions, action)
+	}
+
+	return actions
+}
+
+// ParseAction returns the action named name
+func ParseAction(name string) (action Action, err error) {
+	for i := range actionNames {
+		if Action(i) != ActionNone && actionNames[i] == strings.ToLower(name) {
+			return Action(i), nil
+		}
+	}
+
+	return action, fmt.Errorf("%w: %q", ErrUnknownAction, name)
+}
+
+// String returns the name of the action
+func (action Action) String() string {
+	if action < 0 || int(action) >= len(actionNames) {
+		return fmt.Sprintf("action %d", int(action))
+	}
+
+	return actionNames[action]
+}
+
+// MarshalText allows actions to be written by name in the configuration
+func (action Action) MarshalText() ([]byte, error) {
+	return []byte(action.String()), nil
+}
+
+// UnmarshalText allows actions to be read by name from the configuration
+func (action *Action) UnmarshalText(text []byte) (err error) {
+	*action, err = ParseAction(string(text))
+
+	return err
+}
+
+// Presets returns the names of the presets
+func Presets() (names []string) {
+	for name := range presets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Preset returns a copy of the bindings of the preset named name
+func Preset(name string) (bindings Bindings, err error) {
+	moves, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownPreset, name,
+			strings.Join(Presets(), ", "))
+	}
+
+	bindings = make(Bindings, len(actionNames))
+	bindings.Override(moves)
+	bindings.Override(commonBindings)
+
+	return bindings, nil
+}
+
+// Override replaces the keys of the actions bound in overrides
+func (bindings Bindings) Override(overrides Bindings) {
+	for action, keys := range overrides {
+		bindings[action] = append([]uimanager.Key(nil), keys...)
+	}
+}
+
+// Validate checks that every action has a key and that no key is bound twice
+func (bindings Bindings) Validate() (err error) {
+	used := make(map[uimanager.Key]Action)
+
+	for _, action := range Actions() {
+		if len(bindings[action]) == 0 {
+			return fmt.Errorf("%w: %v", ErrUnboundAction, action)
+		}
+
+		for _, key := range bindings[action] {
+			if other, ok := used[key]; ok {
+				return fmt.Errorf("%w: %v is bound to %v and %v", ErrDuplicateKey, key, other, action)
+			}
+
+			used[key] = action
+		}
+	}
+
+	for action := range bindings {
+		if action <= ActionNone || int(action) >= len(actionNames) {
+			return fmt.Errorf("%w: %v", ErrUnknownAction, action)
+		}
+	}
+
+	return nil
+}
+
+// Action returns the action bound to key, ActionNone if there is none
+func (bindings Bindings) Action(key uimanager.Key) Action {
+	for action, keys := range bindings {
+		for i := range keys {
+			if keys[i] == key {
+				return action
+			}
+		}
+	}
+
+	return ActionNone
+}
+
+// Keys returns all the bound keys, in the order of the actions
+func (bindings Bindings) Keys() (keys []uimanager.Key) {
+	for _, action := range Actions() {
+		keys = append(keys, bindings[action]...)
+	}
+
+	return keys
+}
+
+// Label returns the name of the first key bound to action, as displayed in the help
+func (bindings Bindings) Label(action Action) string {
+	if len(bindings[action]) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(bindings[action][0].String())
+}
@@ -0,0 +1,136 @@
+package keybindings
+
+import (
+	"gosnake/pkg/uimanager"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		name        string
+		preset      string
+		key         uimanager.Key
+		wantAction  Action
+		wantErrType error
+	}{
+		{
+			name:       "TestArrows",
+			preset:     "arrows",
+			key:        uimanager.KeyArrowLeft,
+			wantAction: ActionLeft,
+		},
+		{
+			name:       "TestWASD",
+			preset:     "WASD",
+			key:        uimanager.KeyRune('W'),
+			wantAction: ActionUp,
+		},
+		{
+			name:       "TestHJKL",
+			preset:     "hjkl",
+			key:        uimanager.KeyRune('j'),
+			wantAction: ActionDown,
+		},
+		{
+			name:       "TestZQSD",
+			preset:     "zqsd",
+			key:        uimanager.KeyRune('q'),
+			wantAction: ActionLeft,
+		},
+		{
+			name:       "TestCommonBindings",
+			preset:     "hjkl",
+			key:        uimanager.KeySpace,
+			wantAction: ActionStart,
+		},
+		{
+			name:       "TestUnboundKey",
+			preset:     "arrows",
+			key:        uimanager.KeyRune('w'),
+			wantAction: ActionNone,
+		},
+		{
+			name:        "TestUnknownPreset",
+			preset:      "dvorak",
+			wantErrType: ErrUnknownPreset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindings, err := Preset(tt.preset)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, bindings.Validate())
+			require.Equal(t, tt.wantAction, bindings.Action(tt.key))
+		})
+	}
+}
+
+func TestBindings_Override(t *testing.T) {
+	bindings, err := Preset(DefaultPreset)
+	require.NoError(t, err)
+
+	// The preset itself is not modified
+	bindings.Override(Bindings{ActionPause: {uimanager.KeyRune('x')}})
+	require.Equal(t, ActionPause, bindings.Action(uimanager.KeyRune('x')))
+	require.Equal(t, ActionNone, bindings.Action(uimanager.KeyRune('p')))
+	require.Equal(t, "X", bindings.Label(ActionPause))
+
+	bindings, err = Preset(DefaultPreset)
+	require.NoError(t, err)
+	require.Equal(t, "P", bindings.Label(ActionPause))
+	require.Len(t, bindings.Keys(), 23)
+}
+
+func TestBindings_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		overrides   Bindings
+		wantErrType error
+	}{
+		{
+			name:        "TestUnbound",
+			overrides:   Bindings{ActionQuit: nil},
+			wantErrType: ErrUnboundAction,
+		},
+		{
+			name:        "TestBoundTwice",
+			overrides:   Bindings{ActionPause: {uimanager.KeySpace}},
+			wantErrType: ErrDuplicateKey,
+		},
+		{
+			name:        "TestSameActionTwice",
+			overrides:   Bindings{ActionPause: {uimanager.KeyRune('x'), uimanager.KeyRune('x')}},
+			wantErrType: ErrDuplicateKey,
+		},
+		{
+			name:        "TestUnknownAction",
+			overrides:   Bindings{Action(100): {uimanager.KeyRune('x')}},
+			wantErrType: ErrUnknownAction,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bindings, err := Preset(DefaultPreset)
+			require.NoError(t, err)
+			bindings.Override(tt.overrides)
+			require.ErrorIs(t, bindings.Validate(), tt.wantErrType)
+		})
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	for _, action := range Actions() {
+		gotAction, err := ParseAction(action.String())
+		require.NoError(t, err)
+		require.Equal(t, action, gotAction)
+	}
+
+	_, err := ParseAction("none")
+	require.ErrorIs(t, err, ErrUnknownAction)
+}
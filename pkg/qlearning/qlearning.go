@@ -0,0 +1,208 @@
+package qlearning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/grid"
+	"gosnake/pkg/strategy"
+	"io"
+	"os"
+)
+
+// Sizes of the encoding of the states: a danger bit per direction, the side of the candy on each axis and the heading
+const (
+	dangerStates  = 1 << 4
+	candyStates   = 3 * 3
+	headingStates = 4
+
+	StateCount  = dangerStates * candyStates * headingStates
+	ActionCount = 4 // The directions of strategy.Directions
+)
+
+// tableFormat and tableVersion head the files of the tables
+const (
+	tableFormat  = "gosnake-qtable"
+	tableVersion = 1
+)
+
+// ErrInvalidTable is returned when a file doesn't hold a table of this encoding
+var ErrInvalidTable = errors.New("invalid Q-table")
+
+// Table holds the value of each action in each state
+type Table struct {
+	Q       [][]float64 // Q[state][action]
+	Visited [][]int     // Updates of each value, the actions never tried are known
+}
+
+// tableFile is the content of the files
+type tableFile struct {
+	Format  string      `json:"format"`
+	Version int         `json:"version"`
+	States  int         `json:"states"`
+	Actions int         `json:"actions"`
+	Q       [][]float64 `json:"q"`
+	Visited [][]int     `json:"visited"`
+}
+
+// NewTable returns a table knowing nothing
+func NewTable() *Table {
+	table := &Table{Q: make([][]float64, StateCount), Visited: make([][]int, StateCount)}
+
+	for state := range table.Q {
+		table.Q[state], table.Visited[state] = make([]float64, ActionCount), make([]int, ActionCount)
+	}
+
+	return table
+}
+
+// Encode returns the state of a game in the table
+func Encode(state strategy.State) int {
+	danger := 0
+
+	for i, direction := range strategy.Directions {
+		if !state.Safe(direction) {
+			danger |= 1 << i
+		}
+	}
+
+	// The candy is on the shortest way, through the sides of the board
+	candy := candyStates / 2
+	if state.CandyAlive {
+		delta := grid.Delta(state.Grid(), state.Head(), state.Candy)
+		candy = (sign(delta.DY)+1)*3 + sign(delta.DX) + 1
+	}
+
+	return (danger*candyStates+candy)*headingStates + directionIndex(state.Direction)
+}
+
+func sign(value int) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	}
+
+	return 0
+}
+
+func directionIndex(direction common.Direction) int {
+	for i, aDirection := range strategy.Directions {
+		if aDirection == direction {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// Best returns the action of greatest value in a state, the first one wins the ties
+func (table *Table) Best(state int) (action int) {
+	for i, value := range table.Q[state] {
+		if value > table.Q[state][action] {
+			action = i
+		}
+	}
+
+	return action
+}
+
+// Save writes the table as JSON
+func (table *Table) Save(w io.Writer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return json.NewEncoder(w).Encode(tableFile{
+		Format:  tableFormat,
+		Version: tableVersion,
+		States:  StateCount,
+		Actions: ActionCount,
+		Q:       table.Q,
+		Visited: table.Visited,
+	})
+}
+
+// Load reads a table written by Save
+func Load(r io.Reader) (table *Table, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var file tableFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+
+	if file.Format != tableFormat || file.Version != tableVersion || file.States != StateCount ||
+		file.Actions != ActionCount || len(file.Q) != StateCount || len(file.Visited) != StateCount {
+		return nil, fmt.Errorf("%w: %s version %d, %d states, %d actions", ErrInvalidTable,
+			file.Format, file.Version, file.States, file.Actions)
+	}
+
+	for state := range file.Q {
+		if len(file.Q[state]) != ActionCount || len(file.Visited[state]) != ActionCount {
+			return nil, fmt.Errorf("%w: state %d", ErrInvalidTable, state)
+		}
+	}
+
+	return &Table{Q: file.Q, Visited: file.Visited}, nil
+}
+
+// SaveFile writes the table to a file
+func (table *Table) SaveFile(fileName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err := table.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// LoadFile reads a table from a file
+func LoadFile(fileName string) (table *Table, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Load(file)
+}
+
+// agent plays the best action of its table
+type agent struct {
+	table *Table
+}
+
+// NewAgent returns a strategy playing table
+func NewAgent(table *Table) strategy.Strategyer {
+	return &agent{table: table}
+}
+
+func (anAgent *agent) Name() string {
+	return "qlearning"
+}
+
+// NextMove plays the best action of the state, the snake goes straight in the states never met
+func (anAgent *agent) NextMove(state strategy.State) common.Direction {
+	if len(state.Body) == 0 {
+		return state.Direction
+	}
+
+	encoded := Encode(state)
+	for _, visited := range anAgent.table.Visited[encoded] {
+		if visited > 0 {
+			return strategy.Directions[anAgent.table.Best(encoded)]
+		}
+	}
+
+	return state.Direction
+}
@@ -0,0 +1,136 @@
+package qlearning
+
+import (
+	"bytes"
+	"gosnake/pkg/common"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/strategy/strategytest"
+	"gosnake/pkg/tournament"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// boardSize is the size of the boards of the tests
+var boardSize = common.Size{Width: 10, Height: 10}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		state strategy.State
+		want  int
+	}{
+		{
+			name:  "TestNoDangerNoCandy",
+			state: strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Up, nil),
+			want:  (0*candyStates+4)*headingStates + 0,
+		},
+		{
+			name:  "TestCandyUpLeft",
+			state: strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Right, &common.Position{X: 2, Y: 1}),
+			want:  (0*candyStates+0)*headingStates + 3,
+		},
+		{
+			name: "TestCandyThroughTheSide", // 2 cells to the left through the side, not 8 to the right
+			state: strategytest.New(boardSize, []common.Position{{X: 1, Y: 5}}, strategy.Left,
+				&common.Position{X: 9, Y: 5}),
+			want: (0*candyStates+3)*headingStates + 2,
+		},
+		{
+			name: "TestDanger", // The body is below and on the right, the tail above leaves its cell
+			state: strategytest.New(boardSize, []common.Position{{X: 5, Y: 3}, {X: 4, Y: 3}, {X: 3, Y: 3}, {X: 3, Y: 4},
+				{X: 3, Y: 5}, {X: 4, Y: 5}, {X: 5, Y: 5}, {X: 6, Y: 5}, {X: 6, Y: 4}, {X: 5, Y: 4}}, strategy.Left,
+				&common.Position{X: 5, Y: 8}),
+			want: (0b1010*candyStates+7)*headingStates + 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Encode(tt.state))
+		})
+	}
+}
+
+func TestTable_SaveLoad(t *testing.T) {
+	table := NewTable()
+	table.Q[7][2], table.Visited[7][2] = 1.5, 3
+
+	fileName := filepath.Join(t.TempDir(), "qtable.json")
+	require.NoError(t, table.SaveFile(fileName))
+
+	loaded, err := LoadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, table, loaded)
+	require.Equal(t, 2, loaded.Best(7))
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+
+	for _, content := range []string{
+		"not json",
+		`{"format":"gosnake-qtable","version":1,"states":3,"actions":4}`,
+		`{"format":"gosnake-genome","version":1}`,
+	} {
+		_, err := Load(strings.NewReader(content))
+		require.ErrorIs(t, err, ErrInvalidTable, content)
+	}
+
+	var buffer bytes.Buffer
+	table.Q[0] = table.Q[0][:2]
+	require.NoError(t, table.Save(&buffer))
+	_, err = Load(&buffer)
+	require.ErrorIs(t, err, ErrInvalidTable)
+}
+
+func TestTrain(t *testing.T) {
+	settings := DefaultSettings()
+	settings.Episodes, settings.BoardSize = 600, 8
+
+	// The same settings train the same table
+	table, again := NewTable(), NewTable()
+	episodes := 0
+	require.NoError(t, Train(table, settings, func(episode int, outcome Episode) {
+		require.Equal(t, episodes, episode)
+		episodes++
+	}))
+	require.NoError(t, Train(again, settings, nil))
+	require.Equal(t, settings.Episodes, episodes)
+	require.Equal(t, table, again)
+
+	// The agent trained beats the random moves
+	strategy.Register("qlearning-test", func(int64) strategy.Strategyer { return NewAgent(table) })
+
+	report, err := tournament.Run(tournament.Settings{Strategies: []string{"qlearning-test", "random"},
+		BoardSizes: []int{8}, Games: 20, Seed: 1000, MaxRounds: 500, Workers: 1})
+	require.NoError(t, err)
+	require.Greater(t, report.Rows[0].Score.Mean, report.Rows[1].Score.Mean)
+}
+
+func TestTrainErrors(t *testing.T) {
+	for _, change := range []func(settings *Settings){
+		func(settings *Settings) { settings.Episodes = 0 },
+		func(settings *Settings) { settings.BoardSize = 2 },
+		func(settings *Settings) { settings.Alpha = 0 },
+		func(settings *Settings) { settings.Gamma = 2 },
+		func(settings *Settings) { settings.MinEpsilon = 2 },
+	} {
+		settings := DefaultSettings()
+		change(&settings)
+		require.ErrorIs(t, Train(NewTable(), settings, nil), ErrInvalidSettings)
+	}
+}
+
+func TestAgent_NextMove(t *testing.T) {
+	table := NewTable()
+	agent := NewAgent(table)
+	require.Equal(t, "qlearning", agent.Name())
+
+	// A state never met keeps the snake straight
+	state := strategytest.New(boardSize, []common.Position{{X: 5, Y: 5}}, strategy.Right, &common.Position{X: 5, Y: 1})
+	require.Equal(t, strategy.Right, agent.NextMove(state))
+
+	table.Q[Encode(state)][0], table.Visited[Encode(state)][0] = 1, 1
+	require.Equal(t, strategy.Up, agent.NextMove(state))
+}
@@ -0,0 +1,168 @@
+package qlearning
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"math/rand"
+)
+
+// ErrInvalidSettings is returned when the training can't run with the settings
+var ErrInvalidSettings = errors.New("invalid training settings")
+
+// Settings of a training
+type Settings struct {
+	Episodes   int
+	BoardSize  int
+	MaxRounds  int   // Rounds per episode, gamestate.RoundsPerCell for each cell of the board when 0
+	Seed       int64 // The episode i is played with the seed Seed+i, the explorations are drawn from Seed
+	Alpha      float64
+	Gamma      float64
+	Epsilon    float64 // Chance of a random action in the first episode, it decreases to MinEpsilon in the last one
+	MinEpsilon float64
+	Candy      float64 // Reward of a candy eaten
+	Death      float64 // Reward of a collision
+	Closer     float64 // Reward of a move closer to the candy, a move away gives its opposite
+}
+
+// DefaultSettings learns to play on small boards in a few seconds
+func DefaultSettings() Settings {
+	return Settings{
+		Episodes:   5000,
+		BoardSize:  10,
+		Seed:       1,
+		Alpha:      0.1,
+		Gamma:      0.9,
+		Epsilon:    1,
+		MinEpsilon: 0.01,
+		Candy:      10,
+		Death:      -10,
+		Closer:     1,
+	}
+}
+
+// Episode is the outcome of an episode of training
+type Episode struct {
+	Score  int
+	Rounds int
+}
+
+// Train plays the episodes with table, updated after each round
+// progress is called after each episode when it isn't nil
+func Train(table *Table, settings Settings, progress func(episode int, outcome Episode)) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := settings.validate(); err != nil {
+		return err
+	}
+
+	if settings.MaxRounds == 0 {
+		settings.MaxRounds = gamestate.RoundsPerCell * settings.BoardSize * settings.BoardSize
+	}
+
+	rnd := rand.New(rand.NewSource(settings.Seed))
+
+	for episode := 0; episode < settings.Episodes; episode++ {
+		epsilon := settings.Epsilon
+		if settings.Episodes > 1 {
+			epsilon -= (settings.Epsilon - settings.MinEpsilon) * float64(episode) / float64(settings.Episodes-1)
+		}
+
+		outcome, err := train(table, settings, settings.Seed+int64(episode), epsilon, rnd)
+		if err != nil {
+			return err
+		}
+
+		if progress != nil {
+			progress(episode, outcome)
+		}
+	}
+
+	return nil
+}
+
+func (settings Settings) validate() error {
+	if settings.Episodes < 1 || settings.BoardSize < gamestate.MinBoardSize || settings.MaxRounds < 0 {
+		return fmt.Errorf("%w: %d episodes on a board of %d, %d rounds", ErrInvalidSettings,
+			settings.Episodes, settings.BoardSize, settings.MaxRounds)
+	}
+
+	if settings.Alpha <= 0 || settings.Alpha > 1 || settings.Gamma < 0 || settings.Gamma > 1 ||
+		settings.Epsilon < 0 || settings.Epsilon > 1 || settings.MinEpsilon < 0 || settings.MinEpsilon > settings.Epsilon {
+		return fmt.Errorf("%w: alpha %v, gamma %v, epsilon %v to %v", ErrInvalidSettings,
+			settings.Alpha, settings.Gamma, settings.Epsilon, settings.MinEpsilon)
+	}
+
+	return nil
+}
+
+// train plays an episode, the snake is steered as the keys do
+func train(table *Table, settings Settings, seed int64, epsilon float64, rnd *rand.Rand) (outcome Episode, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	gameState, err := gamestate.NewSeeded(common.Size{Width: settings.BoardSize, Height: settings.BoardSize}, seed)
+	if err != nil {
+		return outcome, err
+	}
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return outcome, err
+	}
+
+	for gameState.GameInProgress() && gameState.Round() < settings.MaxRounds {
+		encoded := Encode(state)
+
+		action := table.Best(encoded)
+		if rnd.Float64() < epsilon {
+			action = rnd.Intn(ActionCount)
+		}
+
+		score := gameState.Score()
+		strategy.Steer(gameState, strategy.Directions[action])
+
+		roundOutcome, err := gamestate.PlayRound(gameState)
+		if err != nil {
+			return outcome, err
+		}
+
+		next, err := strategy.ReadState(gameState)
+		if err != nil {
+			return outcome, err
+		}
+
+		// The value of the next state is only known while the game goes on
+		reward, future := 0.0, 0.0
+
+		switch {
+		case roundOutcome == gamestate.OutcomeBoardFull:
+			reward = settings.Candy
+		case roundOutcome == gamestate.OutcomeCollision:
+			reward = settings.Death
+		default:
+			if gameState.Score() > score {
+				reward = settings.Candy
+			} else if state.CandyAlive {
+				reward = settings.Closer
+				if next.Distance(next.Head(), state.Candy) > state.Distance(state.Head(), state.Candy) {
+					reward = -settings.Closer
+				}
+			}
+
+			future = settings.Gamma * table.Q[Encode(next)][table.Best(Encode(next))]
+		}
+
+		table.Q[encoded][action] += settings.Alpha * (reward + future - table.Q[encoded][action])
+		table.Visited[encoded][action]++
+
+		if roundOutcome == gamestate.OutcomeBoardFull {
+			break
+		}
+
+		state = next
+	}
+
+	return Episode{Score: gameState.Score(), Rounds: gameState.Round()}, nil
+}
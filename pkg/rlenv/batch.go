@@ -0,0 +1,121 @@
+package rlenv
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"sync"
+)
+
+// ErrBatchSize is returned when the seeds or the actions don't match the environments of a batch
+var ErrBatchSize = errors.New("the batch size doesn't match")
+
+// Batcher is the interface for batch
+type Batcher interface {
+	Len() int
+	Reset(seeds []int64) (observations []Observation, err error)
+	Step(actions []Action) (results []StepResult, err error)
+}
+
+// batch steps its environments side by side
+// An episode over starts again with its seed plus the batch size, the seeds of the batch never collide
+type batch struct {
+	environments []Environmenter
+	seeds        []int64
+}
+
+// NewBatch returns an instance of batch with size environments
+func NewBatch(size int, settings Settings) (aBatch Batcher, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if size < 1 {
+		return nil, fmt.Errorf("%w: %d environments", ErrInvalidSettings, size)
+	}
+
+	newBatch := &batch{environments: make([]Environmenter, size), seeds: make([]int64, size)}
+
+	for i := range newBatch.environments {
+		if newBatch.environments[i], err = New(settings); err != nil {
+			return nil, err
+		}
+	}
+
+	return newBatch, nil
+}
+
+func (aBatch *batch) Len() int {
+	return len(aBatch.environments)
+}
+
+// Reset starts an episode in each environment with its seed
+func (aBatch *batch) Reset(seeds []int64) (observations []Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if len(seeds) != aBatch.Len() {
+		return nil, fmt.Errorf("%w: %d seeds for %d environments", ErrBatchSize, len(seeds), aBatch.Len())
+	}
+
+	observations = make([]Observation, aBatch.Len())
+
+	err = aBatch.each(func(i int) (err error) {
+		aBatch.seeds[i] = seeds[i]
+		observations[i], err = aBatch.environments[i].Reset(seeds[i])
+
+		return err
+	})
+
+	return observations, err
+}
+
+// Step plays a round in each environment, the ones done are reset at once
+// Their result holds the first observation of the next episode, the last one is the final_observation of the info map
+func (aBatch *batch) Step(actions []Action) (results []StepResult, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if len(actions) != aBatch.Len() {
+		return nil, fmt.Errorf("%w: %d actions for %d environments", ErrBatchSize, len(actions), aBatch.Len())
+	}
+
+	results = make([]StepResult, aBatch.Len())
+
+	err = aBatch.each(func(i int) (err error) {
+		if results[i], err = aBatch.environments[i].Step(actions[i]); err != nil || !results[i].Done {
+			return err
+		}
+
+		results[i].Info["final_observation"] = results[i].Observation
+		aBatch.seeds[i] += int64(aBatch.Len())
+		results[i].Observation, err = aBatch.environments[i].Reset(aBatch.seeds[i])
+
+		return err
+	})
+
+	return results, err
+}
+
+// each runs do for each environment in parallel, the error of the first environment failing is returned
+func (aBatch *batch) each(do func(i int) error) error {
+	errs := make([]error, aBatch.Len())
+
+	var wait sync.WaitGroup
+
+	for i := range aBatch.environments {
+		wait.Add(1)
+
+		go func(i int) {
+			defer wait.Done()
+
+			errs[i] = do(i)
+		}(i)
+	}
+
+	wait.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
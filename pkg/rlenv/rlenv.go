@@ -0,0 +1,254 @@
+package rlenv
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+)
+
+// Action is the direction the snake takes for the next round
+type Action int
+
+// Actions of the snake, as the keys
+const (
+	ActionUp Action = iota
+	ActionDown
+	ActionLeft
+	ActionRight
+	ActionCount // Number of actions
+)
+
+// actionDirections are the directions of the actions
+var actionDirections = []common.Direction{
+	ActionUp:    strategy.Up,
+	ActionDown:  strategy.Down,
+	ActionLeft:  strategy.Left,
+	ActionRight: strategy.Right,
+}
+
+// Channels of the observations, each one is a plane of the board holding 1 where the element is
+const (
+	ChannelHead = iota
+	ChannelBody // The snake without its head
+	ChannelCandy
+	ChannelCount // Number of channels
+)
+
+// Causes of the end of the episodes, in the info map
+const (
+	CauseCollision  = "collision"
+	CauseRoundLimit = "round limit"
+	CauseBoardFull  = "board full"
+)
+
+// Defines custom errors
+var (
+	ErrInvalidSettings = errors.New("invalid environment settings")
+	ErrInvalidAction   = errors.New("invalid action")
+	ErrEpisodeOver     = errors.New("the episode is over, the environment must be reset")
+)
+
+// Rewards shape the rewards given after each step
+type Rewards struct {
+	Candy  float64 // Eating a candy
+	Death  float64 // Running into the snake, in place of the other rewards
+	Step   float64 // Each round survived, a negative one hurries the snake
+	Closer float64 // Moving closer to the candy, moving away gives its opposite
+	Win    float64 // Filling the board, added to the candy eaten
+}
+
+// DefaultRewards are the rewards when none is configured
+func DefaultRewards() Rewards {
+	return Rewards{Candy: 1, Death: -1, Step: -0.01, Win: 10}
+}
+
+// Settings of an environment
+type Settings struct {
+	BoardSize common.Size
+	Rewards   Rewards
+	MaxRounds int // Rounds per episode, gamestate.RoundsPerCell for each cell of the board when 0
+}
+
+// Observation is a tensor of ChannelCount planes of Height rows of Width cells
+type Observation struct {
+	Channels int
+	Height   int
+	Width    int
+	Data     []float32 // Data[(channel*Height+y)*Width+x]
+}
+
+// StepResult is what the environment returns after an action
+type StepResult struct {
+	Observation Observation
+	Reward      float64
+	Done        bool
+	Info        map[string]interface{} // score, round, length, seed, and cause and truncated when done
+}
+
+// Environmenter is the interface for environment
+type Environmenter interface {
+	Reset(seed int64) (observation Observation, err error)
+	Step(action Action) (result StepResult, err error)
+}
+
+// environment plays an episode at a time with the rules of the game, with a seeded game state
+type environment struct {
+	settings  Settings
+	gameState gamestate.GameStater
+	seed      int64
+	done      bool
+}
+
+// New returns an instance of environment, Reset starts the first episode
+func New(settings Settings) (anEnvironment Environmenter, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	return &environment{settings: settings, done: true}, nil
+}
+
+func (settings Settings) validate() error {
+	if settings.BoardSize.Width < gamestate.MinBoardSize || settings.BoardSize.Height < gamestate.MinBoardSize {
+		return fmt.Errorf("%w: the board must be at least %dx%d, got %dx%d", ErrInvalidSettings,
+			gamestate.MinBoardSize, gamestate.MinBoardSize, settings.BoardSize.Width, settings.BoardSize.Height)
+	}
+
+	if settings.MaxRounds < 0 {
+		return fmt.Errorf("%w: %d rounds", ErrInvalidSettings, settings.MaxRounds)
+	}
+
+	return nil
+}
+
+// Reset starts an episode, the same seed places the candies at the same places
+func (anEnvironment *environment) Reset(seed int64) (observation Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	gameState, err := gamestate.NewSeeded(anEnvironment.settings.BoardSize, seed)
+	if err != nil {
+		return observation, err
+	}
+
+	anEnvironment.gameState, anEnvironment.seed, anEnvironment.done = gameState, seed, false
+
+	state, err := strategy.ReadState(gameState)
+	if err != nil {
+		return observation, err
+	}
+
+	return observe(state), nil
+}
+
+// Step plays a round in the direction of action
+func (anEnvironment *environment) Step(action Action) (result StepResult, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if anEnvironment.done {
+		return result, ErrEpisodeOver
+	}
+
+	if action < 0 || action >= ActionCount {
+		return result, fmt.Errorf("%w: %d", ErrInvalidAction, action)
+	}
+
+	gameState, rewards := anEnvironment.gameState, anEnvironment.settings.Rewards
+
+	before, err := strategy.ReadState(gameState)
+	if err != nil {
+		return result, err
+	}
+
+	score := gameState.Score()
+
+	strategy.Steer(gameState, actionDirections[action])
+
+	result.Info = map[string]interface{}{"seed": anEnvironment.seed}
+
+	outcome, err := gamestate.PlayRound(gameState)
+	if err != nil {
+		return result, err
+	}
+
+	after, err := strategy.ReadState(gameState)
+	if err != nil {
+		return result, err
+	}
+
+	result.Observation = observe(after)
+
+	switch {
+	case outcome == gamestate.OutcomeBoardFull:
+		result.Reward, result.Done = rewards.Candy+rewards.Win, true
+		result.Info["cause"] = CauseBoardFull
+	case outcome == gamestate.OutcomeCollision:
+		result.Reward, result.Done = rewards.Death, true
+		result.Info["cause"] = CauseCollision
+	default:
+		result.Reward = rewards.Step
+
+		if gameState.Score() > score {
+			result.Reward += rewards.Candy
+		} else if before.CandyAlive {
+			distance := after.Distance(after.Head(), before.Candy) - before.Distance(before.Head(), before.Candy)
+			result.Reward -= rewards.Closer * float64(distance)
+		}
+
+		if gameState.Round() >= anEnvironment.settings.maxRounds() {
+			result.Done = true
+			result.Info["cause"] = CauseRoundLimit
+		}
+	}
+
+	if result.Done {
+		result.Info["truncated"] = result.Info["cause"] == CauseRoundLimit
+	}
+
+	result.Info["score"], result.Info["round"], result.Info["length"] =
+		gameState.Score(), gameState.Round(), len(after.Body)
+	anEnvironment.done = result.Done
+
+	return result, nil
+}
+
+func (settings Settings) maxRounds() int {
+	if settings.MaxRounds > 0 {
+		return settings.MaxRounds
+	}
+
+	return gamestate.RoundsPerCell * settings.BoardSize.Width * settings.BoardSize.Height
+}
+
+// observe encodes the board in channels
+func observe(state strategy.State) Observation {
+	observation := Observation{Channels: ChannelCount, Height: state.Size.Height, Width: state.Size.Width}
+	observation.Data = make([]float32, ChannelCount*state.Size.Height*state.Size.Width)
+
+	for i, position := range state.Body {
+		channel := ChannelBody
+		if i == len(state.Body)-1 {
+			channel = ChannelHead
+		}
+
+		observation.set(channel, position)
+	}
+
+	if state.CandyAlive {
+		observation.set(ChannelCandy, state.Candy)
+	}
+
+	return observation
+}
+
+func (observation Observation) set(channel int, position common.Position) {
+	observation.Data[(channel*observation.Height+position.Y)*observation.Width+position.X] = 1
+}
+
+// At returns the value of a cell in a channel
+func (observation Observation) At(channel, x, y int) float32 {
+	return observation.Data[(channel*observation.Height+y)*observation.Width+x]
+}
@@ -0,0 +1,192 @@
+package rlenv
+
+import (
+	"gosnake/pkg/common"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSettings = Settings{BoardSize: common.Size{Width: 8, Height: 6}, Rewards: DefaultRewards()}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		wantErr  bool
+	}{
+		{name: "TestValid", settings: testSettings},
+		{name: "TestBoardTooSmall", settings: Settings{BoardSize: common.Size{Width: 2, Height: 6}}, wantErr: true},
+		{name: "TestNegativeRounds", settings: Settings{BoardSize: testSettings.BoardSize, MaxRounds: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.settings)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidSettings)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestEnvironment_Reset(t *testing.T) {
+	environment, err := New(testSettings)
+	require.NoError(t, err)
+
+	_, err = environment.Step(ActionUp)
+	require.ErrorIs(t, err, ErrEpisodeOver)
+
+	observation, err := environment.Reset(7)
+	require.NoError(t, err)
+	require.Equal(t, ChannelCount, observation.Channels)
+	require.Equal(t, 6, observation.Height)
+	require.Equal(t, 8, observation.Width)
+	require.Len(t, observation.Data, ChannelCount*6*8)
+	require.Equal(t, float32(1), sum(observation, ChannelHead))
+	require.Equal(t, float32(1), sum(observation, ChannelCandy))
+	require.Equal(t, float32(0), sum(observation, ChannelBody), "the snake starts with its head alone")
+
+	_, err = environment.Step(ActionCount)
+	require.ErrorIs(t, err, ErrInvalidAction)
+}
+
+func TestEnvironment_Step(t *testing.T) {
+	// The same seed and the same actions give the same episode
+	first, second := play(t, testSettings, 11), play(t, testSettings, 11)
+	require.Equal(t, first, second)
+
+	last := first[len(first)-1]
+	require.True(t, last.Done)
+	require.Equal(t, CauseCollision, last.Info["cause"])
+	require.Equal(t, false, last.Info["truncated"])
+	require.Equal(t, testSettings.Rewards.Death, last.Reward)
+	require.Equal(t, int64(11), last.Info["seed"])
+	require.Equal(t, len(first), last.Info["round"])
+
+	// The candies eaten are in the score and in the rewards
+	score := 0
+	for _, result := range first[:len(first)-1] {
+		if result.Reward > 0 {
+			score++
+		}
+	}
+
+	require.Equal(t, score, last.Info["score"])
+}
+
+func TestEnvironment_StepRoundLimit(t *testing.T) {
+	settings := testSettings
+	settings.MaxRounds = 3
+
+	environment, err := New(settings)
+	require.NoError(t, err)
+	_, err = environment.Reset(1)
+	require.NoError(t, err)
+
+	for round := 1; round <= 3; round++ {
+		result, err := environment.Step(ActionRight)
+		require.NoError(t, err)
+		require.Equal(t, round == 3, result.Done)
+	}
+
+	_, err = environment.Step(ActionRight)
+	require.ErrorIs(t, err, ErrEpisodeOver)
+}
+
+func TestEnvironment_StepCloser(t *testing.T) {
+	settings := testSettings
+	settings.Rewards = Rewards{Closer: 1}
+
+	environment, err := New(settings)
+	require.NoError(t, err)
+	_, err = environment.Reset(3)
+	require.NoError(t, err)
+
+	// Moving around the board, each move without a candy is one step closer or farther
+	for round := 0; round < 20; round++ {
+		result, err := environment.Step(Action(round / 5 % int(ActionCount)))
+		require.NoError(t, err)
+
+		if result.Done {
+			break
+		}
+
+		require.Contains(t, []float64{-1, 0, 1}, result.Reward)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	_, err := NewBatch(0, testSettings)
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	aBatch, err := NewBatch(3, testSettings)
+	require.NoError(t, err)
+	require.Equal(t, 3, aBatch.Len())
+
+	_, err = aBatch.Reset([]int64{1})
+	require.ErrorIs(t, err, ErrBatchSize)
+
+	observations, err := aBatch.Reset([]int64{1, 2, 11})
+	require.NoError(t, err)
+	require.Len(t, observations, 3)
+
+	_, err = aBatch.Step([]Action{ActionUp})
+	require.ErrorIs(t, err, ErrBatchSize)
+
+	// The third environment replays the episode of the seed 11, then starts again with the seed 14
+	episode := play(t, testSettings, 11)
+	actions := rand.New(rand.NewSource(11))
+
+	for round, expected := range episode {
+		action := Action(actions.Intn(int(ActionCount)))
+
+		results, err := aBatch.Step([]Action{action, action, action})
+		require.NoError(t, err)
+		require.Equal(t, expected.Reward, results[2].Reward, "round %d", round+1)
+		require.Equal(t, expected.Done, results[2].Done)
+
+		if expected.Done {
+			require.Equal(t, expected.Observation, results[2].Info["final_observation"])
+			require.Equal(t, float32(1), sum(results[2].Observation, ChannelHead))
+		}
+	}
+
+	results, err := aBatch.Step([]Action{ActionUp, ActionUp, ActionUp})
+	require.NoError(t, err)
+	require.Equal(t, int64(14), results[2].Info["seed"])
+}
+
+// play plays an episode with actions drawn from seed, as the environment
+func play(t *testing.T, settings Settings, seed int64) (results []StepResult) {
+	environment, err := New(settings)
+	require.NoError(t, err)
+
+	_, err = environment.Reset(seed)
+	require.NoError(t, err)
+
+	actions := rand.New(rand.NewSource(seed))
+
+	for {
+		result, err := environment.Step(Action(actions.Intn(int(ActionCount))))
+		require.NoError(t, err)
+
+		results = append(results, result)
+		if result.Done {
+			return results
+		}
+	}
+}
+
+func sum(observation Observation, channel int) (total float32) {
+	for x := 0; x < observation.Width; x++ {
+		for y := 0; y < observation.Height; y++ {
+			total += observation.At(channel, x, y)
+		}
+	}
+
+	return total
+}
@@ -14,6 +14,9 @@ type Snaker interface {
 	NextMove() (nextPosition common.Position, err error)
 	MoveTo(newPosition common.Position) (theTail common.Position, err error)
 	GrowTo(newPosition common.Position) (err error)
+	Body() []common.Position
+	Direction() common.Direction
+	Clone() Snaker
 }
 
 type snake struct {
@@ -108,3 +111,17 @@ func (aSnake *snake) GrowTo(newPosition common.Position) (err error) {
 	aSnake.body = append(aSnake.body, newPosition)
 	return nil
 }
+
+// Body returns a copy of the body positions from the tail to the head
+func (aSnake *snake) Body() []common.Position {
+	return append([]common.Position(nil), aSnake.body...)
+}
+
+func (aSnake *snake) Direction() common.Direction {
+	return aSnake.direction
+}
+
+// Clone returns a copy of the snake which moves on its own
+func (aSnake *snake) Clone() Snaker {
+	return &snake{body: aSnake.Body(), direction: aSnake.direction}
+}
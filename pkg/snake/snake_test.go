@@ -655,3 +655,36 @@ func Test_snake_Tail(t *testing.T) {
 		})
 	}
 }
+
+func TestSnake_Body(t *testing.T) {
+	aSnake := &snake{
+		body:      []common.Position{testdata.Position0_0, testdata.Position1_2},
+		direction: testdata.Direction1_0,
+	}
+
+	body := aSnake.Body()
+	require.Equal(t, aSnake.body, body)
+	require.Equal(t, testdata.Direction1_0, aSnake.Direction())
+
+	// The body returned is a copy
+	body[0] = testdata.Position1_2
+	require.Equal(t, testdata.Position0_0, aSnake.body[0])
+}
+
+func TestSnake_Clone(t *testing.T) {
+	aSnake := &snake{
+		body:      []common.Position{testdata.Position0_0, testdata.Position1_2},
+		direction: testdata.Direction1_0,
+	}
+
+	aClone := aSnake.Clone()
+	require.Equal(t, aSnake.Body(), aClone.Body())
+	require.Equal(t, aSnake.Direction(), aClone.Direction())
+
+	// The clone moves on its own
+	aClone.SetDirection(testdata.DirectionMinus1_0)
+	_, err := aClone.MoveTo(testdata.Position2_2)
+	require.NoError(t, err)
+	require.Equal(t, []common.Position{testdata.Position0_0, testdata.Position1_2}, aSnake.body)
+	require.Equal(t, testdata.Direction1_0, aSnake.direction)
+}
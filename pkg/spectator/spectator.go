@@ -0,0 +1,251 @@
+package spectator
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/uimanager"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSpectators is the number of spectators accepted when none is configured
+const DefaultMaxSpectators = 8
+
+// writeTimeout disconnects the spectators who stop reading their frames
+const writeTimeout = 5 * time.Second
+
+// Escape sequences written to the terminals of the spectators
+const (
+	screenOpen  = "\x1b[2J\x1b[?25l" // Cleared screen, hidden cursor
+	screenClose = "\x1b[0m\x1b[?25h\r\n"
+)
+
+// fullMessage is written to the spectators who connect when there are too many
+const fullMessage = "Too many spectators, try again later\r\n"
+
+// Spectatorer is the interface for spectators
+type Spectatorer interface {
+	Start(address string, frame func() []string, interval time.Duration) (err error)
+	Address() string
+	Count() int
+	Close() (err error)
+}
+
+// spectators sends the frames of the game to the terminals connected with nc or telnet
+// Each spectator is written by its own routine, a slow one only misses frames
+type spectators struct {
+	maxSpectators int
+	onCount       func(count int) // Called when a spectator connects or leaves
+	listener      net.Listener
+	mutex         sync.Mutex
+	clients       map[*client]struct{}
+	done          chan struct{}
+}
+
+// client is a connected spectator
+type client struct {
+	conn   net.Conn
+	frames chan []string // The last frame not written yet
+	quit   chan struct{}
+	screen []string // The rows last written, the others are written again
+}
+
+// New returns an instance of spectators accepting maxSpectators connections at once
+func New(maxSpectators int, onCount func(count int)) Spectatorer {
+	return &spectators{
+		maxSpectators: maxSpectators,
+		onCount:       onCount,
+		clients:       make(map[*client]struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Start listens on address and sends the rows returned by frame every interval
+func (aSpectators *spectators) Start(address string, frame func() []string, interval time.Duration) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aSpectators.listener, err = net.Listen("tcp", address); err != nil {
+		return err
+	}
+
+	go aSpectators.accept()
+	go aSpectators.broadcast(frame, interval)
+
+	return nil
+}
+
+// Address returns the address listened on
+func (aSpectators *spectators) Address() string {
+	if aSpectators.listener == nil {
+		return ""
+	}
+
+	return aSpectators.listener.Addr().String()
+}
+
+// Count returns the number of spectators connected
+func (aSpectators *spectators) Count() int {
+	aSpectators.mutex.Lock()
+	defer aSpectators.mutex.Unlock()
+
+	return len(aSpectators.clients)
+}
+
+// Close disconnects the spectators and stops listening
+func (aSpectators *spectators) Close() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	close(aSpectators.done)
+
+	if aSpectators.listener != nil {
+		err = aSpectators.listener.Close()
+	}
+
+	aSpectators.mutex.Lock()
+	clients := aSpectators.clients
+	aSpectators.clients = make(map[*client]struct{})
+	aSpectators.mutex.Unlock()
+
+	for aClient := range clients {
+		close(aClient.quit)
+		aClient.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		io.WriteString(aClient.conn, screenClose)
+		aClient.conn.Close()
+	}
+
+	return err
+}
+
+func (aSpectators *spectators) accept() {
+	for {
+		conn, err := aSpectators.listener.Accept()
+		if err != nil {
+			// The listener was closed
+			return
+		}
+
+		aClient := &client{conn: conn, frames: make(chan []string, 1), quit: make(chan struct{})}
+
+		aSpectators.mutex.Lock()
+		full := len(aSpectators.clients) >= aSpectators.maxSpectators
+		if !full {
+			aSpectators.clients[aClient] = struct{}{}
+		}
+		count := len(aSpectators.clients)
+		aSpectators.mutex.Unlock()
+
+		if full {
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			io.WriteString(conn, fullMessage)
+			conn.Close()
+
+			continue
+		}
+
+		go aSpectators.write(aClient)
+		go aSpectators.read(aClient)
+
+		aSpectators.countChanged(count)
+	}
+}
+
+// broadcast offers the frames to the spectators, it never waits for them
+func (aSpectators *spectators) broadcast(frame func() []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-aSpectators.done:
+			return
+		case <-ticker.C:
+		}
+
+		aSpectators.mutex.Lock()
+		idle := len(aSpectators.clients) == 0
+		aSpectators.mutex.Unlock()
+
+		if idle {
+			continue
+		}
+
+		rows := frame()
+
+		aSpectators.mutex.Lock()
+		for aClient := range aSpectators.clients {
+			aClient.offer(rows)
+		}
+		aSpectators.mutex.Unlock()
+	}
+}
+
+// offer replaces the frame waiting to be written, if any
+func (aClient *client) offer(rows []string) {
+	select {
+	case <-aClient.frames:
+	default:
+	}
+
+	aClient.frames <- rows
+}
+
+// write sends the frames to the spectator, the spectators too slow are disconnected
+func (aSpectators *spectators) write(aClient *client) {
+	defer aSpectators.remove(aClient)
+
+	aClient.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	if _, err := io.WriteString(aClient.conn, screenOpen); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-aClient.quit:
+			return
+		case rows := <-aClient.frames:
+			changes := uimanager.FrameChanges(aClient.screen, rows)
+			if changes == "" {
+				continue
+			}
+
+			aClient.screen = rows
+
+			aClient.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+			if _, err := io.WriteString(aClient.conn, changes); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read ignores what the spectator types until it disconnects
+func (aSpectators *spectators) read(aClient *client) {
+	defer aSpectators.remove(aClient)
+
+	io.Copy(io.Discard, aClient.conn)
+}
+
+func (aSpectators *spectators) remove(aClient *client) {
+	aSpectators.mutex.Lock()
+	_, found := aSpectators.clients[aClient]
+	delete(aSpectators.clients, aClient)
+	count := len(aSpectators.clients)
+	aSpectators.mutex.Unlock()
+
+	if !found {
+		return
+	}
+
+	close(aClient.quit)
+	aClient.conn.Close()
+	aSpectators.countChanged(count)
+}
+
+func (aSpectators *spectators) countChanged(count int) {
+	if aSpectators.onCount != nil {
+		aSpectators.onCount(count)
+	}
+}
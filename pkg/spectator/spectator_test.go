@@ -0,0 +1,119 @@
+package spectator
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInterval = 5 * time.Millisecond
+
+// readUntil reads from conn until the text received contains want
+func readUntil(t *testing.T, conn net.Conn, want string) string {
+	var received strings.Builder
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	for !strings.Contains(received.String(), want) {
+		ch, _, err := reader.ReadRune()
+		require.NoError(t, err, received.String())
+		received.WriteRune(ch)
+	}
+
+	return received.String()
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 200 && !condition(); i++ {
+		time.Sleep(testInterval)
+	}
+
+	require.True(t, condition())
+}
+
+func TestSpectators(t *testing.T) {
+	var (
+		mutex  sync.Mutex
+		rows   = []string{"┌──┐", "│S │", "└──┘"}
+		counts []int
+	)
+
+	frame := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return rows
+	}
+
+	aSpectators := New(1, func(count int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		counts = append(counts, count)
+	})
+	require.NoError(t, aSpectators.Start("127.0.0.1:0", frame, testInterval))
+	defer aSpectators.Close()
+
+	conn, err := net.Dial("tcp", aSpectators.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The screen is cleared then the rows are written
+	received := readUntil(t, conn, "└──┘")
+	require.True(t, strings.HasPrefix(received, screenOpen))
+	require.Contains(t, received, "\x1b[2;1H│S │")
+	waitFor(t, func() bool { return aSpectators.Count() == 1 })
+
+	// Only the rows which changed are written again
+	mutex.Lock()
+	rows = []string{"┌──┐", "│ S│", "└──┘"}
+	mutex.Unlock()
+	received = readUntil(t, conn, "│ S│")
+	require.NotContains(t, received, "┌")
+
+	// The spectators beyond the limit are turned away
+	other, err := net.Dial("tcp", aSpectators.Address())
+	require.NoError(t, err)
+	defer other.Close()
+	readUntil(t, other, fullMessage)
+	require.Equal(t, 1, aSpectators.Count())
+
+	// The spectators who leave are counted
+	conn.Close()
+	waitFor(t, func() bool { return aSpectators.Count() == 0 })
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, []int{1, 0}, counts)
+}
+
+func TestSlowSpectator(t *testing.T) {
+	var rounds int64
+
+	// Every frame is different and large
+	frame := func() []string {
+		round := atomic.AddInt64(&rounds, 1)
+		return []string{strings.Repeat("x", 4096), strings.Repeat("y", int(round%100))}
+	}
+
+	aSpectators := New(DefaultMaxSpectators, nil)
+	require.NoError(t, aSpectators.Start("127.0.0.1:0", frame, time.Millisecond))
+	defer aSpectators.Close()
+
+	// The spectator never reads
+	conn, err := net.Dial("tcp", aSpectators.Address())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The frames keep being computed, they aren't queued for the spectator
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, aSpectators.Close())
+	require.Greater(t, atomic.LoadInt64(&rounds), int64(10))
+}
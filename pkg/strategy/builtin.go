@@ -0,0 +1,73 @@
+package strategy
+
+import (
+	"gosnake/pkg/common"
+	"math/rand"
+)
+
+// random moves to any cell which doesn't kill the snake, it is the baseline of the tournaments
+type random struct {
+	rnd *rand.Rand
+}
+
+func newRandom(seed int64) Strategyer {
+	return &random{rnd: rand.New(rand.NewSource(seed))}
+}
+
+func (aRandom *random) Name() string {
+	return "random"
+}
+
+// NextMove draws a safe direction, the snake goes straight when there is none
+func (aRandom *random) NextMove(state State) common.Direction {
+	if len(state.Body) == 0 {
+		return state.Direction
+	}
+
+	var safe []common.Direction
+
+	for _, direction := range Directions {
+		if state.Safe(direction) {
+			safe = append(safe, direction)
+		}
+	}
+
+	if len(safe) == 0 {
+		return state.Direction
+	}
+
+	return safe[aRandom.rnd.Intn(len(safe))]
+}
+
+// greedy takes the shortest way to the candy, it only avoids the snake one move ahead
+type greedy struct{}
+
+func newGreedy(int64) Strategyer {
+	return &greedy{}
+}
+
+func (aGreedy *greedy) Name() string {
+	return "greedy"
+}
+
+// NextMove takes the safe direction closest to the candy, the current direction wins the ties
+func (aGreedy *greedy) NextMove(state State) common.Direction {
+	if len(state.Body) == 0 {
+		return state.Direction
+	}
+
+	best, bestDistance := state.Direction, -1
+
+	for _, direction := range append([]common.Direction{state.Direction}, Directions...) {
+		if !state.Safe(direction) {
+			continue
+		}
+
+		distance := state.Distance(state.Next(state.Head(), direction), state.Candy)
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = direction, distance
+		}
+	}
+
+	return best
+}
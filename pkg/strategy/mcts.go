@@ -0,0 +1,247 @@
+package strategy
+
+import (
+	"errors"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/gamestate"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// MCTSSettings bounds the Monte Carlo tree search of each move
+type MCTSSettings struct {
+	Rollouts    int           // Rollouts per move, the budget alone bounds the search when 0
+	Budget      time.Duration // Time per move, the rollouts alone bound the search when 0
+	Depth       int           // Random moves played after the moves of the tree
+	Exploration float64       // Weight of the moves less tried in UCB1
+	Seed        int64         // Draws the seeds of the rollouts, the same seed gives the same moves when there is no budget
+}
+
+// DefaultMCTSSettings searches without a time budget, the games stay reproducible
+func DefaultMCTSSettings() MCTSSettings {
+	return MCTSSettings{
+		Rollouts:    32,
+		Depth:       10,
+		Exploration: math.Sqrt2,
+		Seed:        1,
+	}
+}
+
+// mctsDeath is the reward of a rollout where the snake dies, a candy is worth 1
+const mctsDeath = -1.0
+
+// mcts plays the move of the Monte Carlo tree search, from copies of the game
+type mcts struct {
+	settings MCTSSettings
+	rnd      *rand.Rand // Draws the seed of the search of each move
+}
+
+func newMCTS(seed int64) Strategyer {
+	settings := DefaultMCTSSettings()
+	settings.Seed = seed
+
+	return NewMCTS(settings)
+}
+
+// NewMCTS returns an instance of mcts, the settings left to 0 take their default value
+func NewMCTS(settings MCTSSettings) Strategyer {
+	return &mcts{settings: settings.normalize(), rnd: rand.New(rand.NewSource(settings.Seed))}
+}
+
+func (settings MCTSSettings) normalize() MCTSSettings {
+	defaults := DefaultMCTSSettings()
+
+	if settings.Rollouts <= 0 && settings.Budget <= 0 {
+		settings.Rollouts = defaults.Rollouts
+	}
+
+	if settings.Depth <= 0 {
+		settings.Depth = defaults.Depth
+	}
+
+	if settings.Exploration <= 0 {
+		settings.Exploration = defaults.Exploration
+	}
+
+	return settings
+}
+
+func (aMCTS *mcts) Name() string {
+	return "mcts"
+}
+
+// NextMove searches from a game rebuilt from the state, the snake goes straight if it can't be rebuilt
+func (aMCTS *mcts) NextMove(state State) common.Direction {
+	if len(state.Body) == 0 {
+		return state.Direction
+	}
+
+	gameState := gamestate.New()
+	if err := gameState.Restore(common.GameCheckpoint{Board: common.BoardCheckpoint{
+		Size:           state.Size,
+		Board:          state.Cells,
+		SnakeBody:      state.Body,
+		SnakeDirection: state.Direction,
+		CandyPosition:  state.Candy,
+		CandyAlive:     state.CandyAlive,
+	}}); err != nil {
+		return state.Direction
+	}
+
+	gameState.Resume()
+
+	settings := aMCTS.settings
+	settings.Seed = aMCTS.rnd.Int63()
+
+	direction, err := SearchMCTS(gameState, settings)
+	if err != nil {
+		return state.Direction
+	}
+
+	return direction
+}
+
+// mctsNode holds the outcome of the rollouts through a move, its children are the moves of the next round
+type mctsNode struct {
+	visits   int
+	value    float64 // Sum of the rewards of the rollouts
+	children [4]*mctsNode
+}
+
+// SearchMCTS plays rollouts on copies of gameState and returns the direction tried the most
+// Each rollout draws its candies and its random moves from its own seed, gameState isn't changed
+func SearchMCTS(gameState gamestate.GameStater, settings MCTSSettings) (direction common.Direction, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	cloner, ok := gameState.(gamestate.Cloner)
+	if !ok {
+		return direction, gamestate.ErrNotClonable
+	}
+
+	checkpoint, err := gameState.Checkpoint()
+	if err != nil {
+		return direction, err
+	}
+
+	settings = settings.normalize()
+
+	var (
+		root     = &mctsNode{}
+		rnd      = rand.New(rand.NewSource(settings.Seed))
+		deadline = time.Now().Add(settings.Budget)
+	)
+
+	for rollout := 0; settings.Rollouts <= 0 || rollout < settings.Rollouts; rollout++ {
+		if settings.Budget > 0 && rollout > 0 && time.Now().After(deadline) {
+			break
+		}
+
+		aClone, err := cloner.Clone(rnd.Int63())
+		if err != nil {
+			return direction, err
+		}
+
+		if err := root.rollout(aClone, checkpoint.Board.SnakeDirection, settings, rnd); err != nil {
+			return direction, err
+		}
+	}
+
+	direction = checkpoint.Board.SnakeDirection
+	visits := 0
+
+	for action, child := range root.children {
+		if child != nil && child.visits > visits {
+			direction, visits = Directions[action], child.visits
+		}
+	}
+
+	return direction, nil
+}
+
+// rollout plays the moves of the tree down to a move never tried, then random moves
+// The reward is the candies eaten, less a candy when the snake dies
+func (node *mctsNode) rollout(gameState gamestate.GameStater, direction common.Direction, settings MCTSSettings,
+	rnd *rand.Rand) (err error) {
+	path := []*mctsNode{node}
+	score := gameState.Score()
+	over := false
+
+	// play moves a round in direction, the game is over once the snake died or filled the board
+	play := func(action int) error {
+		direction = Directions[action]
+		Steer(gameState, direction)
+
+		_, err := gameState.Play()
+		if errors.Is(err, gameboard.ErrBoardFull) {
+			over = true
+			return nil
+		}
+
+		over = over || !gameState.GameInProgress()
+
+		return err
+	}
+
+	for current := node; !over; {
+		action, expanded := current.next(settings.Exploration)
+		if expanded {
+			current.children[action] = &mctsNode{}
+		}
+
+		if err := play(action); err != nil {
+			return err
+		}
+
+		current = current.children[action]
+		path = append(path, current)
+
+		if expanded {
+			break
+		}
+	}
+
+	// The random moves don't go back, the snake would bite its neck
+	for depth := 0; depth < settings.Depth && !over; depth++ {
+		action := rnd.Intn(len(Directions))
+		if Directions[action].DX == -direction.DX && Directions[action].DY == -direction.DY {
+			action = (action + 1 + rnd.Intn(len(Directions)-1)) % len(Directions)
+		}
+
+		if err := play(action); err != nil {
+			return err
+		}
+	}
+
+	reward := float64(gameState.Score() - score)
+	if !gameState.GameInProgress() {
+		reward += mctsDeath
+	}
+
+	for _, visited := range path {
+		visited.visits++
+		visited.value += reward
+	}
+
+	return nil
+}
+
+// next returns the first move never tried, or the move of greatest UCB1 once they all were
+func (node *mctsNode) next(exploration float64) (action int, expanded bool) {
+	best := math.Inf(-1)
+
+	for i, child := range node.children {
+		if child == nil {
+			return i, true
+		}
+
+		value := child.value/float64(child.visits) +
+			exploration*math.Sqrt(math.Log(float64(node.visits))/float64(child.visits))
+		if value > best {
+			action, best = i, value
+		}
+	}
+
+	return action, false
+}
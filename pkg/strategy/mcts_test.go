@@ -0,0 +1,62 @@
+package strategy_test
+
+import (
+	"gosnake/mocks"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/strategy/strategytest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCTS_NextMove(t *testing.T) {
+	aStrategy, err := strategy.New("mcts", 1)
+	require.NoError(t, err)
+	require.Equal(t, "mcts", aStrategy.Name())
+
+	// The candy next to the head is eaten, the body is avoided
+	nextToCandy := strategytest.New(common.Size{Width: 5, Height: 5},
+		[]common.Position{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}, strategy.Down, &common.Position{X: 3, Y: 2})
+	require.Equal(t, strategy.Right, aStrategy.NextMove(nextToCandy))
+	require.NotEqual(t, strategy.Up, aStrategy.NextMove(testSnake))
+
+	// The same seed plays the same moves
+	again := strategy.NewMCTS(strategy.MCTSSettings{Seed: 1})
+	first, err := strategy.New("mcts", 1)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, first.NextMove(testSnake), again.NextMove(testSnake))
+	}
+
+	require.Equal(t, strategy.Left, first.NextMove(strategy.State{Direction: strategy.Left}))
+}
+
+func TestSearchMCTS(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	gameState.SetSeed(3)
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+	gameState.Start()
+
+	want, err := gameState.Checkpoint()
+	require.NoError(t, err)
+
+	// The search within a budget plays on copies, the game is left as it was
+	started := time.Now()
+	direction, err := strategy.SearchMCTS(gameState, strategy.MCTSSettings{Budget: 20 * time.Millisecond})
+	require.NoError(t, err)
+	require.Less(t, time.Since(started), time.Second)
+	require.Contains(t, strategy.Directions, direction)
+
+	got, err := gameState.Checkpoint()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Empty(t, gameState.History().Rounds)
+
+	_, err = strategy.SearchMCTS(&mocks.GameStater{}, strategy.DefaultMCTSSettings())
+	require.ErrorIs(t, err, gamestate.ErrNotClonable)
+}
@@ -0,0 +1,173 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/grid"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Directions the snake can take, the moves of the grid package
+var (
+	Up    = grid.Up
+	Down  = grid.Down
+	Left  = grid.Left
+	Right = grid.Right
+
+	Directions = grid.Directions
+)
+
+// ErrUnknownStrategy is returned when there is no strategy with the name requested
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// Strategyer is the interface for the strategies playing in place of the keyboard
+type Strategyer interface {
+	Name() string
+	NextMove(state State) (direction common.Direction)
+}
+
+// State is what a strategy knows of the game before a round, a copy it can't change the game with
+type State struct {
+	Size       common.Size
+	Cells      [][]rune          // Cells[x][y], as the board
+	Body       []common.Position // From the tail to the head
+	Direction  common.Direction
+	Candy      common.Position
+	CandyAlive bool
+}
+
+// registry creates the strategies by name, the seed is drawn by the ones playing random moves
+var (
+	registry = map[string]func(seed int64) Strategyer{
+		"random": newRandom,
+		"greedy": newGreedy,
+		"mcts":   newMCTS,
+	}
+	registryMutex sync.RWMutex
+)
+
+// Register adds a strategy, or replaces the one with the same name
+func Register(name string, create func(seed int64) Strategyer) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[name] = create
+}
+
+// Names returns the names of the strategies registered, sorted
+func Names() (names []string) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	for name := range registry {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// New returns the strategy registered with name
+func New(name string, seed int64) (aStrategy Strategyer, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	registryMutex.RLock()
+	create, ok := registry[name]
+	registryMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, expected %s", ErrUnknownStrategy, name, strings.Join(Names(), ", "))
+	}
+
+	return create(seed), nil
+}
+
+// ReadState copies the state of a game for a strategy
+func ReadState(gameState gamestate.GameStater) (state State, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	checkpoint, err := gameState.Checkpoint()
+	if err != nil {
+		return state, err
+	}
+
+	return State{
+		Size:       checkpoint.Board.Size,
+		Cells:      checkpoint.Board.Board,
+		Body:       checkpoint.Board.SnakeBody,
+		Direction:  checkpoint.Board.SnakeDirection,
+		Candy:      checkpoint.Board.CandyPosition,
+		CandyAlive: checkpoint.Board.CandyAlive,
+	}, nil
+}
+
+// Steer turns the snake as the keys do, the other directions are ignored
+func Steer(gameState gamestate.GameStater, direction common.Direction) {
+	switch direction {
+	case Up:
+		gameState.MoveUp()
+	case Down:
+		gameState.MoveDown()
+	case Left:
+		gameState.MoveLeft()
+	case Right:
+		gameState.MoveRight()
+	}
+}
+
+// Head returns the position of the head of the snake
+func (state State) Head() common.Position {
+	return state.Body[len(state.Body)-1]
+}
+
+// stateGrid is the read-only view of the cells of a state
+type stateGrid struct {
+	state State
+}
+
+// Grid returns a view of the cells of the state for the algorithms of the grid package, the sides of the board wrap
+func (state State) Grid() grid.Grider {
+	return stateGrid{state: state}
+}
+
+func (view stateGrid) Size() common.Size {
+	return view.state.Size
+}
+
+// Cell returns the value of a cell, 0 out of the board
+func (view stateGrid) Cell(position common.Position) rune {
+	if !grid.Inside(view, position) {
+		return 0
+	}
+
+	return view.state.Cells[position.X][position.Y]
+}
+
+func (view stateGrid) Boundary() grid.Boundary {
+	return grid.BoundaryWrap
+}
+
+// Next returns the cell reached from position in direction, the snake leaving a side enters the other side
+func (state State) Next(position common.Position, direction common.Direction) common.Position {
+	next, _ := grid.Step(state.Grid(), position, direction)
+
+	return next
+}
+
+// Safe tells if the snake survives a move in direction, the tail moves away and leaves its cell free
+func (state State) Safe(direction common.Direction) bool {
+	next := state.Next(state.Head(), direction)
+
+	return state.Cells[next.X][next.Y] != gameboard.SnakePart || next == state.Body[0]
+}
+
+// Distance returns the number of moves between two cells, through the sides of the board
+func (state State) Distance(from, to common.Position) int {
+	return grid.Distance(state.Grid(), from, to)
+}
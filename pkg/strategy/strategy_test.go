@@ -0,0 +1,117 @@
+package strategy_test
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"gosnake/pkg/strategy/strategytest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A snake of 4 cells turning around (1,1), its head is at (2,2)
+var testSnake = strategytest.New(common.Size{Width: 5, Height: 5},
+	[]common.Position{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}}, strategy.Down, &common.Position{X: 4, Y: 3})
+
+func TestState(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction common.Direction
+		wantNext  common.Position
+		wantSafe  bool
+	}{
+		{
+			name:      "TestFree",
+			direction: strategy.Down,
+			wantNext:  common.Position{X: 2, Y: 3},
+			wantSafe:  true,
+		},
+		{
+			name:      "TestBody",
+			direction: strategy.Up,
+			wantNext:  common.Position{X: 2, Y: 1},
+			wantSafe:  false,
+		},
+		{
+			name:      "TestTail", // The tail leaves its cell
+			direction: strategy.Left,
+			wantNext:  common.Position{X: 1, Y: 2},
+			wantSafe:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantNext, testSnake.Next(testSnake.Head(), tt.direction))
+			require.Equal(t, tt.wantSafe, testSnake.Safe(tt.direction))
+		})
+	}
+
+	// The sides of the board are crossed
+	require.Equal(t, common.Position{X: 4, Y: 0}, testSnake.Next(common.Position{X: 0, Y: 0}, strategy.Left))
+	require.Equal(t, common.Position{X: 0, Y: 4}, testSnake.Next(common.Position{X: 0, Y: 0}, strategy.Up))
+	require.Equal(t, 2, testSnake.Distance(common.Position{X: 0, Y: 0}, common.Position{X: 4, Y: 4}))
+	require.Equal(t, 3, testSnake.Distance(common.Position{X: 2, Y: 2}, common.Position{X: 4, Y: 3}))
+
+	// The grid view reads the cells of the state
+	require.Equal(t, testSnake.Size, testSnake.Grid().Size())
+	require.Equal(t, gameboard.SnakePart, testSnake.Grid().Cell(testSnake.Head()))
+	require.Equal(t, rune(0), testSnake.Grid().Cell(common.Position{X: 5, Y: 0}))
+}
+
+func TestNew(t *testing.T) {
+	require.Subset(t, strategy.Names(), []string{"greedy", "mcts", "random"})
+
+	aStrategy, err := strategy.New("greedy", 1)
+	require.NoError(t, err)
+	require.Equal(t, "greedy", aStrategy.Name())
+
+	_, err = strategy.New("psychic", 1)
+	require.ErrorIs(t, err, strategy.ErrUnknownStrategy)
+
+	strategy.Register("straight", func(int64) strategy.Strategyer { return strategy.NewMCTS(strategy.MCTSSettings{}) })
+	require.Contains(t, strategy.Names(), "straight")
+}
+
+func TestBuiltins(t *testing.T) {
+	// The greedy strategy heads to the candy
+	greedyStrategy, err := strategy.New("greedy", 1)
+	require.NoError(t, err)
+	require.Equal(t, strategy.Down, greedyStrategy.NextMove(testSnake))
+
+	// The random strategy never takes a deadly direction
+	randomStrategy, err := strategy.New("random", 1)
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		require.NotEqual(t, strategy.Up, randomStrategy.NextMove(testSnake))
+	}
+
+	// A trapped snake goes straight, it fills the board around its head
+	trapped := strategytest.New(common.Size{Width: 3, Height: 3}, []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2},
+		{X: 1, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 1}, {X: 1, Y: 1}}, strategy.Left, nil)
+	require.Equal(t, strategy.Left, greedyStrategy.NextMove(trapped))
+	require.Equal(t, strategy.Left, randomStrategy.NextMove(trapped))
+}
+
+func TestReadStateSteer(t *testing.T) {
+	gameState := gamestate.New()
+	require.NoError(t, gameState.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := gameState.CreateObjects()
+	require.NoError(t, err)
+
+	state, err := strategy.ReadState(gameState)
+	require.NoError(t, err)
+	require.Equal(t, common.Position{X: 5, Y: 5}, state.Head())
+	require.Equal(t, strategy.Right, state.Direction)
+	require.True(t, state.CandyAlive)
+
+	// The state is a copy
+	state.Cells[0][0] = gameboard.SnakePart
+	require.NotContains(t, gameState.Sprites(), common.Sprite{Value: gameboard.SnakePart})
+
+	strategy.Steer(gameState, strategy.Up)
+	state, err = strategy.ReadState(gameState)
+	require.NoError(t, err)
+	require.Equal(t, strategy.Up, state.Direction)
+}
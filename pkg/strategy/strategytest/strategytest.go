@@ -0,0 +1,33 @@
+package strategytest
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/strategy"
+)
+
+// New returns a free board of size with the snake of body, from the tail to the head, and the candy at candy
+// unless it is nil
+func New(size common.Size, body []common.Position, direction common.Direction,
+	candy *common.Position) strategy.State {
+	state := strategy.State{Size: size, Body: body, Direction: direction}
+	state.Cells = make([][]rune, size.Width)
+
+	for x := range state.Cells {
+		state.Cells[x] = make([]rune, size.Height)
+		for y := range state.Cells[x] {
+			state.Cells[x][y] = gameboard.FreeSpace
+		}
+	}
+
+	for _, position := range body {
+		state.Cells[position.X][position.Y] = gameboard.SnakePart
+	}
+
+	if candy != nil {
+		state.Candy, state.CandyAlive = *candy, true
+		state.Cells[candy.X][candy.Y] = gameboard.CandyBody
+	}
+
+	return state
+}
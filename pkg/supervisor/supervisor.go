@@ -0,0 +1,147 @@
+package supervisor
+
+import (
+	"errors"
+	"gosnake/pkg/common"
+	"sync"
+)
+
+// DefaultRetries is the number of times a game can be resumed after a crash
+const DefaultRetries = 3
+
+// Defines custom errors
+var (
+	ErrNoCheckpoint     = errors.New("no checkpoint to resume from")
+	ErrRetriesExhausted = errors.New("the retry budget is exhausted")
+)
+
+// Checkpointer is implemented by the game states which can be saved and restored
+type Checkpointer interface {
+	Checkpoint() (checkpoint common.GameCheckpoint, err error)
+	Restore(checkpoint common.GameCheckpoint) (err error)
+}
+
+// Supervisorer is the interface for supervisor
+type Supervisorer interface {
+	Reset()
+	Checkpoint(gameState Checkpointer) (err error)
+	Fail(errMsg error) (canResume bool)
+	Failed() bool
+	CanResume() bool
+	RetriesLeft() int
+	Resume(gameState Checkpointer) (err error)
+}
+
+// supervisor keeps the last good state of the game engine and a retry budget
+// Checkpoints and failures come from the engine while the keys resume, hence the mutex
+type supervisor struct {
+	mutex         sync.Mutex
+	maxRetries    int
+	retries       int
+	checkpoint    common.GameCheckpoint
+	hasCheckpoint bool
+	failed        bool
+}
+
+// New returns an instance of supervisor allowing maxRetries resumes per game
+func New(maxRetries int) Supervisorer {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &supervisor{
+		maxRetries: maxRetries,
+	}
+}
+
+// Reset is called when a new game starts, the budget is restored
+func (aSupervisor *supervisor) Reset() {
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	aSupervisor.retries = 0
+	aSupervisor.hasCheckpoint = false
+	aSupervisor.failed = false
+}
+
+// Checkpoint saves the state after a round played without error
+func (aSupervisor *supervisor) Checkpoint(gameState Checkpointer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	checkpoint, err := gameState.Checkpoint()
+	if err != nil {
+		return err
+	}
+
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	aSupervisor.checkpoint = checkpoint
+	aSupervisor.hasCheckpoint = true
+
+	return nil
+}
+
+// Fail records a crash of the engine and tells if the game can be resumed
+func (aSupervisor *supervisor) Fail(errMsg error) (canResume bool) {
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	aSupervisor.failed = errMsg != nil
+
+	return aSupervisor.canResume()
+}
+
+// Failed tells if the last game crashed and was neither resumed nor restarted
+func (aSupervisor *supervisor) Failed() bool {
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	return aSupervisor.failed
+}
+
+// CanResume tells if the crashed game can be resumed
+func (aSupervisor *supervisor) CanResume() bool {
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	return aSupervisor.canResume()
+}
+
+func (aSupervisor *supervisor) canResume() bool {
+	return aSupervisor.failed && aSupervisor.hasCheckpoint &&
+		aSupervisor.retries < aSupervisor.maxRetries
+}
+
+// RetriesLeft returns the number of resumes still allowed
+func (aSupervisor *supervisor) RetriesLeft() int {
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	return aSupervisor.maxRetries - aSupervisor.retries
+}
+
+// Resume restores the last checkpoint into gameState and uses a retry
+func (aSupervisor *supervisor) Resume(gameState Checkpointer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSupervisor.mutex.Lock()
+	defer aSupervisor.mutex.Unlock()
+
+	if !aSupervisor.hasCheckpoint {
+		return ErrNoCheckpoint
+	}
+
+	if aSupervisor.retries >= aSupervisor.maxRetries {
+		return ErrRetriesExhausted
+	}
+
+	if err := gameState.Restore(aSupervisor.checkpoint); err != nil {
+		return err
+	}
+
+	aSupervisor.retries++
+	aSupervisor.failed = false
+
+	return nil
+}
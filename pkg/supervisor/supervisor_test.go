@@ -0,0 +1,150 @@
+package supervisor
+
+import (
+	"errors"
+	"gosnake/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errCrash = errors.New("crash")
+
+// gameState is a Checkpointer, mocks.Checkpointer can't be used since mocks imports this package
+type gameState struct {
+	checkpoint    common.GameCheckpoint
+	checkpointErr error
+	restoreErr    error
+	restored      []common.GameCheckpoint
+}
+
+func (aGameState *gameState) Checkpoint() (common.GameCheckpoint, error) {
+	return aGameState.checkpoint, aGameState.checkpointErr
+}
+
+func (aGameState *gameState) Restore(checkpoint common.GameCheckpoint) error {
+	if aGameState.restoreErr != nil {
+		return aGameState.restoreErr
+	}
+	aGameState.restored = append(aGameState.restored, checkpoint)
+	return nil
+}
+
+func TestSupervisor_Resume(t *testing.T) {
+	checkpoint := common.GameCheckpoint{
+		Round: 12,
+		Score: 3,
+	}
+	tests := []struct {
+		name          string
+		maxRetries    int
+		checkpoint    bool
+		failures      int
+		restoreErr    error
+		wantCanResume bool
+		wantErrType   error
+	}{
+		{
+			name:        "TestNoCheckpoint",
+			maxRetries:  1,
+			failures:    1,
+			wantErrType: ErrNoCheckpoint,
+		},
+		{
+			name:        "TestNoRetry",
+			checkpoint:  true,
+			failures:    1,
+			wantErrType: ErrRetriesExhausted,
+		},
+		{
+			name:          "TestResumed",
+			maxRetries:    2,
+			checkpoint:    true,
+			failures:      2,
+			wantCanResume: true,
+		},
+		{
+			name:        "TestExhausted",
+			maxRetries:  2,
+			checkpoint:  true,
+			failures:    3,
+			wantErrType: ErrRetriesExhausted,
+		},
+		{
+			name:          "TestRestoreFailed",
+			maxRetries:    1,
+			checkpoint:    true,
+			failures:      1,
+			restoreErr:    errCrash,
+			wantCanResume: true,
+			wantErrType:   errCrash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := &gameState{
+				checkpoint: checkpoint,
+			}
+
+			aSupervisor := New(tt.maxRetries)
+			if tt.checkpoint {
+				require.NoError(t, aSupervisor.Checkpoint(aGameState))
+			}
+
+			// Every failure but the last one is resumed
+			for i := 1; i < tt.failures; i++ {
+				require.True(t, aSupervisor.Fail(errCrash))
+				require.NoError(t, aSupervisor.Resume(aGameState))
+				require.False(t, aSupervisor.Failed())
+			}
+			aGameState.restoreErr = tt.restoreErr
+
+			require.Equal(t, tt.wantCanResume, aSupervisor.Fail(errCrash))
+			require.True(t, aSupervisor.Failed())
+			require.Equal(t, tt.wantCanResume, aSupervisor.CanResume())
+
+			err := aSupervisor.Resume(aGameState)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				require.True(t, aSupervisor.Failed())
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, aSupervisor.Failed())
+			require.Equal(t, tt.maxRetries-tt.failures, aSupervisor.RetriesLeft())
+			require.Len(t, aGameState.restored, tt.failures)
+			require.Equal(t, checkpoint, aGameState.restored[0])
+		})
+	}
+}
+
+func TestSupervisor_Checkpoint(t *testing.T) {
+	aGameState := &gameState{
+		checkpointErr: errCrash,
+	}
+
+	aSupervisor := New(1)
+	require.ErrorIs(t, aSupervisor.Checkpoint(aGameState), errCrash)
+	require.False(t, aSupervisor.Fail(errCrash))
+}
+
+func TestSupervisor_Reset(t *testing.T) {
+	aGameState := &gameState{}
+
+	aSupervisor := New(1)
+	require.NoError(t, aSupervisor.Checkpoint(aGameState))
+	require.True(t, aSupervisor.Fail(errCrash))
+	require.NoError(t, aSupervisor.Resume(aGameState))
+	require.Equal(t, 0, aSupervisor.RetriesLeft())
+
+	// A new game gets the whole budget but no checkpoint
+	aSupervisor.Reset()
+	require.Equal(t, 1, aSupervisor.RetriesLeft())
+	require.False(t, aSupervisor.Failed())
+	require.False(t, aSupervisor.Fail(errCrash))
+}
+
+func TestNew(t *testing.T) {
+	require.Equal(t, 0, New(-1).RetriesLeft())
+	require.Equal(t, DefaultRetries, New(DefaultRetries).RetriesLeft())
+}
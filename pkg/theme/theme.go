@@ -0,0 +1,163 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"image/color"
+	"strings"
+)
+
+// DefaultTheme is the name of the theme used when none is configured
+const DefaultTheme = "classic"
+
+// ErrUnknownTheme is returned when there is no theme with the name requested
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Theme holds the style of each element of the game
+type Theme struct {
+	Name  string
+	Snake common.Style
+	Candy common.Style
+	Board common.Style // The free cells and the background of the board
+	Panel common.Style // The frames and the text of the panels
+	Alert common.Style // The error panel
+	Trap  common.Style // The free cells of the region the snake is trapped in
+}
+
+// themes are listed in the order they are cycled
+var themes = []Theme{
+	{
+		Name:  "classic",
+		Snake: common.Style{Fg: common.ColorGreen, Bold: true},
+		Candy: common.Style{Fg: common.ColorRed, Bold: true},
+		Alert: common.Style{Bg: common.ColorRed},
+		Trap:  common.Style{Bg: common.ColorRed},
+	},
+	{
+		Name:  "high-contrast",
+		Snake: common.Style{Fg: common.Color256(15), Bg: common.Color256(0), Bold: true},
+		Candy: common.Style{Fg: common.Color256(226), Bg: common.Color256(0), Bold: true},
+		Board: common.Style{Fg: common.Color256(15), Bg: common.Color256(0)},
+		Panel: common.Style{Fg: common.Color256(15), Bg: common.Color256(0), Bold: true},
+		Alert: common.Style{Fg: common.Color256(15), Bg: common.Color256(160), Bold: true},
+		Trap:  common.Style{Bg: common.Color256(160)},
+	},
+	{
+		// Okabe-Ito colors, they stay distinct with every kind of color blindness
+		Name:  "color-blind-safe",
+		Snake: common.Style{Fg: common.Color256(32), Bold: true},  // Blue
+		Candy: common.Style{Fg: common.Color256(214), Bold: true}, // Orange
+		Panel: common.Style{Fg: common.Color256(74)},              // Sky blue
+		Alert: common.Style{Fg: common.Color256(0), Bg: common.Color256(220)},
+		Trap:  common.Style{Bg: common.Color256(166)}, // Vermillion
+	},
+	{
+		Name:  "monochrome",
+		Snake: common.Style{Bold: true},
+		Candy: common.Style{Bold: true},
+		Alert: common.Style{Fg: common.ColorBlack, Bg: common.ColorWhite},
+		Trap:  common.Style{Bg: common.ColorWhite},
+	},
+}
+
+// Names returns the names of the themes
+func Names() (names []string) {
+	for i := range themes {
+		names = append(names, themes[i].Name)
+	}
+
+	return names
+}
+
+// Get returns the theme named name
+func Get(name string) (theme Theme, err error) {
+	for i := range themes {
+		if themes[i].Name == strings.ToLower(name) {
+			return themes[i], nil
+		}
+	}
+
+	return theme, fmt.Errorf("%w: %q (expected one of %s)", ErrUnknownTheme, name,
+		strings.Join(Names(), ", "))
+}
+
+// Next returns the name of the theme following the theme named name, the first one after the last one
+func Next(name string) string {
+	for i := range themes {
+		if themes[i].Name == strings.ToLower(name) {
+			return themes[(i+1)%len(themes)].Name
+		}
+	}
+
+	return themes[0].Name
+}
+
+// Cells returns the style of each value of the board cells
+func (theme Theme) Cells() map[rune]common.Style {
+	return map[rune]common.Style{
+		gameboard.FreeSpace: theme.Board,
+		gameboard.SnakePart: theme.Snake,
+		gameboard.CandyBody: theme.Candy,
+	}
+}
+
+// basicColors are the 16 first colors of the palette, as xterm displays them
+var basicColors = []color.RGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x00, 0x00, 0xff}, {0x00, 0xcd, 0x00, 0xff}, {0xcd, 0xcd, 0x00, 0xff},
+	{0x00, 0x00, 0xee, 0xff}, {0xcd, 0x00, 0xcd, 0xff}, {0x00, 0xcd, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff}, {0xff, 0x00, 0x00, 0xff}, {0x00, 0xff, 0x00, 0xff}, {0xff, 0xff, 0x00, 0xff},
+	{0x5c, 0x5c, 0xff, 0xff}, {0xff, 0x00, 0xff, 0xff}, {0x00, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+// cubeLevels are the intensities of the 6x6x6 color cube of the palette
+var cubeLevels = []uint8{0, 95, 135, 175, 215, 255}
+
+// RGB returns the color displayed for a terminal color, outside of the terminal
+// The default color has none, ok is false
+func RGB(aColor common.Color) (rgb color.RGBA, ok bool) {
+	if aColor <= common.ColorDefault || aColor > common.Color256(255) {
+		return rgb, false
+	}
+
+	index := int(aColor) - 1
+
+	switch {
+	case index < len(basicColors):
+		return basicColors[index], true
+	case index < 232:
+		index -= 16
+		return color.RGBA{cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6], 0xff}, true
+	}
+
+	grey := uint8(8 + (index-232)*10)
+
+	return color.RGBA{grey, grey, grey, 0xff}, true
+}
+
+// defaultPalette holds the colors of the images for the cells a theme leaves to the terminal
+var defaultPalette = gameboard.Palette{
+	Background: basicColors[0],
+	Snake:      basicColors[2],
+	Candy:      basicColors[1],
+}
+
+// Palette returns the colors of the cells in the images of the board
+func (theme Theme) Palette() gameboard.Palette {
+	palette := defaultPalette
+
+	if rgb, ok := RGB(theme.Board.Bg); ok {
+		palette.Background = rgb
+	}
+
+	if rgb, ok := RGB(theme.Snake.Fg); ok {
+		palette.Snake = rgb
+	}
+
+	if rgb, ok := RGB(theme.Candy.Fg); ok {
+		palette.Candy = rgb
+	}
+
+	return palette
+}
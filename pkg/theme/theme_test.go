@@ -0,0 +1,129 @@
+package theme
+
+import (
+	"gosnake/pkg/common"
+	"gosnake/pkg/gameboard"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		themeName   string
+		wantErrType error
+	}{
+		{
+			name:      "TestDefault",
+			themeName: DefaultTheme,
+		},
+		{
+			name:      "TestCaseInsensitive",
+			themeName: "High-Contrast",
+		},
+		{
+			name:        "TestUnknown",
+			themeName:   "neon",
+			wantErrType: ErrUnknownTheme,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, err := Get(tt.themeName)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			require.NotZero(t, theme.Alert)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	names := Names()
+	require.Equal(t, DefaultTheme, names[0])
+
+	// Every theme is visited once before coming back to the first one
+	name := names[0]
+	for i := 1; i < len(names); i++ {
+		name = Next(name)
+		require.Equal(t, names[i], name)
+	}
+	require.Equal(t, names[0], Next(name))
+	require.Equal(t, names[0], Next("neon"))
+}
+
+func TestCells(t *testing.T) {
+	theme, err := Get("color-blind-safe")
+	require.NoError(t, err)
+	cells := theme.Cells()
+	require.Equal(t, theme.Snake, cells[gameboard.SnakePart])
+	require.Equal(t, theme.Candy, cells[gameboard.CandyBody])
+	require.NotEqual(t, cells[gameboard.SnakePart], cells[gameboard.CandyBody])
+}
+
+func TestTrap(t *testing.T) {
+	// The shading of a trap must show on the free cells of every theme
+	for _, name := range Names() {
+		theme, err := Get(name)
+		require.NoError(t, err)
+		require.NotEqual(t, theme.Board.Bg, theme.Trap.Bg, name)
+	}
+}
+
+func TestRGB(t *testing.T) {
+	tests := []struct {
+		name   string
+		color  common.Color
+		want   color.RGBA
+		wantOk bool
+	}{
+		{
+			name:  "TestDefault",
+			color: common.ColorDefault,
+		},
+		{
+			name:   "TestBasic",
+			color:  common.ColorGreen,
+			want:   color.RGBA{0x00, 0xcd, 0x00, 0xff},
+			wantOk: true,
+		},
+		{
+			name:   "TestCube", // Orange
+			color:  common.Color256(214),
+			want:   color.RGBA{0xff, 0xaf, 0x00, 0xff},
+			wantOk: true,
+		},
+		{
+			name:   "TestGrey",
+			color:  common.Color256(244),
+			want:   color.RGBA{0x80, 0x80, 0x80, 0xff},
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rgb, ok := RGB(tt.color)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, rgb)
+		})
+	}
+}
+
+func TestTheme_Palette(t *testing.T) {
+	// The classic theme leaves the board to the terminal
+	classic, err := Get("classic")
+	require.NoError(t, err)
+	palette := classic.Palette()
+	require.Equal(t, defaultPalette.Background, palette.Background)
+	require.Equal(t, color.RGBA{0x00, 0xcd, 0x00, 0xff}, palette.Snake)
+
+	highContrast, err := Get("high-contrast")
+	require.NoError(t, err)
+	palette = highContrast.Palette()
+	require.Equal(t, color.RGBA{0xff, 0xff, 0x00, 0xff}, palette.Candy)
+	require.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, palette.Snake)
+}
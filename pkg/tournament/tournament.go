@@ -0,0 +1,354 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/strategy"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+)
+
+// z95 is the quantile of the normal distribution giving the 95% confidence intervals
+const z95 = 1.96
+
+// ErrInvalidSettings is returned when a tournament can't be played with the settings
+var ErrInvalidSettings = errors.New("invalid tournament settings")
+
+// Cause tells how a game ended
+type Cause int
+
+// Causes of the end of the games
+const (
+	CauseCollision  Cause = iota // The snake ran into itself
+	CauseRoundLimit              // Still alive after the last round allowed
+	CauseBoardFull               // The snake fills the board, the game is won
+	CauseError                   // The game engine or the strategy failed
+	causeCount
+)
+
+var causeNames = []string{
+	CauseCollision:  "collision",
+	CauseRoundLimit: "round limit",
+	CauseBoardFull:  "board full",
+	CauseError:      "error",
+}
+
+func (cause Cause) String() string {
+	if cause < 0 || cause >= causeCount {
+		return "unknown"
+	}
+
+	return causeNames[cause]
+}
+
+// Settings of a tournament, each strategy plays Games games on each board size
+type Settings struct {
+	Strategies []string
+	BoardSizes []int
+	Games      int
+	Seed       int64 // The game i of each series is played with the seed Seed+i, the series are comparable
+	MaxRounds  int   // Rounds per game, gamestate.RoundsPerCell for each cell of the board when 0
+	Workers    int   // Games played at once, one per CPU core when 0
+}
+
+// Result is the outcome of a game
+type Result struct {
+	Strategy  string
+	BoardSize int
+	Seed      int64
+	Score     int
+	Rounds    int
+	Cause     Cause
+	Err       error // Why the game failed, with CauseError
+}
+
+// Stats sums up a value over the games
+type Stats struct {
+	Mean   float64
+	CI95   float64 // Half the width of the 95% confidence interval of the mean
+	Median float64
+	Max    int
+}
+
+// Row sums up the games of a strategy on a board size
+type Row struct {
+	Strategy  string
+	BoardSize int
+	Games     int
+	Score     Stats
+	Rounds    Stats // The rounds survived
+	Causes    [causeCount]int
+}
+
+// Report holds the rows in the order of the strategies then of the board sizes
+type Report struct {
+	Settings Settings
+	Rows     []Row
+	Results  []Result
+}
+
+// Run plays the games in parallel, without user interface
+func Run(settings Settings) (report Report, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := settings.validate(); err != nil {
+		return report, err
+	}
+
+	if settings.Workers == 0 {
+		settings.Workers = runtime.NumCPU()
+	}
+
+	var results []Result
+
+	for _, name := range settings.Strategies {
+		for _, size := range settings.BoardSizes {
+			for i := 0; i < settings.Games; i++ {
+				results = append(results, Result{Strategy: name, BoardSize: size, Seed: settings.Seed + int64(i)})
+			}
+		}
+	}
+
+	// Each worker plays the games it takes, the results keep their place
+	games := make(chan int)
+
+	var wait sync.WaitGroup
+
+	for worker := 0; worker < settings.Workers; worker++ {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			for i := range games {
+				playGame(&results[i], settings.maxRounds(results[i].BoardSize))
+			}
+		}()
+	}
+
+	for i := range results {
+		games <- i
+	}
+
+	close(games)
+	wait.Wait()
+
+	report = Report{Settings: settings, Results: results}
+
+	for i := 0; i < len(results); i += settings.Games {
+		report.Rows = append(report.Rows, summarize(results[i:i+settings.Games]))
+	}
+
+	return report, nil
+}
+
+func (settings Settings) validate() error {
+	if len(settings.Strategies) == 0 || len(settings.BoardSizes) == 0 {
+		return fmt.Errorf("%w: a strategy and a board size are needed at least", ErrInvalidSettings)
+	}
+
+	for _, name := range settings.Strategies {
+		if _, err := strategy.New(name, settings.Seed); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSettings, err)
+		}
+	}
+
+	for _, size := range settings.BoardSizes {
+		if size < gamestate.MinBoardSize {
+			return fmt.Errorf("%w: the board size must be at least %d, got %d", ErrInvalidSettings,
+				gamestate.MinBoardSize, size)
+		}
+	}
+
+	if settings.Games < 1 || settings.MaxRounds < 0 || settings.Workers < 0 {
+		return fmt.Errorf("%w: %d games, %d rounds, %d workers", ErrInvalidSettings,
+			settings.Games, settings.MaxRounds, settings.Workers)
+	}
+
+	return nil
+}
+
+func (settings Settings) maxRounds(boardSize int) int {
+	if settings.MaxRounds > 0 {
+		return settings.MaxRounds
+	}
+
+	return gamestate.RoundsPerCell * boardSize * boardSize
+}
+
+// playGame plays the game of result with the strategy named in result
+func playGame(result *Result, maxRounds int) {
+	aStrategy, err := strategy.New(result.Strategy, result.Seed)
+	if err != nil {
+		result.Cause, result.Err = CauseError, err
+		return
+	}
+
+	// The strategy keeps the name it was registered with
+	name := result.Strategy
+	*result = Play(aStrategy, result.BoardSize, result.Seed, maxRounds)
+	result.Strategy = name
+}
+
+// Play plays a game with the game state, the strategy steers the snake as the keys do
+// The game is limited to gamestate.RoundsPerCell rounds for each cell of the board when maxRounds is 0
+func Play(aStrategy strategy.Strategyer, boardSize int, seed int64, maxRounds int) (result Result) {
+	var err error
+
+	result = Result{Strategy: aStrategy.Name(), BoardSize: boardSize, Seed: seed}
+
+	if maxRounds <= 0 {
+		maxRounds = gamestate.RoundsPerCell * boardSize * boardSize
+	}
+
+	result.Cause, err = play(aStrategy, &result, maxRounds)
+	if err != nil {
+		result.Cause, result.Err = CauseError, err
+	}
+
+	return result
+}
+
+func play(aStrategy strategy.Strategyer, result *Result, maxRounds int) (cause Cause, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	gameState, err := gamestate.NewSeeded(common.Size{Width: result.BoardSize, Height: result.BoardSize}, result.Seed)
+	if err != nil {
+		return cause, err
+	}
+
+	// The score and the rounds are kept whatever the end of the game
+	defer func() {
+		result.Score, result.Rounds = gameState.Score(), gameState.Round()
+	}()
+
+	for gameState.GameInProgress() {
+		if gameState.Round() >= maxRounds {
+			return CauseRoundLimit, nil
+		}
+
+		state, err := strategy.ReadState(gameState)
+		if err != nil {
+			return cause, err
+		}
+
+		strategy.Steer(gameState, aStrategy.NextMove(state))
+
+		outcome, err := gamestate.PlayRound(gameState)
+		if err != nil {
+			return cause, err
+		}
+
+		if outcome == gamestate.OutcomeBoardFull {
+			return CauseBoardFull, nil
+		}
+	}
+
+	return CauseCollision, nil
+}
+
+func summarize(results []Result) (row Row) {
+	row.Strategy, row.BoardSize, row.Games = results[0].Strategy, results[0].BoardSize, len(results)
+
+	scores, rounds := make([]int, len(results)), make([]int, len(results))
+
+	for i := range results {
+		scores[i], rounds[i] = results[i].Score, results[i].Rounds
+		row.Causes[results[i].Cause]++
+	}
+
+	row.Score, row.Rounds = stats(scores), stats(rounds)
+
+	return row
+}
+
+// stats computes the mean with its confidence interval from the normal approximation, the median and the maximum
+func stats(values []int) (aStats Stats) {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	n := len(sorted)
+	aStats.Max = sorted[n-1]
+	aStats.Median = float64(sorted[(n-1)/2]+sorted[n/2]) / 2
+
+	sum := 0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	aStats.Mean = float64(sum) / float64(n)
+
+	if n < 2 {
+		return aStats
+	}
+
+	squares := 0.0
+	for _, value := range sorted {
+		squares += (float64(value) - aStats.Mean) * (float64(value) - aStats.Mean)
+	}
+
+	aStats.CI95 = z95 * math.Sqrt(squares/float64(n-1)/float64(n))
+
+	return aStats
+}
+
+// WriteText writes the report as a table aligned for the terminal
+func (report Report) WriteText(w io.Writer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	settings := report.Settings
+	fmt.Fprintf(w, "%d games per strategy and board size, seeds %d to %d, %d workers\n\n", settings.Games,
+		settings.Seed, settings.Seed+int64(settings.Games)-1, settings.Workers)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "STRATEGY\tSIZE\tGAMES\tSCORE\t±95%\tMEDIAN\tMAX\tROUNDS\t±95%\tMEDIAN\tMAX\t"+
+		"COLLISION\tROUND LIMIT\tBOARD FULL\tERROR\t")
+
+	for _, row := range report.Rows {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%.2f\t%.2f\t%.1f\t%d\t%.1f\t%.1f\t%.1f\t%d\t%d\t%d\t%d\t%d\t\n",
+			row.Strategy, row.BoardSize, row.Games,
+			row.Score.Mean, row.Score.CI95, row.Score.Median, row.Score.Max,
+			row.Rounds.Mean, row.Rounds.CI95, row.Rounds.Median, row.Rounds.Max,
+			row.Causes[CauseCollision], row.Causes[CauseRoundLimit], row.Causes[CauseBoardFull], row.Causes[CauseError])
+	}
+
+	return table.Flush()
+}
+
+// WriteCSV writes a line per row, with a header line
+func (report Report) WriteCSV(w io.Writer) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	output := csv.NewWriter(w)
+
+	output.Write([]string{"strategy", "board_size", "games",
+		"score_mean", "score_ci95", "score_median", "score_max",
+		"rounds_mean", "rounds_ci95", "rounds_median", "rounds_max",
+		"collision", "round_limit", "board_full", "error"})
+
+	for _, row := range report.Rows {
+		output.Write([]string{row.Strategy, strconv.Itoa(row.BoardSize), strconv.Itoa(row.Games),
+			formatFloat(row.Score.Mean), formatFloat(row.Score.CI95), formatFloat(row.Score.Median),
+			strconv.Itoa(row.Score.Max),
+			formatFloat(row.Rounds.Mean), formatFloat(row.Rounds.CI95), formatFloat(row.Rounds.Median),
+			strconv.Itoa(row.Rounds.Max),
+			strconv.Itoa(row.Causes[CauseCollision]), strconv.Itoa(row.Causes[CauseRoundLimit]),
+			strconv.Itoa(row.Causes[CauseBoardFull]), strconv.Itoa(row.Causes[CauseError])})
+	}
+
+	output.Flush()
+
+	return output.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
@@ -0,0 +1,165 @@
+package tournament
+
+import (
+	"bytes"
+	"encoding/csv"
+	"gosnake/pkg/common"
+	"gosnake/pkg/strategy"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	settings := Settings{
+		Strategies: []string{"greedy", "random"},
+		BoardSizes: []int{6, 10},
+		Games:      8,
+		Seed:       100,
+		MaxRounds:  300,
+		Workers:    3,
+	}
+
+	report, err := Run(settings)
+	require.NoError(t, err)
+	require.Len(t, report.Results, 32)
+	require.Len(t, report.Rows, 4)
+
+	// The rows follow the strategies then the board sizes
+	require.Equal(t, "greedy", report.Rows[0].Strategy)
+	require.Equal(t, 10, report.Rows[1].BoardSize)
+	require.Equal(t, "random", report.Rows[2].Strategy)
+
+	for _, row := range report.Rows {
+		require.Equal(t, 8, row.Games)
+		require.Equal(t, 0, row.Causes[CauseError])
+		require.Equal(t, 8, row.Causes[CauseCollision]+row.Causes[CauseRoundLimit]+row.Causes[CauseBoardFull])
+		require.LessOrEqual(t, row.Rounds.Max, 300)
+		require.LessOrEqual(t, row.Score.Mean, float64(row.Score.Max))
+	}
+
+	// The seeds make the tournaments repeatable, whatever the number of workers
+	settings.Workers = 1
+	again, err := Run(settings)
+	require.NoError(t, err)
+	require.Equal(t, report.Rows, again.Rows)
+}
+
+func TestRunErrors(t *testing.T) {
+	valid := Settings{Strategies: []string{"greedy"}, BoardSizes: []int{10}, Games: 1}
+
+	tests := []struct {
+		name   string
+		change func(settings *Settings)
+	}{
+		{
+			name:   "TestUnknownStrategy",
+			change: func(settings *Settings) { settings.Strategies = []string{"psychic"} },
+		},
+		{
+			name:   "TestNoBoardSize",
+			change: func(settings *Settings) { settings.BoardSizes = nil },
+		},
+		{
+			name:   "TestBoardTooSmall",
+			change: func(settings *Settings) { settings.BoardSizes = []int{2} },
+		},
+		{
+			name:   "TestNoGames",
+			change: func(settings *Settings) { settings.Games = 0 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.change(&settings)
+			_, err := Run(settings)
+			require.ErrorIs(t, err, ErrInvalidSettings)
+		})
+	}
+}
+
+// panicking is a strategy with a bug
+type panicking struct{}
+
+func (panicking) Name() string { return "panicking" }
+
+func (panicking) NextMove(strategy.State) (direction common.Direction) { panic("bug") }
+
+func TestRunStrategyPanic(t *testing.T) {
+	strategy.Register("panicking", func(int64) strategy.Strategyer { return panicking{} })
+
+	// The game fails, the tournament goes on
+	report, err := Run(Settings{Strategies: []string{"panicking"}, BoardSizes: []int{8}, Games: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Rows[0].Causes[CauseError])
+	require.Error(t, report.Results[0].Err)
+}
+
+func TestPlay(t *testing.T) {
+	greedy, err := strategy.New("greedy", 0)
+	require.NoError(t, err)
+
+	result := Play(greedy, 8, 42, 0)
+	require.NoError(t, result.Err)
+	require.Equal(t, Result{Strategy: "greedy", BoardSize: 8, Seed: 42, Score: result.Score, Rounds: result.Rounds,
+		Cause: result.Cause}, result)
+	require.Greater(t, result.Score, 0)
+
+	// The same seed plays the same game
+	require.Equal(t, result, Play(greedy, 8, 42, 0))
+
+	limited := Play(greedy, 8, 42, 5)
+	require.Equal(t, CauseRoundLimit, limited.Cause)
+	require.Equal(t, 5, limited.Rounds)
+
+	require.Equal(t, CauseError, Play(panicking{}, 8, 42, 0).Cause)
+}
+
+func Test_stats(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []int
+		wantStats Stats
+	}{
+		{
+			name:      "TestOneValue",
+			values:    []int{4},
+			wantStats: Stats{Mean: 4, Median: 4, Max: 4},
+		},
+		{
+			name:      "TestEvenCount",
+			values:    []int{5, 1, 3, 7},
+			wantStats: Stats{Mean: 4, CI95: 1.96 * 1.2909944487358056, Median: 4, Max: 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStats := stats(tt.values)
+			require.InDelta(t, tt.wantStats.CI95, gotStats.CI95, 1e-9)
+			gotStats.CI95 = tt.wantStats.CI95
+			require.Equal(t, tt.wantStats, gotStats)
+		})
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	report, err := Run(Settings{Strategies: []string{"greedy"}, BoardSizes: []int{8}, Games: 3, Seed: 1, Workers: 2})
+	require.NoError(t, err)
+
+	var text bytes.Buffer
+	require.NoError(t, report.WriteText(&text))
+	require.Contains(t, text.String(), "3 games per strategy and board size, seeds 1 to 3")
+	require.Contains(t, text.String(), "COLLISION")
+	require.Len(t, strings.Split(strings.TrimSpace(text.String()), "\n"), 4)
+
+	var table bytes.Buffer
+	require.NoError(t, report.WriteCSV(&table))
+	records, err := csv.NewReader(&table).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "strategy", records[0][0])
+	require.Equal(t, []string{"greedy", "8", "3"}, records[1][:3])
+	require.Len(t, records[1], len(records[0]))
+}
@@ -0,0 +1,581 @@
+package uimanager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Escape sequences written by the ANSI backend
+const (
+	ansiOpen        = "\x1b[?1049h\x1b[?25l\x1b[2J" // Alternate screen, hidden cursor
+	ansiClose       = "\x1b[0m\x1b[?25h\x1b[?1049l"
+	ansiMoveTo      = "\x1b[%d;%dH"
+	ansiReset       = "0"
+	ansiBold        = "1"
+	ansiFgColors    = 30 // Then 38;5;n for the palette
+	ansiBgColors    = 40 // Then 48;5;n for the palette
+	ansiBasicColors = 8
+)
+
+// Frames of the views
+const (
+	frameHorizontal  = '─'
+	frameVertical    = '│'
+	frameTopLeft     = '┌'
+	frameTopRight    = '┐'
+	frameBottomLeft  = '└'
+	frameBottomRight = '┘'
+)
+
+// Defines the errors of the ANSI backend
+var (
+	ErrUnknownView  = errors.New("unknown view")
+	errInvalidPoint = errors.New("invalid point")
+)
+
+// ansiManager draws the views with ANSI escape sequences and reads the keys from stdin in raw mode
+// It has the same views as gocui: framed, wrapped, drawn in the order they were created
+type ansiManager struct {
+	painter
+	mutex    sync.Mutex
+	views    []*ansiView
+	handlers map[Key][]func(Key) error
+	input    io.Reader
+	output   *bufio.Writer
+	restore  func() error
+	screen   []string // The rows last written, the others are written again
+	redraw   chan struct{}
+}
+
+// ansiView is a framed rectangle of cells
+type ansiView struct {
+	name     string
+	position common.ViewPosition
+	style    common.Style // The style of the text without one and of the background
+	current  common.Style // The style of the next cells written
+	cells    [][]ansiCell
+	cx, cy   int
+}
+
+// ansiCell is a character of a view, the style of the view is used when it has none
+type ansiCell struct {
+	ch    rune
+	style common.Style
+}
+
+// NewANSI returns an instance of ansiManager
+func NewANSI() UIManagerer {
+	return &ansiManager{
+		painter:  newPainter(),
+		handlers: make(map[Key][]func(Key) error),
+		redraw:   make(chan struct{}, 1),
+	}
+}
+
+// OpenUIManager switches the terminal to raw mode and to the alternate screen
+func (am *ansiManager) OpenUIManager(colorMode ColorMode) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if colorMode == ColorsAuto {
+		colorMode = DetectColorMode()
+	}
+
+	if am.restore, err = makeRaw(int(os.Stdin.Fd())); err != nil {
+		return err
+	}
+
+	am.colorMode = colorMode
+	am.input = os.Stdin
+	am.output = bufio.NewWriter(os.Stdout)
+
+	if _, err := am.output.WriteString(ansiOpen); err != nil {
+		return err
+	}
+
+	return am.output.Flush()
+}
+
+// Close restores the terminal
+func (am *ansiManager) Close() {
+	am.output.WriteString(ansiClose)
+	am.output.Flush()
+
+	if am.restore != nil {
+		am.restore()
+	}
+}
+
+// MainLoop draws the views when they change and calls the handlers of the keys typed
+func (am *ansiManager) MainLoop() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	keys := make(chan []Key)
+	readErr := make(chan error, 1)
+
+	go func() {
+		data := make([]byte, 64)
+
+		for {
+			n, err := am.input.Read(data)
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			keys <- parseKeys(data[:n])
+		}
+	}()
+
+	for {
+		if err := am.draw(); err != nil {
+			return err
+		}
+
+		select {
+		case <-am.redraw:
+		case err := <-readErr:
+			return err
+		case typed := <-keys:
+			for _, key := range typed {
+				if err := am.handle(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (am *ansiManager) handle(key Key) error {
+	am.mutex.Lock()
+	handlers := am.handlers[key]
+	am.mutex.Unlock()
+
+	for _, fn := range handlers {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// invalidate makes the main loop draw the views
+func (am *ansiManager) invalidate() {
+	select {
+	case am.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// Update a view with a list of sprites
+func (am *ansiManager) Update(viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		return err
+	}
+
+	defer am.invalidate()
+
+	return am.displaySprites(view, viewName, spriteList)
+}
+
+// UpdateLn prints a line to the view
+func (am *ansiManager) UpdateLn(viewName, msg string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		return err
+	}
+
+	defer am.invalidate()
+
+	if err := view.SetCursor(0, 0); err != nil {
+		return err
+	}
+
+	return writeLn(view, msg)
+}
+
+// SetView adds the view to the display manager, or moves it
+func (am *ansiManager) SetView(viewName string, position common.ViewPosition) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	defer am.invalidate()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		view = &ansiView{name: viewName, style: am.styles.Views[viewName]}
+		am.views = append(am.views, view)
+	} else if view.position == position {
+		return nil
+	}
+
+	view.position = position
+	view.clear()
+
+	return nil
+}
+
+// ClearView clears a view
+func (am *ansiManager) ClearView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		return err
+	}
+
+	view.clear()
+	am.clearBoard(viewName)
+	am.invalidate()
+
+	return nil
+}
+
+// DisplayRedLayout displays the view layout with the alert style, a red background unless a theme changes it
+func (am *ansiManager) DisplayRedLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		return err
+	}
+
+	view.style = am.Styles().Alert
+
+	return am.setLayout(view, layout)
+}
+
+// SetViewLayout defines the view layout
+func (am *ansiManager) SetViewLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	view, err := am.view(viewName)
+	if err != nil {
+		return err
+	}
+
+	return am.setLayout(view, layout)
+}
+
+func (am *ansiManager) setLayout(view *ansiView, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	defer am.invalidate()
+
+	view.clear()
+
+	for i := range layout {
+		if err := view.SetCursor(0, i); err != nil {
+			return err
+		}
+
+		if err := writeLn(view, layout[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteView removes the view from the display manager
+func (am *ansiManager) DeleteView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for i := range am.views {
+		if am.views[i].name == viewName {
+			am.views = append(am.views[:i], am.views[i+1:]...)
+			break
+		}
+	}
+
+	am.clearBoard(viewName)
+	am.invalidate()
+
+	return nil
+}
+
+// SetTheme defines the styles of the views and of the sprites
+func (am *ansiManager) SetTheme(styles Styles) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	am.setStyles(styles)
+
+	for _, view := range am.views {
+		if style, ok := styles.Views[view.name]; ok {
+			view.style = style
+		}
+	}
+
+	am.invalidate()
+}
+
+// OnKeyPress attaches the keys to an eventHandler
+func (am *ansiManager) OnKeyPress(keys []Key, fn func(Key) error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for i := range keys {
+		am.handlers[keys[i]] = append(am.handlers[keys[i]], fn)
+	}
+
+	return nil
+}
+
+// Quit stops the mainLoop
+func (am *ansiManager) Quit() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return ErrQuit
+}
+
+func (am *ansiManager) view(viewName string) (*ansiView, error) {
+	for _, view := range am.views {
+		if view.name == viewName {
+			return view, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownView, viewName)
+}
+
+// draw writes the rows of the screen which changed since the last call
+func (am *ansiManager) draw() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	am.mutex.Lock()
+	rows := am.render()
+	am.mutex.Unlock()
+
+	for y := range rows {
+		if y < len(am.screen) && am.screen[y] == rows[y] {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(am.output, ansiMoveTo+"%s", y+1, 1, rows[y]); err != nil {
+			return err
+		}
+	}
+
+	am.screen = rows
+
+	return am.output.Flush()
+}
+
+// render returns the rows of the screen with their escape sequences
+func (am *ansiManager) render() []string {
+	var size common.Size
+
+	for _, view := range am.views {
+		if view.position.X2+1 > size.Width {
+			size.Width = view.position.X2 + 1
+		}
+
+		if view.position.Y2+1 > size.Height {
+			size.Height = view.position.Y2 + 1
+		}
+	}
+
+	screen := make([][]ansiCell, size.Height)
+	for y := range screen {
+		screen[y] = make([]ansiCell, size.Width)
+		for x := range screen[y] {
+			screen[y][x] = ansiCell{ch: ' ', style: am.styles.Screen}
+		}
+	}
+
+	for _, view := range am.views {
+		view.render(screen, am.styles.Screen)
+	}
+
+	rows := make([]string, len(screen))
+
+	for y := range screen {
+		var (
+			row   strings.Builder
+			style common.Style
+		)
+
+		row.WriteString(am.sgr(style))
+
+		for x := 0; x < len(screen[y]); x++ {
+			if screen[y][x].style != style {
+				style = screen[y][x].style
+				row.WriteString(am.sgr(style))
+			}
+
+			row.WriteRune(screen[y][x].ch)
+
+			// The right half of a wide rune hides the next cell
+			if RuneWidth(screen[y][x].ch) == 2 {
+				x++
+			}
+		}
+
+		rows[y] = row.String()
+	}
+
+	return rows
+}
+
+// sgr returns the escape sequence selecting the style
+func (am *ansiManager) sgr(style common.Style) string {
+	codes := []string{ansiReset}
+
+	if style.Bold {
+		codes = append(codes, ansiBold)
+	}
+
+	if fgColor := am.color(style.Fg); fgColor != common.ColorDefault {
+		codes = append(codes, colorCode(fgColor, ansiFgColors))
+	}
+
+	if bgColor := am.color(style.Bg); bgColor != common.ColorDefault {
+		codes = append(codes, colorCode(bgColor, ansiBgColors))
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// colorCode returns the code of a basic color, or of a color of the palette
+func colorCode(color common.Color, base int) string {
+	index := int(color) - 1
+	if index < ansiBasicColors {
+		return strconv.Itoa(base + index)
+	}
+
+	return fmt.Sprintf("%d;5;%d", base+8, index)
+}
+
+// Size returns the number of columns and rows inside the frame
+func (view *ansiView) Size() (x, y int) {
+	return view.position.X2 - view.position.X1 - 1, view.position.Y2 - view.position.Y1 - 1
+}
+
+// SetCursor moves the cursor inside the view
+func (view *ansiView) SetCursor(x, y int) error {
+	maxX, maxY := view.Size()
+	if x < 0 || x >= maxX || y < 0 || y >= maxY {
+		return errInvalidPoint
+	}
+
+	view.cx, view.cy = x, y
+
+	return nil
+}
+
+// EditWrite writes ch at the cursor and moves it, to the next row at the end of a row
+func (view *ansiView) EditWrite(ch rune) {
+	maxX, _ := view.Size()
+
+	if view.cy < len(view.cells) && view.cx < len(view.cells[view.cy]) {
+		view.cells[view.cy][view.cx] = ansiCell{ch: ch, style: view.current}
+	}
+
+	if view.cx++; view.cx >= maxX {
+		view.cx = 0
+		view.cy++
+	}
+}
+
+func (view *ansiView) setStyle(style common.Style) {
+	view.current = style
+}
+
+func (view *ansiView) clear() {
+	maxX, maxY := view.Size()
+	if maxX < 0 || maxY < 0 {
+		maxX, maxY = 0, 0
+	}
+
+	view.cells = make([][]ansiCell, maxY)
+	for y := range view.cells {
+		view.cells[y] = make([]ansiCell, maxX)
+	}
+}
+
+// render draws the frame and the cells of the view on the screen
+func (view *ansiView) render(screen [][]ansiCell, frameStyle common.Style) {
+	position := view.position
+
+	set := func(x, y int, cell ansiCell) {
+		if y >= 0 && y < len(screen) && x >= 0 && x < len(screen[y]) {
+			screen[y][x] = cell
+		}
+	}
+
+	for x := position.X1 + 1; x < position.X2; x++ {
+		set(x, position.Y1, ansiCell{ch: frameHorizontal, style: frameStyle})
+		set(x, position.Y2, ansiCell{ch: frameHorizontal, style: frameStyle})
+	}
+
+	for y := position.Y1 + 1; y < position.Y2; y++ {
+		set(position.X1, y, ansiCell{ch: frameVertical, style: frameStyle})
+		set(position.X2, y, ansiCell{ch: frameVertical, style: frameStyle})
+	}
+
+	set(position.X1, position.Y1, ansiCell{ch: frameTopLeft, style: frameStyle})
+	set(position.X2, position.Y1, ansiCell{ch: frameTopRight, style: frameStyle})
+	set(position.X1, position.Y2, ansiCell{ch: frameBottomLeft, style: frameStyle})
+	set(position.X2, position.Y2, ansiCell{ch: frameBottomRight, style: frameStyle})
+
+	for y := 0; y < position.Y2-position.Y1-1; y++ {
+		for x := 0; x < position.X2-position.X1-1; x++ {
+			cell := ansiCell{ch: ' ', style: view.style}
+
+			if y < len(view.cells) && x < len(view.cells[y]) && view.cells[y][x].ch != 0 {
+				cell = ansiCell{ch: view.cells[y][x].ch, style: mergeStyles(view.cells[y][x].style, view.style)}
+			}
+
+			set(position.X1+1+x, position.Y1+1+y, cell)
+		}
+	}
+}
+
+// mergeStyles returns style with the colors it doesn't define taken from base
+func mergeStyles(style, base common.Style) common.Style {
+	if style.Fg == common.ColorDefault {
+		style.Fg = base.Fg
+	}
+
+	if style.Bg == common.ColorDefault {
+		style.Bg = base.Bg
+	}
+
+	style.Bold = style.Bold || base.Bold
+
+	return style
+}
@@ -0,0 +1,99 @@
+package uimanager
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// escapeSequences are the special keys sent by the terminals after ESC
+var escapeSequences = map[string]Key{
+	"[A":   KeyArrowUp,
+	"[B":   KeyArrowDown,
+	"[C":   KeyArrowRight,
+	"[D":   KeyArrowLeft,
+	"OA":   KeyArrowUp,
+	"OB":   KeyArrowDown,
+	"OC":   KeyArrowRight,
+	"OD":   KeyArrowLeft,
+	"[H":   {special: keyHome},
+	"OH":   {special: keyHome},
+	"[1~":  {special: keyHome},
+	"[7~":  {special: keyHome},
+	"[F":   {special: keyEnd},
+	"OF":   {special: keyEnd},
+	"[4~":  {special: keyEnd},
+	"[8~":  {special: keyEnd},
+	"[2~":  {special: keyInsert},
+	"[3~":  {special: keyDelete},
+	"[5~":  KeyPgup,
+	"[6~":  KeyPgdn,
+	"OP":   {special: keyF1},
+	"OQ":   {special: keyF2},
+	"OR":   {special: keyF3},
+	"OS":   {special: keyF4},
+	"[11~": {special: keyF1},
+	"[12~": {special: keyF2},
+	"[13~": {special: keyF3},
+	"[14~": {special: keyF4},
+	"[15~": {special: keyF5},
+	"[17~": {special: keyF6},
+	"[18~": {special: keyF7},
+	"[19~": {special: keyF8},
+	"[20~": KeyF9,
+	"[21~": KeyF10,
+	"[23~": {special: keyF11},
+	"[24~": {special: keyF12},
+}
+
+// controlKeys are the special keys sent as a single control character
+var controlKeys = map[rune]Key{
+	0x03: KeyCtrlC,
+	'\r': KeyEnter,
+	'\n': KeyEnter,
+	'\t': KeyTab,
+	' ':  KeySpace,
+	0x7f: {special: keyBackspace},
+	0x08: {special: keyBackspace},
+}
+
+// parseKeys returns the keys typed in data, as read from a terminal in raw mode
+// An ESC which doesn't start a known sequence is the escape key, the unknown sequences are dropped
+func parseKeys(data []byte) (keys []Key) {
+	for len(data) > 0 {
+		if data[0] == 0x1b {
+			key, size := parseEscape(data[1:])
+			if size > 0 {
+				keys = append(keys, key)
+			} else {
+				keys = append(keys, KeyEsc)
+			}
+
+			data = data[1+size:]
+
+			continue
+		}
+
+		ch, size := utf8.DecodeRune(data)
+		data = data[size:]
+
+		if key, ok := controlKeys[ch]; ok {
+			keys = append(keys, key)
+		} else if ch != utf8.RuneError && unicode.IsPrint(ch) {
+			keys = append(keys, KeyRune(ch))
+		}
+	}
+
+	return keys
+}
+
+// parseEscape returns the key of the escape sequence data starts with, and the size of the sequence
+func parseEscape(data []byte) (key Key, size int) {
+	for sequence, key := range escapeSequences {
+		if bytes.HasPrefix(data, []byte(sequence)) {
+			return key, len(sequence)
+		}
+	}
+
+	return key, 0
+}
@@ -0,0 +1,131 @@
+package uimanager
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"gosnake/pkg/common"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantKeys []Key
+	}{
+		{
+			name:     "TestCharacters",
+			data:     "zQé",
+			wantKeys: []Key{KeyRune('z'), KeyRune('Q'), KeyRune('é')},
+		},
+		{
+			name:     "TestControls",
+			data:     "\x03\r\t ",
+			wantKeys: []Key{KeyCtrlC, KeyEnter, KeyTab, KeySpace},
+		},
+		{
+			name:     "TestArrows",
+			data:     "\x1b[A\x1bOB\x1b[C\x1b[D",
+			wantKeys: []Key{KeyArrowUp, KeyArrowDown, KeyArrowRight, KeyArrowLeft},
+		},
+		{
+			name:     "TestFunctionKeys",
+			data:     "\x1b[5~\x1b[6~\x1b[20~\x1b[21~",
+			wantKeys: []Key{KeyPgup, KeyPgdn, KeyF9, KeyF10},
+		},
+		{
+			name:     "TestEscape", // An ESC alone or before a character which doesn't start a sequence
+			data:     "\x1b\x1bp",
+			wantKeys: []Key{KeyEsc, KeyEsc, KeyRune('p')},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantKeys, parseKeys([]byte(tt.data)))
+		})
+	}
+}
+
+func newTestANSI(output *bytes.Buffer) *ansiManager {
+	am := NewANSI().(*ansiManager)
+	am.colorMode = Colors256
+	am.output = bufio.NewWriter(output)
+
+	return am
+}
+
+func TestAnsiManager_draw(t *testing.T) {
+	var output bytes.Buffer
+
+	am := newTestANSI(&output)
+	am.SetTheme(Styles{
+		Views: map[string]common.Style{"board": {Fg: common.ColorGreen}},
+		Cells: map[rune]common.Style{'*': {Fg: common.Color256(214)}},
+	})
+	am.SetGlyphs(map[rune]rune{'S': '█'})
+
+	require.NoError(t, am.SetView("board", common.ViewPosition{X1: 0, Y1: 0, X2: 6, Y2: 3}))
+	require.NoError(t, am.SetViewLayout("board", []string{"蛇 ok"}))
+	require.NoError(t, am.Update("board", []common.Sprite{
+		{Value: 'S', Position: common.Position{X: 0, Y: 1}},
+		{Value: '*', Position: common.Position{X: 1, Y: 1}},
+	}))
+	require.NoError(t, am.draw())
+
+	// The frame, the text and the sprites with their colors
+	screen := output.String()
+	require.Contains(t, screen, "┌─────┐")
+	require.Contains(t, screen, "蛇 ok")
+	require.Contains(t, screen, "\x1b[0;32m█\x1b[0;38;5;214m*")
+	require.Contains(t, screen, "└─────┘")
+
+	// Only the rows which changed are written again
+	output.Reset()
+	require.NoError(t, am.UpdateLn("board", "Hi"))
+	require.NoError(t, am.draw())
+	require.Equal(t, 1, strings.Count(output.String(), "\x1b[2;1H"+am.sgr(common.Style{})))
+	require.NotContains(t, output.String(), "┌")
+}
+
+func TestAnsiManager_Styles(t *testing.T) {
+	am := newTestANSI(&bytes.Buffer{})
+	am.SetTheme(Styles{Trap: common.Style{Bg: common.ColorRed}})
+
+	// The alerts keep their style when the theme has none
+	require.Equal(t, common.Style{Bg: common.ColorRed}, am.Styles().Trap)
+	require.Equal(t, common.Style{Bg: common.ColorRed}, am.Styles().Alert)
+}
+
+func TestAnsiManager_views(t *testing.T) {
+	var output bytes.Buffer
+
+	am := newTestANSI(&output)
+
+	require.True(t, errors.Is(am.ClearView("unknown"), ErrUnknownView))
+	require.NoError(t, am.SetView("under", common.ViewPosition{X1: 0, Y1: 0, X2: 9, Y2: 2}))
+	require.NoError(t, am.SetView("over", common.ViewPosition{X1: 0, Y1: 0, X2: 9, Y2: 2}))
+	require.NoError(t, am.SetViewLayout("under", []string{"under"}))
+	require.NoError(t, am.DisplayRedLayout("over", []string{"over"}))
+	require.NoError(t, am.draw())
+
+	// The views created last are drawn over the others, with the alert style
+	require.Contains(t, output.String(), "\x1b[0;41mover")
+	require.NotContains(t, output.String(), "under")
+
+	require.NoError(t, am.DeleteView("over"))
+	require.NoError(t, am.draw())
+	require.Contains(t, output.String(), "under")
+
+	// Every handler of a key is called
+	var calls int
+	handler := func(Key) error { calls++; return nil }
+	require.NoError(t, am.OnKeyPress([]Key{KeyEnter, KeyRune('q')}, handler))
+	require.NoError(t, am.OnKeyPress([]Key{KeyEnter}, handler))
+	require.NoError(t, am.handle(KeyEnter))
+	require.Equal(t, 2, calls)
+	require.ErrorIs(t, am.Quit(), ErrQuit)
+}
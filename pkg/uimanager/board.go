@@ -0,0 +1,166 @@
+package uimanager
+
+import (
+	"gosnake/pkg/common"
+)
+
+// Runes of the packed board modes
+const (
+	upperHalfBlock = '▀'
+	lowerHalfBlock = '▄'
+	fullBlock      = '█'
+	brailleBlank   = '⠀' // The Braille dots are added to it
+	blankCell      = ' ' // The value of the empty cells, they aren't drawn in a packed board mode
+)
+
+// brailleDots are the dots of a Braille character, by position in its 2x4 block of cells
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// Packed tells if several board cells are drawn in a terminal cell
+func (boardMode BoardMode) Packed() bool {
+	return boardMode == BoardHalfBlock || boardMode == BoardBraille
+}
+
+// blockSize returns the number of board cells drawn in a terminal cell, horizontally and vertically
+func (boardMode BoardMode) blockSize() common.Size {
+	switch boardMode {
+	case BoardHalfBlock:
+		return common.Size{Width: 1, Height: 2}
+	case BoardBraille:
+		return common.Size{Width: 2, Height: 4}
+	default:
+		return common.Size{Width: 1, Height: 1}
+	}
+}
+
+// ViewSize returns the number of terminal columns and rows needed to draw a board of boardSize cells,
+// cellWidth being the number of columns of a cell in the normal and square modes
+func (boardMode BoardMode) ViewSize(boardSize common.Size, cellWidth int) common.Size {
+	block := boardMode.blockSize()
+	if boardMode.Packed() {
+		cellWidth = 1
+	}
+
+	return common.Size{
+		Width:  (boardSize.Width*cellWidth + block.Width - 1) / block.Width,
+		Height: (boardSize.Height + block.Height - 1) / block.Height,
+	}
+}
+
+// displayPacked records the sprites in the board of the view, then draws the terminal cells which changed
+func (pnt *painter) displayPacked(view canvas, viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	board, ok := pnt.boards[viewName]
+	if !ok {
+		board = make(map[common.Position]common.Style)
+		pnt.boards[viewName] = board
+	}
+
+	var (
+		block   = pnt.boardMode.blockSize()
+		changed []common.Position
+		seen    = make(map[common.Position]bool)
+	)
+
+	for i := range spriteList {
+		if spriteList[i].Value == blankCell {
+			delete(board, spriteList[i].Position)
+		} else {
+			board[spriteList[i].Position] = pnt.spriteStyle(spriteList[i])
+		}
+
+		terminalCell := common.Position{
+			X: spriteList[i].Position.X / block.Width,
+			Y: spriteList[i].Position.Y / block.Height,
+		}
+
+		if !seen[terminalCell] {
+			seen[terminalCell] = true
+			changed = append(changed, terminalCell)
+		}
+	}
+
+	for _, terminalCell := range changed {
+		if err := view.SetCursor(terminalCell.X, terminalCell.Y); err != nil {
+			return err
+		}
+
+		var (
+			ch    rune
+			style common.Style
+		)
+
+		if pnt.boardMode == BoardHalfBlock {
+			ch, style = halfBlock(board, terminalCell)
+		} else {
+			ch, style = braille(board, terminalCell)
+		}
+
+		view.setStyle(style)
+		view.EditWrite(ch)
+	}
+
+	return nil
+}
+
+// halfBlock returns the half block drawing the two cells of the terminal cell, the upper one in the
+// foreground color and the lower one in the background color
+func halfBlock(board map[common.Position]common.Style, terminalCell common.Position) (rune, common.Style) {
+	upper, upperOK := board[common.Position{X: terminalCell.X, Y: terminalCell.Y * 2}]
+	lower, lowerOK := board[common.Position{X: terminalCell.X, Y: terminalCell.Y*2 + 1}]
+
+	switch {
+	case upperOK && lowerOK && upper.Fg == lower.Fg:
+		return fullBlock, common.Style{Fg: upper.Fg, Bold: upper.Bold || lower.Bold}
+	case upperOK && lowerOK:
+		return upperHalfBlock, common.Style{Fg: upper.Fg, Bg: lower.Fg, Bold: upper.Bold}
+	case upperOK:
+		return upperHalfBlock, common.Style{Fg: upper.Fg, Bold: upper.Bold}
+	case lowerOK:
+		return lowerHalfBlock, common.Style{Fg: lower.Fg, Bold: lower.Bold}
+	default:
+		return blankCell, common.Style{}
+	}
+}
+
+// braille returns the Braille character with a dot for each cell drawn in the terminal cell
+// A terminal cell has a single color: the one of its fewest dots, so that the candy beside the snake stays visible
+func braille(board map[common.Position]common.Style, terminalCell common.Position) (rune, common.Style) {
+	var (
+		dots   rune
+		styles []common.Style
+		counts = make(map[common.Style]int)
+	)
+
+	for x := range brailleDots {
+		for y := range brailleDots[x] {
+			style, ok := board[common.Position{X: terminalCell.X*2 + x, Y: terminalCell.Y*4 + y}]
+			if !ok {
+				continue
+			}
+
+			dots |= brailleDots[x][y]
+
+			if counts[style]++; counts[style] == 1 {
+				styles = append(styles, style)
+			}
+		}
+	}
+
+	if dots == 0 {
+		return blankCell, common.Style{}
+	}
+
+	style := styles[0]
+	for i := range styles {
+		if counts[styles[i]] < counts[style] {
+			style = styles[i]
+		}
+	}
+
+	return brailleBlank + dots, style
+}
@@ -0,0 +1,151 @@
+package uimanager
+
+import (
+	"gosnake/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	snakeStyle = common.Style{Fg: common.ColorGreen}
+	candyStyle = common.Style{Fg: common.ColorRed}
+)
+
+func TestBoardMode_ViewSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		boardMode BoardMode
+		boardSize int
+		cellWidth int
+		wantSize  common.Size
+	}{
+		{
+			name:      "TestNormal",
+			boardMode: BoardNormal,
+			boardSize: 40,
+			cellWidth: 1,
+			wantSize:  common.Size{Width: 40, Height: 40},
+		},
+		{
+			name:      "TestSquare",
+			boardMode: BoardSquare,
+			boardSize: 20,
+			cellWidth: 2,
+			wantSize:  common.Size{Width: 40, Height: 20},
+		},
+		{
+			name:      "TestHalfBlock",
+			boardMode: BoardHalfBlock,
+			boardSize: 80,
+			cellWidth: 2, // The glyphs aren't displayed
+			wantSize:  common.Size{Width: 80, Height: 40},
+		},
+		{
+			name:      "TestBraille",
+			boardMode: BoardBraille,
+			boardSize: 160,
+			cellWidth: 1,
+			wantSize:  common.Size{Width: 80, Height: 40},
+		},
+		{
+			name:      "TestBrailleRoundedUp",
+			boardMode: BoardBraille,
+			boardSize: 10,
+			cellWidth: 1,
+			wantSize:  common.Size{Width: 5, Height: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boardSize := common.Size{Width: tt.boardSize, Height: tt.boardSize}
+			require.Equal(t, tt.wantSize, tt.boardMode.ViewSize(boardSize, tt.cellWidth))
+		})
+	}
+}
+
+func Test_halfBlock(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     map[common.Position]common.Style
+		wantRune  rune
+		wantStyle common.Style
+	}{
+		{
+			name:     "TestEmpty",
+			wantRune: blankCell,
+		},
+		{
+			name:      "TestUpper",
+			board:     map[common.Position]common.Style{{X: 3, Y: 2}: snakeStyle},
+			wantRune:  upperHalfBlock,
+			wantStyle: snakeStyle,
+		},
+		{
+			name:      "TestLower",
+			board:     map[common.Position]common.Style{{X: 3, Y: 3}: candyStyle},
+			wantRune:  lowerHalfBlock,
+			wantStyle: candyStyle,
+		},
+		{
+			name:      "TestSameColor",
+			board:     map[common.Position]common.Style{{X: 3, Y: 2}: snakeStyle, {X: 3, Y: 3}: snakeStyle},
+			wantRune:  fullBlock,
+			wantStyle: snakeStyle,
+		},
+		{
+			name:      "TestTwoColors", // The lower cell is drawn with the background color
+			board:     map[common.Position]common.Style{{X: 3, Y: 2}: snakeStyle, {X: 3, Y: 3}: candyStyle},
+			wantRune:  upperHalfBlock,
+			wantStyle: common.Style{Fg: common.ColorGreen, Bg: common.ColorRed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, style := halfBlock(tt.board, common.Position{X: 3, Y: 1})
+			require.Equal(t, tt.wantRune, ch)
+			require.Equal(t, tt.wantStyle, style)
+		})
+	}
+}
+
+func Test_braille(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     map[common.Position]common.Style
+		wantRune  rune
+		wantStyle common.Style
+	}{
+		{
+			name:     "TestEmpty",
+			wantRune: blankCell,
+		},
+		{
+			name:      "TestCorners",
+			board:     map[common.Position]common.Style{{X: 2, Y: 4}: snakeStyle, {X: 3, Y: 7}: snakeStyle},
+			wantRune:  '⢁',
+			wantStyle: snakeStyle,
+		},
+		{
+			name: "TestCandyColor", // The fewest dots give the color
+			board: map[common.Position]common.Style{
+				{X: 2, Y: 4}: snakeStyle, {X: 2, Y: 5}: snakeStyle, {X: 2, Y: 6}: snakeStyle,
+				{X: 3, Y: 5}: candyStyle,
+			},
+			wantRune:  '⠗',
+			wantStyle: candyStyle,
+		},
+		{
+			name:     "TestOtherBlock",
+			board:    map[common.Position]common.Style{{X: 4, Y: 4}: snakeStyle, {X: 2, Y: 8}: snakeStyle},
+			wantRune: blankCell,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, style := braille(tt.board, common.Position{X: 1, Y: 1})
+			require.Equal(t, tt.wantRune, ch)
+			require.Equal(t, tt.wantStyle, style)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package uimanager
+
+import (
+	"gosnake/pkg/common"
+
+	"github.com/jroimartin/gocui"
+)
+
+// gocuiKeys are the gocui keys of the special keys
+var gocuiKeys = map[specialKey]gocui.Key{
+	keyCtrlC:      gocui.KeyCtrlC,
+	keyArrowUp:    gocui.KeyArrowUp,
+	keyArrowDown:  gocui.KeyArrowDown,
+	keyArrowLeft:  gocui.KeyArrowLeft,
+	keyArrowRight: gocui.KeyArrowRight,
+	keySpace:      gocui.KeySpace,
+	keyEnter:      gocui.KeyEnter,
+	keyPgup:       gocui.KeyPgup,
+	keyPgdn:       gocui.KeyPgdn,
+	keyTab:        gocui.KeyTab,
+	keyEsc:        gocui.KeyEsc,
+	keyHome:       gocui.KeyHome,
+	keyEnd:        gocui.KeyEnd,
+	keyInsert:     gocui.KeyInsert,
+	keyDelete:     gocui.KeyDelete,
+	keyBackspace:  gocui.KeyBackspace2,
+	keyF1:         gocui.KeyF1,
+	keyF2:         gocui.KeyF2,
+	keyF3:         gocui.KeyF3,
+	keyF4:         gocui.KeyF4,
+	keyF5:         gocui.KeyF5,
+	keyF6:         gocui.KeyF6,
+	keyF7:         gocui.KeyF7,
+	keyF8:         gocui.KeyF8,
+	keyF9:         gocui.KeyF9,
+	keyF10:        gocui.KeyF10,
+	keyF11:        gocui.KeyF11,
+	keyF12:        gocui.KeyF12,
+}
+
+// uiManager encapsulates gocui library
+type uiManager struct {
+	painter
+	gui *gocui.Gui
+}
+
+// gocuiView is a gocui view drawn by the painter
+type gocuiView struct {
+	*gocui.View
+	uim              *uiManager
+	fgColor, bgColor gocui.Attribute // The colors of the view
+}
+
+// New returns an instance of uiManager
+func New() UIManagerer {
+	return &uiManager{painter: newPainter()}
+}
+
+// OpenUIManager inits and gets a pointer to the user interface library
+func (uim *uiManager) OpenUIManager(colorMode ColorMode) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if colorMode == ColorsAuto {
+		colorMode = DetectColorMode()
+	}
+
+	outputMode := gocui.OutputNormal
+	if colorMode == Colors256 {
+		outputMode = gocui.Output256
+	}
+
+	gui, err := gocui.NewGui(outputMode)
+	if err != nil {
+		return err
+	}
+
+	uim.gui = gui
+	uim.colorMode = colorMode
+	uim.applyScreenStyle()
+
+	return nil
+}
+
+// Close the UI library
+func (uim *uiManager) Close() {
+	uim.gui.Close()
+}
+
+// MainLoop updates the UI and manages events
+func (uim *uiManager) MainLoop() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return uim.gui.MainLoop()
+}
+
+// Update a view with a list of sprites
+func (uim *uiManager) Update(viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var view *gocui.View
+
+	if view, err = uim.gui.View(viewName); err != nil {
+		return err
+	}
+
+	uim.gui.Update(
+		func(g *gocui.Gui) error {
+			return uim.displaySprites(uim.canvas(view), viewName, spriteList)
+		})
+
+	return nil
+}
+
+// UpdateLn prints a line to the view
+func (uim *uiManager) UpdateLn(viewName, msg string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var view *gocui.View
+
+	if view, err = uim.gui.View(viewName); err != nil {
+		return err
+	}
+
+	uim.gui.Update(
+		func(g *gocui.Gui) error {
+			return uim.writeLn(view, msg)
+		})
+
+	return nil
+}
+
+// SetTheme defines the styles of the views and of the sprites
+// The text already displayed keeps its style until it is written again
+func (uim *uiManager) SetTheme(styles Styles) {
+	uim.setStyles(styles)
+
+	if uim.gui == nil {
+		return
+	}
+
+	uim.applyScreenStyle()
+
+	for viewName := range styles.Views {
+		if view, err := uim.gui.View(viewName); err == nil {
+			view.FgColor, view.BgColor = uim.attributes(styles.Views[viewName])
+		}
+	}
+}
+
+func (uim *uiManager) applyScreenStyle() {
+	uim.gui.FgColor, uim.gui.BgColor = uim.attributes(uim.styles.Screen)
+}
+
+// attributes returns the gocui colors of a style
+func (uim *uiManager) attributes(style common.Style) (fgColor, bgColor gocui.Attribute) {
+	fgColor = gocui.Attribute(uim.color(style.Fg))
+	bgColor = gocui.Attribute(uim.color(style.Bg))
+
+	if style.Bold {
+		fgColor |= gocui.AttrBold
+	}
+
+	return fgColor, bgColor
+}
+
+// canvas returns the view to be drawn by the painter, with its current colors
+func (uim *uiManager) canvas(view *gocui.View) gocuiView {
+	return gocuiView{View: view, uim: uim, fgColor: view.FgColor, bgColor: view.BgColor}
+}
+
+func (view gocuiView) setStyle(style common.Style) {
+	fgColor, bgColor := view.uim.attributes(style)
+
+	if style.Fg == common.ColorDefault {
+		fgColor |= view.fgColor
+	}
+
+	if style.Bg == common.ColorDefault {
+		bgColor = view.bgColor
+	}
+
+	view.FgColor, view.BgColor = fgColor, bgColor
+}
+
+// SetView adds the view to the display manager
+func (uim *uiManager) SetView(viewName string, position common.ViewPosition) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if view, err := uim.gui.SetView(viewName, position.X1, position.Y1,
+		position.X2, position.Y2); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		view.Overwrite = true
+		view.Autoscroll = false
+		view.Wrap = true
+		view.FgColor, view.BgColor = uim.attributes(uim.styles.Views[viewName])
+	}
+
+	return nil
+}
+
+// ClearView clears a view
+func (uim *uiManager) ClearView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	view, err := uim.gui.View(viewName)
+	if err != nil {
+		return err
+	}
+
+	view.Clear()
+	uim.clearBoard(viewName)
+
+	return nil
+}
+
+// DisplayRedLayout displays the view layout with the alert style, a red background unless a theme changes it
+func (uim *uiManager) DisplayRedLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	view, err := uim.gui.View(viewName)
+	if err != nil {
+		return err
+	}
+
+	view.Clear()
+	view.FgColor, view.BgColor = uim.attributes(uim.Styles().Alert)
+
+	for i := range layout {
+		if err := view.SetCursor(0, i); err != nil {
+			return err
+		}
+
+		if err := writeLn(uim.canvas(view), layout[i]); err != nil {
+			return err
+		}
+	}
+
+	uim.gui.Update(func(g *gocui.Gui) error { return nil })
+
+	return nil
+}
+
+// SetViewLayout defines the view layout
+func (uim *uiManager) SetViewLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	view, err := uim.gui.View(viewName)
+	if err != nil {
+		return err
+	}
+
+	view.Clear()
+
+	for i := range layout {
+		if err := view.SetCursor(0, i); err != nil {
+			return err
+		}
+
+		if err := writeLn(uim.canvas(view), layout[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteView removes the view from the display manager
+func (uim *uiManager) DeleteView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err = uim.gui.DeleteView(viewName); err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+
+	uim.clearBoard(viewName)
+
+	// Redraws the views which were hidden
+	uim.gui.Update(func(g *gocui.Gui) error { return nil })
+
+	return nil
+}
+
+func (uim *uiManager) writeLn(view *gocui.View, str string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := view.SetCursor(0, 0); err != nil {
+		return err
+	}
+
+	return writeLn(uim.canvas(view), str)
+}
+
+// OnKeyPress attaches the keys to an eventHandler
+func (uim *uiManager) OnKeyPress(keys []Key, fn func(Key) error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	for i := range keys {
+		if err := uim.setKeybinding(keys[i], fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uim *uiManager) setKeybinding(key Key, fn func(Key) error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return uim.gui.SetKeybinding("", gocuiBinding(key), gocui.ModNone,
+		func(g *gocui.Gui, v *gocui.View) error {
+			return fn(key)
+		})
+}
+
+// gocuiBinding returns the key as expected by gocui
+func gocuiBinding(key Key) interface{} {
+	if key.ch != 0 {
+		return key.ch
+	}
+
+	return gocuiKeys[key.special]
+}
+
+// Quit stops the mainLoop
+func (uim *uiManager) Quit() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return ErrQuit
+}
@@ -0,0 +1,174 @@
+package uimanager
+
+import (
+	"fmt"
+	"gosnake/pkg/common"
+	"strings"
+)
+
+// Escape sequences written by FrameChanges
+const (
+	frameRowEnd    = "\x1b[0m\x1b[K" // Erases the end of the row
+	frameScreenEnd = "\x1b[0m\x1b[J" // Erases the rows below
+)
+
+// Mirrorer is the interface for mirror
+type Mirrorer interface {
+	UIManagerer
+	Frame() (rows []string)
+}
+
+// mirror displays the views with a backend and draws them again off screen,
+// the frames are the rows of the screen with ANSI escape sequences
+type mirror struct {
+	UIManagerer
+	screen *ansiManager // Never opened, it only renders
+}
+
+// NewMirror returns an instance of mirror displaying the views with userInterface
+func NewMirror(userInterface UIManagerer) Mirrorer {
+	return &mirror{UIManagerer: userInterface, screen: NewANSI().(*ansiManager)}
+}
+
+// OpenUIManager opens the backend, the frames use the same colors
+func (aMirror *mirror) OpenUIManager(colorMode ColorMode) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if colorMode == ColorsAuto {
+		colorMode = DetectColorMode()
+	}
+
+	aMirror.screen.colorMode = colorMode
+
+	return aMirror.UIManagerer.OpenUIManager(colorMode)
+}
+
+// Frame returns the rows of the screen as displayed
+func (aMirror *mirror) Frame() (rows []string) {
+	aMirror.screen.mutex.Lock()
+	defer aMirror.screen.mutex.Unlock()
+
+	return aMirror.screen.render()
+}
+
+// FrameChanges returns the escape sequences writing the rows of frame which differ from previous
+func FrameChanges(previous, frame []string) string {
+	var output strings.Builder
+
+	for y := range frame {
+		if y < len(previous) && previous[y] == frame[y] {
+			continue
+		}
+
+		fmt.Fprintf(&output, ansiMoveTo+"%s"+frameRowEnd, y+1, 1, frame[y])
+	}
+
+	if len(frame) < len(previous) {
+		fmt.Fprintf(&output, ansiMoveTo+frameScreenEnd, len(frame)+1, 1)
+	}
+
+	return output.String()
+}
+
+// Update a view with a list of sprites
+func (aMirror *mirror) Update(viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.Update(viewName, spriteList); err != nil {
+		return err
+	}
+
+	return aMirror.screen.Update(viewName, spriteList)
+}
+
+// UpdateLn prints a line to the view
+func (aMirror *mirror) UpdateLn(viewName, msg string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.UpdateLn(viewName, msg); err != nil {
+		return err
+	}
+
+	return aMirror.screen.UpdateLn(viewName, msg)
+}
+
+// SetView adds the view to the display manager
+func (aMirror *mirror) SetView(viewName string, position common.ViewPosition) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.SetView(viewName, position); err != nil {
+		return err
+	}
+
+	return aMirror.screen.SetView(viewName, position)
+}
+
+// ClearView clears a view
+func (aMirror *mirror) ClearView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.ClearView(viewName); err != nil {
+		return err
+	}
+
+	return aMirror.screen.ClearView(viewName)
+}
+
+// DisplayRedLayout displays the view layout with the alert style
+func (aMirror *mirror) DisplayRedLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.DisplayRedLayout(viewName, layout); err != nil {
+		return err
+	}
+
+	return aMirror.screen.DisplayRedLayout(viewName, layout)
+}
+
+// SetViewLayout defines the view layout
+func (aMirror *mirror) SetViewLayout(viewName string, layout []string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.SetViewLayout(viewName, layout); err != nil {
+		return err
+	}
+
+	return aMirror.screen.SetViewLayout(viewName, layout)
+}
+
+// DeleteView removes the view from the display manager
+func (aMirror *mirror) DeleteView(viewName string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aMirror.UIManagerer.DeleteView(viewName); err != nil {
+		return err
+	}
+
+	return aMirror.screen.DeleteView(viewName)
+}
+
+// SetGlyphs defines the runes displayed in place of the board cell values
+func (aMirror *mirror) SetGlyphs(glyphs map[rune]rune) {
+	aMirror.UIManagerer.SetGlyphs(glyphs)
+
+	aMirror.screen.mutex.Lock()
+	defer aMirror.screen.mutex.Unlock()
+
+	aMirror.screen.SetGlyphs(glyphs)
+}
+
+// SetBoardMode defines how the board cells are drawn
+func (aMirror *mirror) SetBoardMode(boardMode BoardMode) {
+	aMirror.UIManagerer.SetBoardMode(boardMode)
+
+	aMirror.screen.mutex.Lock()
+	defer aMirror.screen.mutex.Unlock()
+
+	aMirror.screen.SetBoardMode(boardMode)
+}
+
+// SetTheme defines the styles of the views and of the sprites
+func (aMirror *mirror) SetTheme(styles Styles) {
+	aMirror.UIManagerer.SetTheme(styles)
+	aMirror.screen.SetTheme(styles)
+}
@@ -0,0 +1,77 @@
+package uimanager
+
+import (
+	"bytes"
+	"gosnake/pkg/common"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirror(t *testing.T) {
+	var output bytes.Buffer
+
+	// The backend is an ANSI manager writing to a buffer
+	backend := newTestANSI(&output)
+	aMirror := NewMirror(backend)
+	aMirror.(*mirror).screen.colorMode = Colors256
+
+	aMirror.SetGlyphs(map[rune]rune{'S': '@'})
+	aMirror.SetTheme(Styles{Cells: map[rune]common.Style{'S': {Fg: common.ColorGreen}}})
+	require.NoError(t, aMirror.SetView("board", common.ViewPosition{X1: 0, Y1: 0, X2: 6, Y2: 3}))
+	require.NoError(t, aMirror.SetView("score", common.ViewPosition{X1: 7, Y1: 0, X2: 16, Y2: 3}))
+	require.NoError(t, aMirror.SetViewLayout("score", []string{"ROUND: 3"}))
+	require.NoError(t, aMirror.Update("board", []common.Sprite{{Value: 'S', Position: common.Position{X: 1, Y: 1}}}))
+	require.NoError(t, backend.draw())
+
+	// The frame holds what the backend displays
+	frame := strings.Join(aMirror.Frame(), "\n")
+	require.Equal(t, backend.screen, aMirror.Frame())
+	require.Contains(t, frame, "ROUND: 3")
+	require.Contains(t, frame, "\x1b[0;32m@")
+
+	// The errors of the backend are returned
+	require.ErrorIs(t, aMirror.UpdateLn("unknown", "text"), ErrUnknownView)
+
+	require.NoError(t, aMirror.DeleteView("score"))
+	require.NotContains(t, strings.Join(aMirror.Frame(), "\n"), "ROUND")
+}
+
+func TestFrameChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		frame    []string
+		want     string
+	}{
+		{
+			name:  "TestFirstFrame",
+			frame: []string{"ab", "cd"},
+			want:  "\x1b[1;1Hab" + frameRowEnd + "\x1b[2;1Hcd" + frameRowEnd,
+		},
+		{
+			name:     "TestSameFrame",
+			previous: []string{"ab", "cd"},
+			frame:    []string{"ab", "cd"},
+			want:     "",
+		},
+		{
+			name:     "TestChangedRow",
+			previous: []string{"ab", "cd"},
+			frame:    []string{"ab", "ce"},
+			want:     "\x1b[2;1Hce" + frameRowEnd,
+		},
+		{
+			name:     "TestShorterFrame",
+			previous: []string{"ab", "cd"},
+			frame:    []string{"ab"},
+			want:     "\x1b[2;1H" + frameScreenEnd,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, FrameChanges(tt.previous, tt.frame))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package uimanager
+
+import (
+	"gosnake/pkg/common"
+	"sync"
+)
+
+// canvas is a view both backends can draw in, cell by cell
+type canvas interface {
+	Size() (x, y int)
+	SetCursor(x, y int) error
+	EditWrite(ch rune)
+	setStyle(style common.Style) // The style of the next cells written, the style of the view when zero
+}
+
+// painter holds what the backends share to draw the views: the glyphs, the board mode and the theme
+type painter struct {
+	colorMode ColorMode
+	glyphs    map[rune]rune
+	boardMode BoardMode
+	// The number of columns of a board cell, 2 when a glyph is wide or the board square
+	cellWidth int
+	// The styles of the cells drawn in a packed board mode, by view
+	boards map[string]map[common.Position]common.Style
+	styles Styles
+	// The game engine reads the styles while the key handler changes the theme
+	stylesMutex sync.RWMutex
+}
+
+func newPainter() painter {
+	return painter{
+		cellWidth: 1,
+		boards:    make(map[string]map[common.Position]common.Style),
+		// The alerts have always been red
+		styles: Styles{Alert: common.Style{Bg: common.ColorRed}},
+	}
+}
+
+// SetGlyphs defines the runes displayed in place of the board cell values
+// The board cells take two columns when a glyph is wide
+func (pnt *painter) SetGlyphs(glyphs map[rune]rune) {
+	pnt.glyphs = glyphs
+	pnt.updateCellWidth()
+}
+
+// SetBoardMode defines how the board cells are drawn, the sprite positions are translated accordingly
+func (pnt *painter) SetBoardMode(boardMode BoardMode) {
+	pnt.boardMode = boardMode
+	pnt.updateCellWidth()
+}
+
+func (pnt *painter) updateCellWidth() {
+	pnt.cellWidth = 1
+
+	switch pnt.boardMode {
+	case BoardSquare:
+		pnt.cellWidth = 2
+	case BoardHalfBlock, BoardBraille:
+		// The glyphs aren't displayed
+		return
+	}
+
+	for _, glyph := range pnt.glyphs {
+		if RuneWidth(glyph) > pnt.cellWidth {
+			pnt.cellWidth = RuneWidth(glyph)
+		}
+	}
+}
+
+// setStyles replaces the theme, the alerts keep their style when the theme has none
+func (pnt *painter) setStyles(styles Styles) {
+	pnt.stylesMutex.Lock()
+	defer pnt.stylesMutex.Unlock()
+
+	if styles.Alert == (common.Style{}) {
+		styles.Alert = pnt.styles.Alert
+	}
+
+	pnt.styles = styles
+}
+
+// Styles returns the styles of the theme displayed, it can be called from any goroutine
+func (pnt *painter) Styles() Styles {
+	pnt.stylesMutex.RLock()
+	defer pnt.stylesMutex.RUnlock()
+
+	return pnt.styles
+}
+
+// clearBoard forgets the cells drawn in the view
+func (pnt *painter) clearBoard(viewName string) {
+	delete(pnt.boards, viewName)
+}
+
+func (pnt *painter) displaySprites(view canvas, viewName string, spriteList []common.Sprite) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// Each sprite is written with its style, then the style of the view is restored
+	defer view.setStyle(common.Style{})
+
+	if pnt.boardMode.Packed() {
+		return pnt.displayPacked(view, viewName, spriteList)
+	}
+
+	for i := range spriteList {
+		if err := view.SetCursor(spriteList[i].Position.X*pnt.cellWidth,
+			spriteList[i].Position.Y); err != nil {
+			return err
+		}
+
+		view.setStyle(pnt.spriteStyle(spriteList[i]))
+		writeCell(view, pnt.glyph(spriteList[i].Value), pnt.cellWidth, pnt.boardMode == BoardSquare)
+	}
+
+	return nil
+}
+
+// spriteStyle returns the style of the sprite, the style of its value in the theme when it has none
+func (pnt *painter) spriteStyle(sprite common.Sprite) common.Style {
+	if sprite.Style == (common.Style{}) {
+		return pnt.styles.Cells[sprite.Value]
+	}
+
+	return sprite.Style
+}
+
+func (pnt *painter) glyph(value rune) rune {
+	if glyph, ok := pnt.glyphs[value]; ok {
+		return glyph
+	}
+
+	return value
+}
+
+// color returns the color displayed, in 8 colors mode the colors of the palette are replaced by the closest basic color
+func (pnt *painter) color(color common.Color) common.Color {
+	if pnt.colorMode == Colors256 || color <= common.ColorWhite {
+		return color
+	}
+
+	return BasicColor(color)
+}
+
+// BasicColor returns the basic color closest to a color of the 256 colors palette
+func BasicColor(color common.Color) common.Color {
+	index := int(color) - 1
+
+	switch {
+	case index < 0:
+		return common.ColorDefault
+	case index < 16: // The basic colors then their bright versions
+		return common.Color(index%8 + 1)
+	case index < 232: // A 6x6x6 cube, each basic color is a corner
+		index -= 16
+		red, green, blue := index/36, index/6%6, index%6
+		basic := 0
+
+		if red > 2 {
+			basic |= 1
+		}
+
+		if green > 2 {
+			basic |= 2
+		}
+
+		if blue > 2 {
+			basic |= 4
+		}
+
+		return common.Color(basic + 1)
+	case index < 244: // The dark grays
+		return common.ColorBlack
+	default:
+		return common.ColorWhite
+	}
+}
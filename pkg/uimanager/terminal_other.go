@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package uimanager
+
+import "errors"
+
+// ErrRawMode is returned by the ANSI backend where the terminal can't be switched to raw mode
+var ErrRawMode = errors.New("the ansi backend isn't supported on this system")
+
+func makeRaw(fd int) (restore func() error, err error) {
+	return nil, ErrRawMode
+}
@@ -0,0 +1,44 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package uimanager
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw switches the terminal to raw mode: the keys are read as they are typed, without echo
+// The function returned restores the previous mode
+func makeRaw(fd int) (restore func() error, err error) {
+	var previous syscall.Termios
+
+	if err := ioctlTermios(fd, ioctlGetTermios, &previous); err != nil {
+		return nil, err
+	}
+
+	raw := previous
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR |
+		syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := ioctlTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() error { return ioctlTermios(fd, ioctlSetTermios, &previous) }, nil
+}
+
+func ioctlTermios(fd int, request uintptr, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request,
+		uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package uimanager
+
+import (
+	"gosnake/pkg/common"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// wideFiller fills the column hidden by the right half of a wide rune
+const wideFiller = ' '
+
+// RuneWidth returns the number of terminal columns r takes, as the terminal library draws it
+// The runes which can't be displayed on their own, control characters or combining marks, take none
+func RuneWidth(r rune) int {
+	if !unicode.IsPrint(r) {
+		return 0
+	}
+
+	// Ambiguous runes are drawn in a single column
+	width := runewidth.RuneWidth(r)
+	if width == 2 && runewidth.IsAmbiguousWidth(r) {
+		return 1
+	}
+
+	return width
+}
+
+// TextWidth returns the number of terminal columns str takes
+func TextWidth(str string) (width int) {
+	for _, r := range str {
+		width += RuneWidth(r)
+	}
+
+	return width
+}
+
+// TruncateText cuts str to fit in width columns, a wide rune is never split
+func TruncateText(str string, width int) string {
+	var columns int
+
+	for i, r := range str {
+		if columns += RuneWidth(r); columns > width {
+			return str[:i]
+		}
+	}
+
+	return str
+}
+
+// writeText writes str at the cursor, each rune taking as many view cells as terminal columns
+func writeText(view canvas, str string) {
+	var (
+		maxX, _ = view.Size()
+		column  = 0
+	)
+
+	for _, r := range str {
+		switch RuneWidth(r) {
+		case 1:
+			view.EditWrite(r)
+			column++
+		case 2:
+			// A wide rune can't be split over two lines, the views wrap
+			if maxX > 1 && column%maxX == maxX-1 {
+				view.EditWrite(wideFiller)
+				column++
+			}
+
+			view.EditWrite(r)
+			view.EditWrite(wideFiller)
+			column += 2
+		}
+	}
+}
+
+// writeCell writes glyph at the cursor and pads it to the width of a board cell,
+// with the glyph itself when paired or else blanks; the right half of a wide glyph hides its padding
+func writeCell(view canvas, glyph rune, width int, paired bool) {
+	if RuneWidth(glyph) == 0 {
+		glyph = wideFiller
+	}
+
+	pad := wideFiller
+	if paired && RuneWidth(glyph) == 1 {
+		pad = glyph
+	}
+
+	view.EditWrite(glyph)
+
+	for column := 1; column < width; column++ {
+		view.EditWrite(pad)
+	}
+}
+
+func writeLn(view canvas, str string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	writeText(view, str)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package uimanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		str       string
+		width     int
+		wantWidth int
+		wantText  string
+	}{
+		{
+			name:      "TestASCII",
+			str:       "Snake",
+			width:     3,
+			wantWidth: 5,
+			wantText:  "Sna",
+		},
+		{
+			name:      "TestAccents",
+			str:       "Serpent à sonnette",
+			width:     10,
+			wantWidth: 18,
+			wantText:  "Serpent à ",
+		},
+		{
+			name:      "TestWideRunes", // A wide rune is never split
+			str:       "蛇🍎",
+			width:     3,
+			wantWidth: 4,
+			wantText:  "蛇",
+		},
+		{
+			name:      "TestCombiningMark",
+			str:       "été",
+			width:     2,
+			wantWidth: 3,
+			wantText:  "ét",
+		},
+		{
+			name:      "TestFits",
+			str:       "GoSnake",
+			width:     18,
+			wantWidth: 7,
+			wantText:  "GoSnake",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantWidth, TextWidth(tt.str))
+			require.Equal(t, tt.wantText, TruncateText(tt.str, tt.width))
+		})
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	require.Equal(t, 1, RuneWidth('S'))
+	require.Equal(t, 1, RuneWidth('█'))
+	require.Equal(t, 2, RuneWidth('🍎'))
+	require.Equal(t, 0, RuneWidth('\t'))
+	require.Equal(t, 0, RuneWidth('\u0301'))
+}
@@ -1,14 +1,18 @@
 package uimanager
 
 import (
+	"errors"
+	"fmt"
 	"gosnake/pkg/common"
-
-	"github.com/jroimartin/gocui"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // UIManagerer is the interface for uiManager
 type UIManagerer interface {
-	OpenUIManager() (err error)
+	OpenUIManager(colorMode ColorMode) (err error)
 	Close()
 	MainLoop() (err error)
 	Update(viewName string, spriteList []common.Sprite) (err error)
@@ -17,257 +21,299 @@ type UIManagerer interface {
 	ClearView(viewName string) (err error)
 	DisplayRedLayout(viewName string, layout []string) (err error)
 	SetViewLayout(viewName string, layout []string) (err error)
-	OnKeyPress(fn func(Key) error) (err error)
+	DeleteView(viewName string) (err error)
+	SetGlyphs(glyphs map[rune]rune)
+	SetBoardMode(boardMode BoardMode)
+	SetTheme(styles Styles)
+	Styles() Styles
+	OnKeyPress(keys []Key, fn func(Key) error) (err error)
 	Quit() (err error)
 }
 
-// Key is an alias
-type Key gocui.Key
+// Key is a key pressed, either a special key or a character
+type Key struct {
+	special specialKey
+	ch      rune
+}
+
+// specialKey identifies the keys which don't type a character, each backend translates them
+type specialKey uint16
 
-// Aliases to active keys
 const (
-	KeyCtrlC      Key = Key(gocui.KeyCtrlC)
-	KeyArrowUp        = Key(gocui.KeyArrowUp)
-	KeyArrowDown      = Key(gocui.KeyArrowDown)
-	KeyArrowLeft      = Key(gocui.KeyArrowLeft)
-	KeyArrowRight     = Key(gocui.KeyArrowRight)
-	KeySpace          = Key(gocui.KeySpace)
-	KeyEnter          = Key(gocui.KeyEnter)
+	keyNone specialKey = iota
+	keyCtrlC
+	keyArrowUp
+	keyArrowDown
+	keyArrowLeft
+	keyArrowRight
+	keySpace
+	keyEnter
+	keyPgup
+	keyPgdn
+	keyTab
+	keyEsc
+	keyHome
+	keyEnd
+	keyInsert
+	keyDelete
+	keyBackspace
+	keyF1
+	keyF2
+	keyF3
+	keyF4
+	keyF5
+	keyF6
+	keyF7
+	keyF8
+	keyF9
+	keyF10
+	keyF11
+	keyF12
 )
 
-// Aliases to gocui constants
+// Aliases to special keys
 var (
-	ErrQuit    error = gocui.ErrQuit
-	ActiveKeys       = []Key{
-		KeyCtrlC,
-		KeyArrowUp,
-		KeyArrowDown,
-		KeyArrowLeft,
-		KeyArrowRight,
-		KeySpace,
-		KeyEnter,
-	}
+	KeyCtrlC      = Key{special: keyCtrlC}
+	KeyArrowUp    = Key{special: keyArrowUp}
+	KeyArrowDown  = Key{special: keyArrowDown}
+	KeyArrowLeft  = Key{special: keyArrowLeft}
+	KeyArrowRight = Key{special: keyArrowRight}
+	KeySpace      = Key{special: keySpace}
+	KeyEnter      = Key{special: keyEnter}
+	KeyPgup       = Key{special: keyPgup}
+	KeyPgdn       = Key{special: keyPgdn}
+	KeyTab        = Key{special: keyTab}
+	KeyEsc        = Key{special: keyEsc}
+	KeyF9         = Key{special: keyF9}
+	KeyF10        = Key{special: keyF10}
 )
 
-// uiManager encapsulates gocui library
-type uiManager struct {
-	gui *gocui.Gui
-}
-
-// New returns an instance of uiManager
-func New() UIManagerer {
-	return new(uiManager)
-}
+// Names of the backends
+const (
+	BackendGocui = "gocui"
+	BackendANSI  = "ansi"
+)
 
-// OpenUIManager inits and gets a pointer to the user interface library
-func (uim *uiManager) OpenUIManager() (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+// Defines custom errors
+var (
+	ErrQuit             = errors.New("quit")
+	ErrUnknownBackend   = errors.New("unknown backend, expected gocui or ansi")
+	ErrUnknownKey       = errors.New("unknown key")
+	ErrUnknownColorMode = errors.New("unknown color mode, expected auto, 8 or 256")
+	ErrUnknownBoardMode = errors.New("unknown board mode, expected normal, square, half-block or braille")
+)
 
-	gui, err := gocui.NewGui(gocui.OutputNormal)
-	if err != nil {
-		return err
-	}
+// ColorMode is the number of colors used
+type ColorMode int
 
-	uim.gui = gui
+// ColorsAuto uses 256 colors when the terminal supports them
+const (
+	ColorsAuto ColorMode = iota
+	Colors8
+	Colors256
+)
 
-	return nil
+var colorModeNames = []string{
+	ColorsAuto: "auto",
+	Colors8:    "8",
+	Colors256:  "256",
 }
 
-// Close the UI library
-func (uim *uiManager) Close() {
-	uim.gui.Close()
-}
+// BoardMode is the way the board cells are drawn
+type BoardMode int
 
-// MainLoop updates the UI and manages events
-func (uim *uiManager) MainLoop() (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+// BoardSquare draws each cell in two columns, terminal cells being about twice as tall as wide
+// BoardHalfBlock and BoardBraille pack 1x2 and 2x4 cells in a terminal cell, for large boards
+const (
+	BoardNormal BoardMode = iota
+	BoardSquare
+	BoardHalfBlock
+	BoardBraille
+)
 
-	return uim.gui.MainLoop()
+var boardModeNames = []string{
+	BoardNormal:    "normal",
+	BoardSquare:    "square",
+	BoardHalfBlock: "half-block",
+	BoardBraille:   "braille",
 }
 
-// Update a view with a list of sprites
-func (uim *uiManager) Update(viewName string, spriteList []common.Sprite) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	var view *gocui.View
-
-	if view, err = uim.gui.View(viewName); err != nil {
-		return err
-	}
-
-	uim.gui.Update(
-		func(g *gocui.Gui) error {
-			return uim.displaySprites(view, spriteList)
-		})
-
-	return nil
+// Styles are the styles of a theme
+type Styles struct {
+	Screen common.Style            // The frames and the background
+	Views  map[string]common.Style // The text of the views
+	Cells  map[rune]common.Style   // The sprites without a style, by value
+	Alert  common.Style            // The layouts displayed by DisplayRedLayout
+	Trap   common.Style            // The free cells of the region the snake is trapped in, cf the trap_shade setting
 }
 
-// UpdateLn prints a line to the view
-func (uim *uiManager) UpdateLn(viewName, msg string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+// keyNames are the names of the special keys which can be bound, as written in the configuration
+var keyNames = map[string]Key{
+	"ctrl+c":    KeyCtrlC,
+	"up":        KeyArrowUp,
+	"down":      KeyArrowDown,
+	"left":      KeyArrowLeft,
+	"right":     KeyArrowRight,
+	"space":     KeySpace,
+	"enter":     KeyEnter,
+	"pgup":      KeyPgup,
+	"pgdn":      KeyPgdn,
+	"tab":       KeyTab,
+	"esc":       KeyEsc,
+	"home":      {special: keyHome},
+	"end":       {special: keyEnd},
+	"insert":    {special: keyInsert},
+	"delete":    {special: keyDelete},
+	"backspace": {special: keyBackspace},
+	"f1":        {special: keyF1},
+	"f2":        {special: keyF2},
+	"f3":        {special: keyF3},
+	"f4":        {special: keyF4},
+	"f5":        {special: keyF5},
+	"f6":        {special: keyF6},
+	"f7":        {special: keyF7},
+	"f8":        {special: keyF8},
+	"f9":        KeyF9,
+	"f10":       KeyF10,
+	"f11":       {special: keyF11},
+	"f12":       {special: keyF12},
+}
 
-	var view *gocui.View
+// KeyRune returns the key typing the character ch
+func KeyRune(ch rune) Key {
+	return Key{ch: ch}
+}
 
-	if view, err = uim.gui.View(viewName); err != nil {
-		return err
+// ParseKey returns the key named name, the case of the names is ignored
+// A single printable character is the key typing it, the case matters
+func ParseKey(name string) (key Key, err error) {
+	if key, ok := keyNames[strings.ToLower(name)]; ok {
+		return key, nil
 	}
 
-	uim.gui.Update(
-		func(g *gocui.Gui) error {
-			return uim.writeLn(view, msg)
-		})
+	if ch, size := utf8.DecodeRuneInString(name); size == len(name) && ch != utf8.RuneError &&
+		unicode.IsPrint(ch) && !unicode.IsSpace(ch) {
+		return KeyRune(ch), nil
+	}
 
-	return nil
+	return key, fmt.Errorf("%w: %q", ErrUnknownKey, name)
 }
 
-func (uim *uiManager) displaySprites(view *gocui.View, spriteList []common.Sprite) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+func keyName(key Key) (name string, ok bool) {
+	if key.ch != 0 {
+		return string(key.ch), true
+	}
 
-	for i := range spriteList {
-		if err := view.SetCursor(spriteList[i].Position.X,
-			spriteList[i].Position.Y); err != nil {
-			return err
+	for name, aKey := range keyNames {
+		if aKey == key {
+			return name, true
 		}
-
-		view.EditWrite(spriteList[i].Value)
 	}
 
-	return nil
+	return name, false
 }
 
-// SetView adds the view to the display manager
-func (uim *uiManager) SetView(viewName string, position common.ViewPosition) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	if view, err := uim.gui.SetView(viewName, position.X1, position.Y1,
-		position.X2, position.Y2); err != nil {
-		if err != gocui.ErrUnknownView {
-			return err
-		}
-
-		view.Overwrite = true
-		view.Autoscroll = false
-		view.Wrap = true
+// String returns the name of the key
+func (key Key) String() string {
+	if name, ok := keyName(key); ok {
+		return name
 	}
 
-	return nil
+	return fmt.Sprintf("key %d", uint16(key.special))
 }
 
-// ClearView clears a view
-func (uim *uiManager) ClearView(viewName string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	view, err := uim.gui.View(viewName)
-	if err != nil {
-		return err
+// MarshalText allows keys to be written by name in the configuration
+func (key Key) MarshalText() ([]byte, error) {
+	name, ok := keyName(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKey, uint16(key.special))
 	}
 
-	view.Clear()
-
-	return nil
+	return []byte(name), nil
 }
 
-// DisplayRedLayout displays the view layout with red background
-func (uim *uiManager) DisplayRedLayout(viewName string, layout []string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	view, err := uim.gui.View(viewName)
-	if err != nil {
-		return err
-	}
-
-	view.Clear()
-	view.BgColor = gocui.ColorRed
+// UnmarshalText allows keys to be read by name from the configuration
+func (key *Key) UnmarshalText(text []byte) (err error) {
+	*key, err = ParseKey(string(text))
 
-	for i := range layout {
-		if err := view.SetCursor(0, i); err != nil {
-			return err
-		}
+	return err
+}
 
-		if err := writeLn(view, layout[i]); err != nil {
-			return err
-		}
+// DetectColorMode tells if the terminal supports 256 colors from its environment variables
+func DetectColorMode() ColorMode {
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Colors256
 	}
 
-	uim.gui.Update(func(g *gocui.Gui) error { return nil })
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return Colors256
+	}
 
-	return nil
+	return Colors8
 }
 
-// SetViewLayout defines the view layout
-func (uim *uiManager) SetViewLayout(viewName string, layout []string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	view, err := uim.gui.View(viewName)
-	if err != nil {
-		return err
+// String returns the name of the color mode
+func (colorMode ColorMode) String() string {
+	if colorMode < 0 || int(colorMode) >= len(colorModeNames) {
+		return fmt.Sprintf("color mode %d", int(colorMode))
 	}
 
-	view.Clear()
+	return colorModeNames[colorMode]
+}
 
-	for i := range layout {
-		if err := view.SetCursor(0, i); err != nil {
-			return err
-		}
+// MarshalText allows the color mode to be written by name in the configuration
+func (colorMode ColorMode) MarshalText() ([]byte, error) {
+	return []byte(colorMode.String()), nil
+}
 
-		if err := writeLn(view, layout[i]); err != nil {
-			return err
+// UnmarshalText allows the color mode to be read by name from the configuration
+func (colorMode *ColorMode) UnmarshalText(text []byte) error {
+	for i := range colorModeNames {
+		if colorModeNames[i] == strings.ToLower(string(text)) {
+			*colorMode = ColorMode(i)
+			return nil
 		}
 	}
 
-	return nil
+	return fmt.Errorf("%w: %q", ErrUnknownColorMode, text)
 }
 
-func writeLn(view *gocui.View, str string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	for i := range str {
-		view.EditWrite(rune(str[i]))
+// String returns the name of the board mode
+func (boardMode BoardMode) String() string {
+	if boardMode < 0 || int(boardMode) >= len(boardModeNames) {
+		return fmt.Sprintf("board mode %d", int(boardMode))
 	}
 
-	return nil
+	return boardModeNames[boardMode]
 }
 
-func (uim *uiManager) writeLn(view *gocui.View, str string) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	if err := view.SetCursor(0, 0); err != nil {
-		return err
-	}
-
-	for i := range str {
-		view.EditWrite(rune(str[i]))
-	}
-
-	return nil
+// MarshalText allows the board mode to be written by name in the configuration
+func (boardMode BoardMode) MarshalText() ([]byte, error) {
+	return []byte(boardMode.String()), nil
 }
 
-// OnKeyPress attaches all active keys to an eventHandler
-func (uim *uiManager) OnKeyPress(fn func(Key) error) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	for i := range ActiveKeys {
-		if err := uim.setKeybinding(gocui.Key(ActiveKeys[i]), fn); err != nil {
-			return err
+// UnmarshalText allows the board mode to be read by name from the configuration
+func (boardMode *BoardMode) UnmarshalText(text []byte) error {
+	for i := range boardModeNames {
+		if boardModeNames[i] == strings.ToLower(string(text)) {
+			*boardMode = BoardMode(i)
+			return nil
 		}
 	}
 
-	return nil
-}
-
-func (uim *uiManager) setKeybinding(key gocui.Key, fn func(Key) error) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	return uim.gui.SetKeybinding("", key, gocui.ModNone,
-		func(g *gocui.Gui, v *gocui.View) error {
-			return fn(Key(key))
-		})
+	return fmt.Errorf("%w: %q", ErrUnknownBoardMode, text)
 }
 
-// Quit stops the mainLoop
-func (uim *uiManager) Quit() (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+// NewBackend returns an instance of the user interface drawn by backend:
+// gocui, or ansi which writes escape sequences itself, for the terminals where termbox misbehaves
+func NewBackend(backend string) (userInterface UIManagerer, err error) {
+	switch strings.ToLower(backend) {
+	case BackendGocui:
+		return New(), nil
+	case BackendANSI:
+		return NewANSI(), nil
+	}
 
-	return ErrQuit
+	return nil, fmt.Errorf("%w: %q", ErrUnknownBackend, backend)
 }
@@ -0,0 +1,352 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxMessageSize is the size of the largest message read, the browser only sends keys
+const MaxMessageSize = 64 * 1024
+
+// acceptGUID is appended to the key of the handshake (RFC 6455)
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of the frames
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Defines custom errors
+var (
+	ErrBadHandshake    = errors.New("bad websocket handshake")
+	ErrBadOrigin       = errors.New("websocket origin not allowed")
+	ErrClosed          = errors.New("websocket closed")
+	ErrMessageTooLarge = errors.New("websocket message too large")
+	ErrProtocol        = errors.New("websocket protocol error")
+)
+
+// Conner is the interface for conn
+type Conner interface {
+	ReadMessage() (message []byte, err error)
+	WriteMessage(message []byte) (err error)
+	Close() (err error)
+}
+
+// conn is a websocket connection carrying text messages
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	mutex   sync.Mutex // The frames are written by several goroutines
+	masked  bool       // The client masks the frames it sends
+	closed  bool
+}
+
+// Upgrade switches the HTTP connection of the request to the websocket protocol
+func Upgrade(w http.ResponseWriter, r *http.Request) (aConn Conner, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	key := r.Header.Get("Sec-Websocket-Key")
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") || r.Header.Get("Sec-Websocket-Version") != "13" || key == "" {
+		http.Error(w, ErrBadHandshake.Error(), http.StatusBadRequest)
+		return nil, ErrBadHandshake
+	}
+
+	// The pages of the other sites can't drive the connection through the browser of the player
+	if !sameOrigin(r) {
+		http.Error(w, ErrBadOrigin.Error(), http.StatusForbidden)
+		return nil, fmt.Errorf("%w: %s", ErrBadOrigin, r.Header.Get("Origin"))
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, ErrBadHandshake.Error(), http.StatusInternalServerError)
+		return nil, fmt.Errorf("%w: the connection can't be hijacked", ErrBadHandshake)
+	}
+
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	// The connection outlives the request, it keeps none of the timeouts of the HTTP server
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+
+	if _, err := netConn.Write([]byte(response)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return &conn{netConn: netConn, reader: rw.Reader}, nil
+}
+
+// Dial opens a websocket connection to rawURL (ws://host/path), as a browser does
+func Dial(rawURL string) (aConn Conner, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	address, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	netConn, err := net.Dial("tcp", address.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	key := base64.StdEncoding.EncodeToString(nonce)
+	request := "GET " + address.RequestURI() + " HTTP/1.1\r\nHost: " + address.Host +
+		"\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: " + key +
+		"\r\nSec-WebSocket-Version: 13\r\n\r\n"
+
+	if _, err := netConn.Write([]byte(request)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(netConn)
+
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols ||
+		response.Header.Get("Sec-Websocket-Accept") != acceptKey(key) {
+		netConn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrBadHandshake, response.Status)
+	}
+
+	return &conn{netConn: netConn, reader: reader, masked: true}, nil
+}
+
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// sameOrigin tells whether the request comes from a page of the serving host, the clients other than browsers send no origin
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	address, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(address.Host, r.Host)
+}
+
+func headerContains(header http.Header, name, value string) bool {
+	for _, field := range strings.Split(header.Get(name), ",") {
+		if strings.EqualFold(strings.TrimSpace(field), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadMessage returns the next text or binary message, the pings are answered meanwhile
+func (aConn *conn) ReadMessage() (message []byte, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	for {
+		final, opcode, payload, err := aConn.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := aConn.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+
+			continue
+		case opPong:
+			continue
+		case opClose:
+			aConn.writeFrame(opClose, nil)
+			return nil, ErrClosed
+		case opContinuation:
+			if message == nil {
+				return nil, fmt.Errorf("%w: unexpected continuation frame", ErrProtocol)
+			}
+		case opText, opBinary:
+			if message != nil {
+				return nil, fmt.Errorf("%w: unfinished message", ErrProtocol)
+			}
+
+			message = []byte{}
+		default:
+			return nil, fmt.Errorf("%w: unknown opcode %d", ErrProtocol, opcode)
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return nil, ErrMessageTooLarge
+		}
+
+		message = append(message, payload...)
+
+		if final {
+			return message, nil
+		}
+	}
+}
+
+func (aConn *conn) readFrame() (final bool, opcode byte, payload []byte, err error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(aConn.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	final, opcode = header[0]&0x80 != 0, header[0]&0x0f
+	masked, length := header[1]&0x80 != 0, uint64(header[1]&0x7f)
+
+	// The frames sent by the clients are masked, not the ones sent by the servers
+	if masked == aConn.masked {
+		return false, 0, nil, fmt.Errorf("%w: wrong masking", ErrProtocol)
+	}
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(aConn.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(aConn.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(aConn.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(aConn.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return final, opcode, payload, nil
+}
+
+// WriteMessage sends a text message
+func (aConn *conn) WriteMessage(message []byte) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return aConn.writeFrame(opText, message)
+}
+
+func (aConn *conn) writeFrame(opcode byte, payload []byte) error {
+	aConn.mutex.Lock()
+	defer aConn.mutex.Unlock()
+
+	if aConn.closed {
+		return ErrClosed
+	}
+
+	frame := []byte{0x80 | opcode, 0}
+
+	switch {
+	case len(payload) < 126:
+		frame[1] = byte(len(payload))
+	case len(payload) <= 0xffff:
+		frame[1] = 126
+		frame = append(frame, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	default:
+		frame[1] = 127
+		frame = append(frame, make([]byte, 8)...)
+		binary.BigEndian.PutUint64(frame[2:], uint64(len(payload)))
+	}
+
+	if aConn.masked {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+
+		frame[1] |= 0x80
+		frame = append(frame, mask[:]...)
+
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+
+		payload = masked
+	}
+
+	if opcode == opClose {
+		aConn.closed = true
+	}
+
+	_, err := aConn.netConn.Write(append(frame, payload...))
+
+	return err
+}
+
+// Close sends a close frame then closes the connection
+func (aConn *conn) Close() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aConn.writeFrame(opClose, nil)
+
+	return aConn.netConn.Close()
+}
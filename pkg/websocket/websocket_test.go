@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// echoServer sends the messages it receives back in upper case
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aConn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer aConn.Close()
+
+		for {
+			message, err := aConn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if err := aConn.WriteMessage([]byte(strings.ToUpper(string(message)))); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestConn(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	aConn, err := Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/")
+	require.NoError(t, err)
+	defer aConn.Close()
+
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{
+			name:    "TestShort",
+			message: "up",
+		},
+		{
+			name:    "TestExtendedLength", // More than 125 bytes
+			message: strings.Repeat("snake ", 100),
+		},
+		{
+			name:    "TestUnicode",
+			message: "蛇🍎",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, aConn.WriteMessage([]byte(tt.message)))
+			message, err := aConn.ReadMessage()
+			require.NoError(t, err)
+			require.Equal(t, strings.ToUpper(tt.message), string(message))
+		})
+	}
+}
+
+func TestUpgradeBadHandshake(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	// A plain HTTP request isn't upgraded
+	response, err := http.Get(server.URL)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestUpgradeOrigin(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantStatus int
+	}{
+		{
+			name:       "TestNoOrigin",
+			origin:     "",
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "TestSameOrigin",
+			origin:     server.URL,
+			wantStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:       "TestOtherOrigin",
+			origin:     "http://example.com",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "TestOtherPort",
+			origin:     server.URL + "1",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			request.Header.Set("Connection", "Upgrade")
+			request.Header.Set("Upgrade", "websocket")
+			request.Header.Set("Sec-WebSocket-Version", "13")
+			request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+			require.Equal(t, tt.wantStatus, response.StatusCode)
+		})
+	}
+}
+
+func Test_acceptKey(t *testing.T) {
+	// The example of RFC 6455
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
@@ -0,0 +1,17 @@
+package webui
+
+import (
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/theme"
+)
+
+// cssColor returns the CSS color of a terminal color, empty for the default color which the page chooses
+func cssColor(color common.Color) string {
+	rgb, ok := theme.RGB(color)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B)
+}
@@ -0,0 +1,47 @@
+package webui
+
+import (
+	"gosnake/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cssColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		color common.Color
+		want  string
+	}{
+		{
+			name:  "TestDefault",
+			color: common.ColorDefault,
+			want:  "",
+		},
+		{
+			name:  "TestBasic",
+			color: common.ColorRed,
+			want:  "#cd0000",
+		},
+		{
+			name:  "TestBright",
+			color: common.Color256(15),
+			want:  "#ffffff",
+		},
+		{
+			name:  "TestCube", // Orange
+			color: common.Color256(214),
+			want:  "#ffaf00",
+		},
+		{
+			name:  "TestGrey",
+			color: common.Color256(244),
+			want:  "#808080",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, cssColor(tt.color))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// GoSnake browser client: draws the board sent by the server and sends back the keys pressed
+"use strict";
+
+const canvas = document.getElementById("board");
+const context = canvas.getContext("2d");
+const scoreView = document.getElementById("score");
+const messageView = document.getElementById("message");
+const helpView = document.getElementById("help");
+
+// Colors used when the theme keeps the default color of the terminal
+const defaultColors = { " ": "#000000", "S": "#00cd00", "*": "#cd0000" };
+
+// Names of the keys as written in the configuration
+const keyNames = {
+  ArrowUp: "up", ArrowDown: "down", ArrowLeft: "left", ArrowRight: "right",
+  " ": "space", Enter: "enter", Escape: "esc", Tab: "tab", PageUp: "pgup", PageDown: "pgdn",
+  Home: "home", End: "end", Insert: "insert", Delete: "delete", Backspace: "backspace",
+};
+
+const messages = {
+  ready: "SPACE to start",
+  playing: "",
+  paused: "PAUSED",
+  over: "GAME OVER",
+};
+
+let board = { width: 1, height: 1, colors: defaultColors, cell: 1 };
+
+function color(value) {
+  return board.colors[value] || defaultColors[value] || "#e5e5e5";
+}
+
+function drawSprite(sprite) {
+  context.fillStyle = color(" ");
+  context.fillRect(sprite.x * board.cell, sprite.y * board.cell, board.cell, board.cell);
+
+  if (sprite.value !== " ") {
+    context.fillStyle = color(sprite.value);
+    context.fillRect(sprite.x * board.cell + 1, sprite.y * board.cell + 1, board.cell - 2, board.cell - 2);
+  }
+}
+
+function resetBoard(msg) {
+  board = { width: msg.width, height: msg.height, colors: msg.colors, cell: Math.floor(600 / msg.width) };
+  canvas.width = board.cell * msg.width;
+  canvas.height = board.cell * msg.height;
+  context.fillStyle = color(" ");
+  context.fillRect(0, 0, canvas.width, canvas.height);
+
+  const keys = msg.keys;
+  helpView.textContent = `${keys.up} ${keys.down} ${keys.left} ${keys.right}: move, ` +
+    `${keys.start}: start, ${keys.pause}: pause`;
+  messages.ready = `${keys.start} to start`;
+}
+
+function receive(event) {
+  const msg = JSON.parse(event.data);
+
+  if (msg.type === "board") {
+    resetBoard(msg);
+  }
+
+  msg.sprites.forEach(drawSprite);
+
+  scoreView.textContent = `Score: ${msg.score}  Top: ${msg.highScore}  Round: ${msg.round}`;
+  messageView.textContent = msg.type === "error" ? `ERROR: ${msg.error}` : messages[msg.status];
+}
+
+const socket = new WebSocket(`${location.protocol === "https:" ? "wss" : "ws"}://${location.host}/play`);
+socket.onmessage = receive;
+socket.onclose = () => { messageView.textContent = "Disconnected, reload the page to play again"; };
+
+document.addEventListener("keydown", (event) => {
+  const key = keyNames[event.key] || event.key;
+  if (socket.readyState !== WebSocket.OPEN || event.ctrlKey || event.altKey || event.metaKey) {
+    return;
+  }
+
+  // The arrows and the space bar don't scroll the page
+  if (keyNames[event.key]) {
+    event.preventDefault();
+  }
+
+  socket.send(JSON.stringify({ key: key }));
+});
@@ -0,0 +1,403 @@
+package webui
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosnake/pkg/common"
+	"gosnake/pkg/config"
+	"gosnake/pkg/engine"
+	"gosnake/pkg/errorlog"
+	"gosnake/pkg/gameboard"
+	"gosnake/pkg/gamestate"
+	"gosnake/pkg/keybindings"
+	"gosnake/pkg/theme"
+	"gosnake/pkg/uimanager"
+	"gosnake/pkg/websocket"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+// DefaultAddress only accepts the browsers of the local machine
+const DefaultAddress = "localhost:8080"
+
+// The page and the script are embedded, the game is played offline
+//
+//go:embed static
+var static embed.FS
+
+// Types of the messages sent to the browser
+const (
+	messageBoard   = "board"   // Redraws the whole board
+	messageSprites = "sprites" // Updates the cells which changed
+	messageError   = "error"   // The round failed, the game is over
+)
+
+// Status of the game shown by the browser
+const (
+	statusReady   = "ready"
+	statusPlaying = "playing"
+	statusPaused  = "paused"
+	statusOver    = "over"
+)
+
+// The timeouts of the requests, the games are played on the connections taken over by the websockets
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 10 * time.Second
+)
+
+// ErrInvalidMessage is returned when the browser sends something else than a key
+var ErrInvalidMessage = errors.New("invalid message")
+
+// Serverer is the interface for server
+type Serverer interface {
+	Handler() http.Handler
+	ListenAndServe(address string) (err error)
+}
+
+// server plays a game with each browser connected
+type server struct {
+	cfg      config.Config
+	bindings keybindings.Bindings
+	colors   map[string]string // The CSS colors of the cells, by value
+	errLog   errorlog.ErrorLogger
+}
+
+// session is the game of a browser, the keys are played by the loop of play and the rounds by the engine
+type session struct {
+	server    *server
+	conn      websocket.Conner
+	gameState gamestate.GameStater
+	boardSize common.Size
+	playing   bool // The engine runs the game, it sends the end of the game to over
+	over      chan error
+	done      chan struct{} // Closed once the browser disconnected
+}
+
+// message is sent to the browser as JSON
+type message struct {
+	Type      string            `json:"type"`
+	Width     int               `json:"width,omitempty"`
+	Height    int               `json:"height,omitempty"`
+	Colors    map[string]string `json:"colors,omitempty"`
+	Keys      map[string]string `json:"keys,omitempty"`
+	Sprites   []sprite          `json:"sprites"`
+	Score     int               `json:"score"`
+	HighScore int               `json:"highScore"`
+	Round     int               `json:"round"`
+	Status    string            `json:"status"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// sprite is a cell of the board
+type sprite struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Value string `json:"value"`
+}
+
+// keyMessage is sent by the browser, the key is named as in the configuration
+type keyMessage struct {
+	Key string `json:"key"`
+}
+
+// New returns an instance of server playing with the settings of cfg
+func New(cfg config.Config, errLog errorlog.ErrorLogger) (aServer Serverer, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	bindings, err := cfg.Controls.Table()
+	if err != nil {
+		return nil, err
+	}
+
+	aTheme, err := theme.Get(cfg.Display.Theme)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := map[string]string{}
+	for value, style := range aTheme.Cells() {
+		colors[string(value)] = cssColor(style.Fg)
+	}
+
+	colors[string(gameboard.FreeSpace)] = cssColor(aTheme.Board.Bg)
+
+	return &server{cfg: cfg, bindings: bindings, colors: colors, errLog: errLog}, nil
+}
+
+// Handler serves the page and the game socket
+func (aServer *server) Handler() http.Handler {
+	files, _ := fs.Sub(static, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(files)))
+	mux.HandleFunc("/play", aServer.serveGame)
+
+	return mux
+}
+
+// ListenAndServe serves the browsers until the listener fails
+func (aServer *server) ListenAndServe(address string) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           aServer.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+	}
+
+	return httpServer.ListenAndServe()
+}
+
+func (aServer *server) serveGame(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		aServer.errLog.LogError("web session", err)
+		return
+	}
+	defer conn.Close()
+
+	aServer.errLog.LogMessage(errorlog.LevelInfo, "web session", "browser connected from "+r.RemoteAddr)
+
+	if err := aServer.play(conn); err != nil && !errors.Is(err, websocket.ErrClosed) {
+		aServer.errLog.LogError("web session", err)
+	}
+}
+
+// play runs the games of one browser until it disconnects
+func (aServer *server) play(conn websocket.Conner) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSession := &session{
+		server:    aServer,
+		conn:      conn,
+		gameState: gamestate.New(),
+		boardSize: common.Size{Width: aServer.cfg.Board.DefaultSize, Height: aServer.cfg.Board.DefaultSize},
+		over:      make(chan error),
+		done:      make(chan struct{}),
+	}
+	defer close(aSession.done)
+
+	if err := aSession.newBoard(); err != nil {
+		return err
+	}
+
+	keys, readErr := make(chan uimanager.Key), make(chan error, 1)
+
+	go readKeys(conn, keys, readErr, aSession.done)
+
+	for {
+		select {
+		case err := <-readErr:
+			return err
+		case key := <-keys:
+			if err := aSession.handleKey(key); err != nil {
+				return err
+			}
+		case err := <-aSession.over:
+			aSession.playing = false
+
+			if err := aSession.gameOver(err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readKeys forwards the keys sent by the browser until the connection fails
+func readKeys(conn websocket.Conner, keys chan<- uimanager.Key, readErr chan<- error, done <-chan struct{}) {
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			readErr <- err
+			return
+		}
+
+		key, err := parseKey(data)
+		if errors.Is(err, uimanager.ErrUnknownKey) {
+			// The browser names the keys it doesn't translate as it likes, they are ignored
+			continue
+		}
+
+		if err != nil {
+			readErr <- err
+			return
+		}
+
+		select {
+		case keys <- key:
+		case <-done:
+			return
+		}
+	}
+}
+
+func parseKey(data []byte) (key uimanager.Key, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var aKeyMessage keyMessage
+	if err := json.Unmarshal(data, &aKeyMessage); err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	return uimanager.ParseKey(aKeyMessage.Key)
+}
+
+// handleKey plays the action of key as the terminal does
+func (aSession *session) handleKey(key uimanager.Key) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	// A new game waits for the engine of the last one to stop
+	action := aSession.server.bindings.Action(key)
+	if action == keybindings.ActionStart && aSession.playing {
+		return nil
+	}
+
+	_, err = engine.HandleAction(aSession.gameState, action, engine.Frontend{
+		ShowPause: func(bool) error {
+			return aSession.send(message{Type: messageSprites})
+		},
+		NewBoard:  aSession.newBoard,
+		StartGame: aSession.start,
+	})
+
+	return err
+}
+
+// start starts the game and runs it in the engine until it is over or the browser disconnects
+func (aSession *session) start() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSession.gameState.Start()
+
+	// The browser learns the game started before its first round
+	if err := aSession.send(message{Type: messageSprites}); err != nil {
+		return err
+	}
+
+	aSession.playing = true
+
+	go func() {
+		err := engine.Run(aSession.gameState, aSession.server.cfg.Timing.RefreshInterval, aSession.done,
+			engine.Frontend{
+				DrawRound: func(spriteList []common.Sprite) error {
+					return aSession.send(message{Type: messageSprites, Sprites: sprites(spriteList)})
+				},
+			})
+
+		select {
+		case aSession.over <- err:
+		case <-aSession.done:
+		}
+	}()
+
+	return nil
+}
+
+// gameOver reports the error of the engine, a failed round ends the game and the browser can start a new one
+func (aSession *session) gameOver(engineErr error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if engineErr == nil {
+		return nil
+	}
+
+	aSession.gameState.SetGameInProgress(false)
+
+	aSession.server.errLog.LogError("web game engine", engineErr)
+
+	return aSession.send(message{Type: messageError, Error: engineErr.Error()})
+}
+
+// newBoard creates the board and the players then sends them
+func (aSession *session) newBoard() (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err := aSession.gameState.InitBoard(aSession.boardSize); err != nil {
+		return err
+	}
+
+	spriteList, err := aSession.gameState.CreateObjects()
+	if err != nil {
+		return err
+	}
+
+	return aSession.send(message{
+		Type:    messageBoard,
+		Width:   aSession.boardSize.Width,
+		Height:  aSession.boardSize.Height,
+		Colors:  aSession.server.colors,
+		Keys:    aSession.server.keyLabels(),
+		Sprites: sprites(spriteList),
+	})
+}
+
+// send sends the message completed with the status of the game
+func (aSession *session) send(aMessage message) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return send(aSession.conn, status(aSession.gameState, aMessage))
+}
+
+// keyLabels returns the keys of the actions available in the browser
+func (aServer *server) keyLabels() map[string]string {
+	labels := map[string]string{}
+
+	for _, action := range []keybindings.Action{keybindings.ActionUp, keybindings.ActionDown,
+		keybindings.ActionLeft, keybindings.ActionRight, keybindings.ActionStart, keybindings.ActionPause} {
+		labels[action.String()] = aServer.bindings.Label(action)
+	}
+
+	return labels
+}
+
+// status completes the message with the score and the status of the game
+func status(gameState gamestate.GameStater, aMessage message) message {
+	aMessage.Score, aMessage.HighScore, aMessage.Round = gameState.Score(), gameState.HighScore(), gameState.Round()
+
+	switch {
+	case gameState.GameInProgress() && gameState.Paused():
+		aMessage.Status = statusPaused
+	case gameState.GameInProgress():
+		aMessage.Status = statusPlaying
+	case gameState.Dirty():
+		aMessage.Status = statusOver
+	default:
+		aMessage.Status = statusReady
+	}
+
+	return aMessage
+}
+
+func send(conn websocket.Conner, aMessage message) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aMessage.Sprites == nil {
+		aMessage.Sprites = []sprite{}
+	}
+
+	data, err := json.Marshal(aMessage)
+	if err != nil {
+		return err
+	}
+
+	return conn.WriteMessage(data)
+}
+
+func sprites(spriteList []common.Sprite) []sprite {
+	list := make([]sprite, 0, len(spriteList))
+
+	for i := range spriteList {
+		list = append(list, sprite{
+			X:     spriteList[i].Position.X,
+			Y:     spriteList[i].Position.Y,
+			Value: string(spriteList[i].Value),
+		})
+	}
+
+	return list
+}
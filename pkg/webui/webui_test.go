@@ -0,0 +1,134 @@
+package webui
+
+import (
+	"encoding/json"
+	"gosnake/pkg/config"
+	"gosnake/pkg/errorlog"
+	"gosnake/pkg/uimanager"
+	"gosnake/pkg/websocket"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	cfg := config.Default()
+	cfg.Timing.RefreshInterval = 10 * time.Millisecond
+	cfg.Board.DefaultSize = 20
+
+	aServer, err := New(cfg, errorlog.New())
+	require.NoError(t, err)
+
+	return httptest.NewServer(aServer.Handler())
+}
+
+func receive(t *testing.T, conn websocket.Conner) (aMessage message) {
+	data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &aMessage))
+
+	return aMessage
+}
+
+func TestServePage(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "TestPage",
+			path: "/",
+			want: "<canvas",
+		},
+		{
+			name: "TestScript",
+			path: "/snake.js",
+			want: "WebSocket",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := http.Get(server.URL + tt.path)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, response.StatusCode)
+			require.Contains(t, string(body), tt.want)
+		})
+	}
+}
+
+func TestPlay(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws" + strings.TrimPrefix(server.URL, "http") + "/play")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The board comes first, with the snake and the candy
+	aMessage := receive(t, conn)
+	require.Equal(t, messageBoard, aMessage.Type)
+	require.Equal(t, 20, aMessage.Width)
+	require.Equal(t, statusReady, aMessage.Status)
+	require.Len(t, aMessage.Sprites, 2)
+	require.Equal(t, "SPACE", aMessage.Keys["start"])
+
+	// Unknown keys are ignored
+	require.NoError(t, conn.WriteMessage([]byte(`{"key":"ctrl+alt+nothing"}`)))
+	require.NoError(t, conn.WriteMessage([]byte(`{"key":"space"}`)))
+	require.Equal(t, statusPlaying, receive(t, conn).Status)
+
+	// The rounds are played and the snake moves
+	aMessage = receive(t, conn)
+	require.Equal(t, messageSprites, aMessage.Type)
+	require.NotEmpty(t, aMessage.Sprites)
+
+	require.NoError(t, conn.WriteMessage([]byte(`{"key":"p"}`)))
+
+	for aMessage.Status == statusPlaying {
+		aMessage = receive(t, conn)
+	}
+
+	require.Equal(t, statusPaused, aMessage.Status)
+}
+
+func Test_parseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{
+			name: "TestKey",
+			data: `{"key":"up"}`,
+		},
+		{
+			name:    "TestUnknownKey",
+			data:    `{"key":"Shift"}`,
+			wantErr: uimanager.ErrUnknownKey,
+		},
+		{
+			name:    "TestInvalidMessage",
+			data:    `up`,
+			wantErr: ErrInvalidMessage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseKey([]byte(tt.data))
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}